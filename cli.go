@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"restServer/config"
+	"restServer/taskio"
+	"restServer/taskstore"
+	"strings"
+)
+
+// Subcomando export: escribe las tareas del archivo de datos en json, ndjson o csv
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	configFile := fs.String("config", os.Getenv(config.EnvConfig), "archivo de configuracion, su storage.dataFile es el -data por defecto")
+	dataFile := fs.String("data", "", "archivo de datos del store (requerido si no esta configurado)")
+	format := fs.String("format", taskio.FormatJSON, "formato de salida: json, ndjson o csv")
+	out := fs.String("out", "-", "archivo de salida, - para stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *dataFile == "" {
+		cfg, err := config.FromFile(*configFile)
+		if err != nil {
+			return err
+		}
+		*dataFile = cfg.Storage.DataFile
+	}
+	if *dataFile == "" {
+		return errors.New("export: -data is required")
+	}
+
+	store, err := taskstore.LoadFile(*dataFile)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "-" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	return taskio.Export(context.Background(), store, w, *format, taskio.StoreLayout)
+}
+
+// Subcomando import: valida e importa tareas en el archivo de datos e imprime el informe
+func runImport(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	configFile := fs.String("config", os.Getenv(config.EnvConfig), "archivo de configuracion, su storage.dataFile es el -data por defecto")
+	dataFile := fs.String("data", "", "archivo de datos del store (requerido si no esta configurado, se crea si no existe)")
+	in := fs.String("in", "-", "archivo a importar, - para stdin")
+	format := fs.String("format", "", "formato de entrada: json, ndjson o csv (por defecto segun la extension)")
+	mode := fs.String("mode", "reassign", "reassign asigna Ids nuevos, preserve conserva los Ids")
+	dryRun := fs.Bool("dry-run", false, "solo validar, sin modificar el archivo de datos")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *dataFile == "" {
+		cfg, err := config.FromFile(*configFile)
+		if err != nil {
+			return err
+		}
+		*dataFile = cfg.Storage.DataFile
+	}
+	if *dataFile == "" {
+		return errors.New("import: -data is required")
+	}
+	if *mode != "reassign" && *mode != "preserve" {
+		return errors.New("import: -mode must be preserve or reassign")
+	}
+	if *format == "" {
+		*format = strings.TrimPrefix(filepath.Ext(*in), ".")
+	}
+
+	store, err := taskstore.LoadFile(*dataFile)
+	if err != nil {
+		return err
+	}
+
+	var r io.Reader = os.Stdin
+	if *in != "-" {
+		f, err := os.Open(*in)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	report, importErr := taskio.Import(context.Background(), store, r, *format, taskio.StoreLayout, *mode == "preserve", *dryRun)
+	if importErr != nil && !errors.Is(importErr, taskstore.ErrImportInvalid) {
+		return importErr
+	}
+
+	js, _ := json.MarshalIndent(report, "", "  ")
+	fmt.Println(string(js))
+	if importErr != nil {
+		return importErr
+	}
+	if *dryRun {
+		return nil
+	}
+	return store.SaveFile(*dataFile)
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"restServer/taskstore"
+	"strings"
+	"testing"
+)
+
+func TestImportExportCommands(t *testing.T) {
+	dir := t.TempDir()
+	data := filepath.Join(dir, "data.json")
+	in := filepath.Join(dir, "tasks.csv")
+	out := filepath.Join(dir, "out.ndjson")
+	csv := "id,text,tags,due,project,attachments\n7,from csv,a;b,2026-01-02T15:04:05Z,,\n"
+	if err := os.WriteFile(in, []byte(csv), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Solo validar no crea el archivo de datos
+	if err := runImport([]string{"-data", data, "-in", in, "-dry-run"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(data); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("dry run wrote the data file: %v", err)
+	}
+
+	if err := runImport([]string{"-data", data, "-in", in, "-mode", "preserve"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := runExport([]string{"-data", data, "-format", "ndjson", "-out", out}); err != nil {
+		t.Fatal(err)
+	}
+	exported, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(exported), `"ID":"7","Text":"from csv","Tags":["a","b"]`) {
+		t.Errorf("export = %s", exported)
+	}
+
+	// Un archivo invalido no modifica los datos
+	if err := os.WriteFile(in, []byte("id,text,tags,due,project,attachments\n,,,,,\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := runImport([]string{"-data", data, "-in", in}); !errors.Is(err, taskstore.ErrImportInvalid) {
+		t.Errorf("invalid import error = %v", err)
+	}
+	store, err := taskstore.LoadFile(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.GetTask(t.Context(), "7"); err != nil {
+		t.Errorf("imported task: %v", err)
+	}
+}
+
+// Los errores de los flags se devuelven en lugar de terminar el proceso
+func TestCommandFlagErrors(t *testing.T) {
+	data := filepath.Join(t.TempDir(), "data.json")
+	tests := []struct {
+		name string
+		run  func([]string) error
+		args []string
+		want string
+	}{
+		{"export unknown flag", runExport, []string{"-bogus"}, "flag provided but not defined"},
+		{"import unknown flag", runImport, []string{"-bogus"}, "flag provided but not defined"},
+		{"import bad bool", runImport, []string{"-dry-run=maybe"}, "invalid boolean value"},
+		{"import bad mode", runImport, []string{"-data", data, "-mode", "merge"}, "-mode must be preserve or reassign"},
+		{"export unknown format", runExport, []string{"-data", data, "-format", "xml"}, "format must be json, ndjson or csv"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.run(tt.args)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+
+	if err := runExport([]string{"-h"}); !errors.Is(err, flag.ErrHelp) {
+		t.Errorf("-h error = %v, want %v", err, flag.ErrHelp)
+	}
+}
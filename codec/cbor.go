@@ -0,0 +1,254 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// CBOR (RFC 8949). Se escribe la forma determinista: longitudes minimas, sin
+// longitud indefinida y claves de mapa ordenadas. Al leer se ignoran las etiquetas
+// (tag) y no se aceptan elementos de longitud indefinida.
+
+const (
+	cborUint   = 0
+	cborNegInt = 1
+	cborBytes  = 2
+	cborText   = 3
+	cborArray  = 4
+	cborMap    = 5
+	cborTag    = 6
+	cborSimple = 7
+)
+
+var errIndefinite = errors.New("indefinite-length items are not supported")
+
+func encodeCBOR(buf *bytes.Buffer, value any) error {
+	switch v := value.(type) {
+	case nil:
+		buf.WriteByte(0xf6)
+	case bool:
+		if v {
+			buf.WriteByte(0xf5)
+		} else {
+			buf.WriteByte(0xf4)
+		}
+	case json.Number:
+		i, f, isInt, err := number(v)
+		if err != nil {
+			return err
+		}
+		switch {
+		case !isInt:
+			buf.WriteByte(0xfb)
+			buf.Write(binary.BigEndian.AppendUint64(nil, math.Float64bits(f)))
+		case i >= 0:
+			cborHead(buf, cborUint, uint64(i))
+		default:
+			cborHead(buf, cborNegInt, uint64(-1-i))
+		}
+	case string:
+		cborHead(buf, cborText, uint64(len(v)))
+		buf.WriteString(v)
+	case []any:
+		cborHead(buf, cborArray, uint64(len(v)))
+		for _, item := range v {
+			if err := encodeCBOR(buf, item); err != nil {
+				return err
+			}
+		}
+	case map[string]any:
+		cborHead(buf, cborMap, uint64(len(v)))
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		// Orden de las claves codificadas: primero las mas cortas
+		sort.Slice(keys, func(i, j int) bool {
+			if len(keys[i]) != len(keys[j]) {
+				return len(keys[i]) < len(keys[j])
+			}
+			return keys[i] < keys[j]
+		})
+		for _, key := range keys {
+			cborHead(buf, cborText, uint64(len(key)))
+			buf.WriteString(key)
+			if err := encodeCBOR(buf, v[key]); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("cbor: unsupported type %T", value)
+	}
+	return nil
+}
+
+func cborHead(buf *bytes.Buffer, major byte, n uint64) {
+	major <<= 5
+	switch {
+	case n < 24:
+		buf.WriteByte(major | byte(n))
+	case n <= math.MaxUint8:
+		buf.Write([]byte{major | 24, byte(n)})
+	case n <= math.MaxUint16:
+		buf.WriteByte(major | 25)
+		buf.Write(binary.BigEndian.AppendUint16(nil, uint16(n)))
+	case n <= math.MaxUint32:
+		buf.WriteByte(major | 26)
+		buf.Write(binary.BigEndian.AppendUint32(nil, uint32(n)))
+	default:
+		buf.WriteByte(major | 27)
+		buf.Write(binary.BigEndian.AppendUint64(nil, n))
+	}
+}
+
+// Lee un valor y devuelve el resto de la entrada. Los mapas solo pueden tener claves de texto.
+func decodeCBOR(data []byte, depth int) (any, []byte, error) {
+	if depth > maxDepth {
+		return nil, nil, errTooDeep
+	}
+	if len(data) == 0 {
+		return nil, nil, errTruncated
+	}
+	major, info := data[0]>>5, data[0]&0x1f
+	data = data[1:]
+
+	if major == cborSimple {
+		return cborSimpleValue(data, info)
+	}
+	if info == 31 {
+		return nil, nil, errIndefinite
+	}
+	n, data, err := cborArgument(data, info)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch major {
+	case cborUint:
+		return n, data, nil
+	case cborNegInt:
+		if n > math.MaxInt64 {
+			// -1-n no entra en int64, se pierde precision como en JSON
+			return -1 - float64(n), data, nil
+		}
+		return -1 - int64(n), data, nil
+	case cborBytes, cborText:
+		if uint64(len(data)) < n {
+			return nil, nil, errTruncated
+		}
+		if major == cborText {
+			return string(data[:n]), data[n:], nil
+		}
+		return bytes.Clone(data[:n]), data[n:], nil
+	case cborArray:
+		// Cada elemento ocupa al menos un byte
+		if uint64(len(data)) < n {
+			return nil, nil, errTruncated
+		}
+		items := make([]any, 0, n)
+		for range n {
+			var item any
+			item, data, err = decodeCBOR(data, depth+1)
+			if err != nil {
+				return nil, nil, err
+			}
+			items = append(items, item)
+		}
+		return items, data, nil
+	case cborMap:
+		if uint64(len(data))/2 < n {
+			return nil, nil, errTruncated
+		}
+		obj := make(map[string]any, n)
+		for range n {
+			var key any
+			key, data, err = decodeCBOR(data, depth+1)
+			if err != nil {
+				return nil, nil, err
+			}
+			name, ok := key.(string)
+			if !ok {
+				return nil, nil, fmt.Errorf("map key must be a text string, got %T", key)
+			}
+			obj[name], data, err = decodeCBOR(data, depth+1)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+		return obj, data, nil
+	default: // cborTag: se usa el valor etiquetado
+		return decodeCBOR(data, depth+1)
+	}
+}
+
+// Argumento de la cabecera: el propio info si es < 24, si no 1, 2, 4 u 8 bytes
+func cborArgument(data []byte, info byte) (uint64, []byte, error) {
+	if info < 24 {
+		return uint64(info), data, nil
+	}
+	if info > 27 {
+		return 0, nil, fmt.Errorf("invalid additional information %d", info)
+	}
+	size := 1 << (info - 24)
+	if len(data) < size {
+		return 0, nil, errTruncated
+	}
+	return beUint(data[:size]), data[size:], nil
+}
+
+func cborSimpleValue(data []byte, info byte) (any, []byte, error) {
+	switch info {
+	case 20:
+		return false, data, nil
+	case 21:
+		return true, data, nil
+	case 22, 23: // null, undefined
+		return nil, data, nil
+	case 25:
+		if len(data) < 2 {
+			return nil, nil, errTruncated
+		}
+		return halfFloat(binary.BigEndian.Uint16(data)), data[2:], nil
+	case 26:
+		if len(data) < 4 {
+			return nil, nil, errTruncated
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(data))), data[4:], nil
+	case 27:
+		if len(data) < 8 {
+			return nil, nil, errTruncated
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(data)), data[8:], nil
+	case 31:
+		return nil, nil, errIndefinite
+	}
+	return nil, nil, fmt.Errorf("unsupported simple value %d", info)
+}
+
+// float16 (IEEE 754 half precision) a float64
+func halfFloat(h uint16) float64 {
+	exp := int(h>>10) & 0x1f
+	mant := float64(h & 0x3ff)
+	var f float64
+	switch exp {
+	case 0:
+		f = math.Ldexp(mant, -24)
+	case 31:
+		if mant == 0 {
+			f = math.Inf(1)
+		} else {
+			f = math.NaN()
+		}
+	default:
+		f = math.Ldexp(mant+1024, exp-25)
+	}
+	if h&0x8000 != 0 {
+		f = -f
+	}
+	return f
+}
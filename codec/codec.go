@@ -0,0 +1,87 @@
+// Formatos binarios de la API REST (MessagePack y CBOR) sobre el mismo modelo de
+// datos que JSON: los valores se pasan por JSON, asi los nombres de campo, omitempty
+// y las fechas (texto RFC 3339) son iguales en los tres formatos.
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+const (
+	MsgPack = "application/msgpack"
+	CBOR    = "application/cbor"
+)
+
+// Profundidad maxima de anidamiento al leer, evita agotar la pila con entradas hostiles
+const maxDepth = 512
+
+var (
+	ErrUnknownFormat = errors.New("codec: unknown format")
+	errTruncated     = errors.New("unexpected end of input")
+	errTooDeep       = errors.New("maximum nesting depth exceeded")
+)
+
+// Codifica v en el formato indicado (MsgPack o CBOR)
+func Marshal(format string, v any) ([]byte, error) {
+	js, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(js))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	switch format {
+	case MsgPack:
+		err = encodeMsgPack(&buf, value)
+	case CBOR:
+		err = encodeCBOR(&buf, value)
+	default:
+		return nil, ErrUnknownFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Convierte un documento MsgPack o CBOR a JSON, para decodificarlo con las mismas
+// reglas que un cuerpo JSON. Los bytes binarios quedan en base64 como en encoding/json.
+func ToJSON(format string, data []byte) ([]byte, error) {
+	var (
+		value any
+		rest  []byte
+		err   error
+	)
+	switch format {
+	case MsgPack:
+		value, rest, err = decodeMsgPack(data, 0)
+	case CBOR:
+		value, rest, err = decodeCBOR(data, 0)
+	default:
+		return nil, ErrUnknownFormat
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", format, err)
+	}
+	if len(rest) > 0 {
+		return nil, fmt.Errorf("%s: %d trailing bytes after value", format, len(rest))
+	}
+	return json.Marshal(value)
+}
+
+// Numero JSON como entero si lo es, si no como float64
+func number(n json.Number) (int64, float64, bool, error) {
+	if i, err := n.Int64(); err == nil {
+		return i, 0, true, nil
+	}
+	f, err := n.Float64()
+	return 0, f, false, err
+}
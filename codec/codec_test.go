@@ -0,0 +1,164 @@
+package codec
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Todos los valores deben volver como el JSON del que salieron
+func TestRoundTrip(t *testing.T) {
+	type task struct {
+		ID    string    `json:"id"`
+		Tags  []string  `json:"tags,omitempty"`
+		Due   time.Time `json:"due"`
+		Count int       `json:"count"`
+	}
+
+	tests := []struct {
+		name  string
+		value any
+	}{
+		{"null", nil},
+		{"bool", []bool{true, false}},
+		{"small ints", []int{0, 1, 23, 24, 127, 128, -1, -24, -25, -32, -33}},
+		{"ints", []int64{255, 256, 65535, 65536, 1 << 32, -128, -129, -32768, -32769, -1 << 31, -1<<31 - 1, 1<<63 - 1, -1 << 63}},
+		{"floats", []float64{0.5, -1.25, 3.14159, 1e300}},
+		{"strings", []string{"", "a", strings.Repeat("x", 23), strings.Repeat("x", 31), strings.Repeat("x", 32), strings.Repeat("x", 256), strings.Repeat("x", 70000), "ñandú"}},
+		{"long array", make([]int, 70000)},
+		{"map", map[string]any{"b": 1, "aa": []any{"x", nil}, "a": map[string]any{}}},
+		{"struct", task{ID: "1", Tags: []string{"go"}, Due: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), Count: 3}},
+		{"omitempty", task{ID: "2"}},
+	}
+	for _, format := range []string{MsgPack, CBOR} {
+		for _, tt := range tests {
+			t.Run(format+"/"+tt.name, func(t *testing.T) {
+				data, err := Marshal(format, tt.value)
+				if err != nil {
+					t.Fatal(err)
+				}
+				js, err := ToJSON(format, data)
+				if err != nil {
+					t.Fatal(err)
+				}
+				want, _ := json.Marshal(tt.value)
+				if !equalJSON(t, js, want) {
+					t.Errorf("ToJSON(Marshal()) = %s, want %s", js, want)
+				}
+			})
+		}
+	}
+}
+
+func equalJSON(t *testing.T, a, b []byte) bool {
+	t.Helper()
+	var va, vb any
+	if err := json.Unmarshal(a, &va); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(b, &vb); err != nil {
+		t.Fatal(err)
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+// Codificaciones exactas: la forma mas corta y las claves ordenadas
+func TestMarshal(t *testing.T) {
+	tests := []struct {
+		format string
+		value  any
+		want   string // hex
+	}{
+		{MsgPack, map[string]int{"b": 1, "a": 2}, "82a16102a16201"},
+		{MsgPack, 500, "cd01f4"},
+		{MsgPack, -33, "d0df"},
+		{MsgPack, 1.5, "cb3ff8000000000000"},
+		{MsgPack, "", "a0"},
+		{MsgPack, []any{nil, true}, "92c0c3"},
+		{CBOR, map[string]int{"bb": 1, "a": 2}, "a261610262626201"},
+		{CBOR, 500, "1901f4"},
+		{CBOR, -1, "20"},
+		{CBOR, -500, "3901f3"},
+		{CBOR, 1.5, "fb3ff8000000000000"},
+		{CBOR, []any{nil, false}, "82f6f4"},
+		{CBOR, "a", "6161"},
+	}
+	for _, tt := range tests {
+		got, err := Marshal(tt.format, tt.value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(got) != tt.want {
+			t.Errorf("Marshal(%s, %v) = %x, want %s", tt.format, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestToJSON(t *testing.T) {
+	deep := func(open, last string) string {
+		return strings.Repeat(open, maxDepth+1) + last
+	}
+
+	tests := []struct {
+		name    string
+		format  string
+		data    string // hex
+		want    string
+		wantErr bool
+	}{
+		{"msgpack bin is base64", MsgPack, "c403010203", `"AQID"`, false},
+		{"msgpack float32", MsgPack, "ca3fc00000", `1.5`, false},
+		{"msgpack uint64", MsgPack, "cfffffffffffffffff", `18446744073709551615`, false},
+		{"msgpack str8", MsgPack, "d90161", `"a"`, false},
+		{"msgpack map16", MsgPack, "de0001a16101", `{"a":1}`, false},
+		{"msgpack truncated", MsgPack, "a261", "", true},
+		{"msgpack truncated map", MsgPack, "82a16101", "", true},
+		{"msgpack trailing bytes", MsgPack, "0101", "", true},
+		{"msgpack non-string key", MsgPack, "810101", "", true},
+		{"msgpack unsupported code", MsgPack, "c1", "", true},
+		{"msgpack too deep", MsgPack, deep("91", "c0"), "", true},
+		{"msgpack empty", MsgPack, "", "", true},
+		{"cbor half float", CBOR, "f93e00", `1.5`, false},
+		{"cbor float32", CBOR, "fa3fc00000", `1.5`, false},
+		{"cbor bytes are base64", CBOR, "43010203", `"AQID"`, false},
+		{"cbor tag is ignored", CBOR, "c11a514b67b0", `1363896240`, false},
+		{"cbor undefined is null", CBOR, "f7", `null`, false},
+		{"cbor non-minimal length", CBOR, "780161", `"a"`, false},
+		{"cbor indefinite array", CBOR, "9f01ff", "", true},
+		{"cbor indefinite string", CBOR, "7f6161ff", "", true},
+		{"cbor truncated", CBOR, "6261", "", true},
+		{"cbor huge length", CBOR, "9b00000000ffffffff", "", true},
+		{"cbor non-text key", CBOR, "a10101", "", true},
+		{"cbor trailing bytes", CBOR, "0101", "", true},
+		{"cbor too deep", CBOR, deep("81", "f6"), "", true},
+		{"cbor reserved info", CBOR, "1c", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := hex.DecodeString(tt.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := ToJSON(tt.format, data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ToJSON() error = %v, want error %v", err, tt.wantErr)
+			}
+			if err == nil && string(got) != tt.want {
+				t.Errorf("ToJSON() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnknownFormat(t *testing.T) {
+	if _, err := Marshal("application/xml", 1); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("Marshal() error = %v, want %v", err, ErrUnknownFormat)
+	}
+	if _, err := ToJSON("application/xml", []byte{0}); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("ToJSON() error = %v, want %v", err, ErrUnknownFormat)
+	}
+}
@@ -0,0 +1,262 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// MessagePack (https://github.com/msgpack/msgpack/blob/master/spec.md). Se escribe
+// la forma mas corta de cada valor y los mapas con las claves ordenadas.
+
+func encodeMsgPack(buf *bytes.Buffer, value any) error {
+	switch v := value.(type) {
+	case nil:
+		buf.WriteByte(0xc0)
+	case bool:
+		if v {
+			buf.WriteByte(0xc3)
+		} else {
+			buf.WriteByte(0xc2)
+		}
+	case json.Number:
+		i, f, isInt, err := number(v)
+		if err != nil {
+			return err
+		}
+		if isInt {
+			msgPackInt(buf, i)
+		} else {
+			buf.WriteByte(0xcb)
+			buf.Write(binary.BigEndian.AppendUint64(nil, math.Float64bits(f)))
+		}
+	case string:
+		msgPackHeader(buf, len(v), 0xa0, 32, 0xd9, 0xda, 0xdb)
+		buf.WriteString(v)
+	case []any:
+		msgPackHeader(buf, len(v), 0x90, 16, 0, 0xdc, 0xdd)
+		for _, item := range v {
+			if err := encodeMsgPack(buf, item); err != nil {
+				return err
+			}
+		}
+	case map[string]any:
+		msgPackHeader(buf, len(v), 0x80, 16, 0, 0xde, 0xdf)
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if err := encodeMsgPack(buf, key); err != nil {
+				return err
+			}
+			if err := encodeMsgPack(buf, v[key]); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("msgpack: unsupported type %T", value)
+	}
+	return nil
+}
+
+func msgPackInt(buf *bytes.Buffer, i int64) {
+	switch {
+	case i >= 0 && i <= 0x7f:
+		buf.WriteByte(byte(i))
+	case i >= -32 && i < 0:
+		buf.WriteByte(byte(int8(i)))
+	case i >= 0 && i <= math.MaxUint8:
+		buf.Write([]byte{0xcc, byte(i)})
+	case i >= 0 && i <= math.MaxUint16:
+		buf.WriteByte(0xcd)
+		buf.Write(binary.BigEndian.AppendUint16(nil, uint16(i)))
+	case i >= 0 && i <= math.MaxUint32:
+		buf.WriteByte(0xce)
+		buf.Write(binary.BigEndian.AppendUint32(nil, uint32(i)))
+	case i >= 0:
+		buf.WriteByte(0xcf)
+		buf.Write(binary.BigEndian.AppendUint64(nil, uint64(i)))
+	case i >= math.MinInt8:
+		buf.Write([]byte{0xd0, byte(int8(i))})
+	case i >= math.MinInt16:
+		buf.WriteByte(0xd1)
+		buf.Write(binary.BigEndian.AppendUint16(nil, uint16(int16(i))))
+	case i >= math.MinInt32:
+		buf.WriteByte(0xd2)
+		buf.Write(binary.BigEndian.AppendUint32(nil, uint32(int32(i))))
+	default:
+		buf.WriteByte(0xd3)
+		buf.Write(binary.BigEndian.AppendUint64(nil, uint64(i)))
+	}
+}
+
+// Cabecera de longitud: la forma fija (fix | n) si n < fixMax, si no la de 8
+// (si existe para el tipo), 16 o 32 bits
+func msgPackHeader(buf *bytes.Buffer, n int, fix byte, fixMax int, code8, code16, code32 byte) {
+	switch {
+	case n < fixMax:
+		buf.WriteByte(fix | byte(n))
+	case code8 != 0 && n <= math.MaxUint8:
+		buf.Write([]byte{code8, byte(n)})
+	case n <= math.MaxUint16:
+		buf.WriteByte(code16)
+		buf.Write(binary.BigEndian.AppendUint16(nil, uint16(n)))
+	default:
+		buf.WriteByte(code32)
+		buf.Write(binary.BigEndian.AppendUint32(nil, uint32(n)))
+	}
+}
+
+// Lee un valor y devuelve el resto de la entrada. Los mapas solo pueden tener claves string.
+func decodeMsgPack(data []byte, depth int) (any, []byte, error) {
+	if depth > maxDepth {
+		return nil, nil, errTooDeep
+	}
+	if len(data) == 0 {
+		return nil, nil, errTruncated
+	}
+	code, data := data[0], data[1:]
+
+	switch {
+	case code <= 0x7f:
+		return int64(code), data, nil
+	case code >= 0xe0:
+		return int64(int8(code)), data, nil
+	case code&0xe0 == 0xa0:
+		return msgPackString(data, int(code&0x1f))
+	case code&0xf0 == 0x90:
+		return msgPackArray(data, int(code&0x0f), depth)
+	case code&0xf0 == 0x80:
+		return msgPackMap(data, int(code&0x0f), depth)
+	}
+
+	switch code {
+	case 0xc0:
+		return nil, data, nil
+	case 0xc2:
+		return false, data, nil
+	case 0xc3:
+		return true, data, nil
+	case 0xc4, 0xc5, 0xc6:
+		n, data, err := msgPackLength(data, code-0xc4)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(data) < n {
+			return nil, nil, errTruncated
+		}
+		return bytes.Clone(data[:n]), data[n:], nil
+	case 0xca:
+		if len(data) < 4 {
+			return nil, nil, errTruncated
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(data))), data[4:], nil
+	case 0xcb:
+		if len(data) < 8 {
+			return nil, nil, errTruncated
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(data)), data[8:], nil
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		size := 1 << (code - 0xcc)
+		if len(data) < size {
+			return nil, nil, errTruncated
+		}
+		return beUint(data[:size]), data[size:], nil
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		size := 1 << (code - 0xd0)
+		if len(data) < size {
+			return nil, nil, errTruncated
+		}
+		u := beUint(data[:size])
+		shift := 64 - 8*size
+		return int64(u<<shift) >> shift, data[size:], nil
+	case 0xd9, 0xda, 0xdb:
+		n, data, err := msgPackLength(data, code-0xd9)
+		if err != nil {
+			return nil, nil, err
+		}
+		return msgPackString(data, n)
+	case 0xdc, 0xdd:
+		n, data, err := msgPackLength(data, code-0xdc+1)
+		if err != nil {
+			return nil, nil, err
+		}
+		return msgPackArray(data, n, depth)
+	case 0xde, 0xdf:
+		n, data, err := msgPackLength(data, code-0xde+1)
+		if err != nil {
+			return nil, nil, err
+		}
+		return msgPackMap(data, n, depth)
+	}
+	return nil, nil, fmt.Errorf("unsupported type code 0x%02x", code)
+}
+
+// Longitud de 8, 16 o 32 bits segun size (0, 1, 2)
+func msgPackLength(data []byte, size byte) (int, []byte, error) {
+	width := 1 << size
+	if len(data) < width {
+		return 0, nil, errTruncated
+	}
+	return int(beUint(data[:width])), data[width:], nil
+}
+
+func msgPackString(data []byte, n int) (any, []byte, error) {
+	if len(data) < n {
+		return nil, nil, errTruncated
+	}
+	return string(data[:n]), data[n:], nil
+}
+
+func msgPackArray(data []byte, n int, depth int) (any, []byte, error) {
+	// Cada elemento ocupa al menos un byte
+	if len(data) < n {
+		return nil, nil, errTruncated
+	}
+	items := make([]any, 0, n)
+	for range n {
+		item, rest, err := decodeMsgPack(data, depth+1)
+		if err != nil {
+			return nil, nil, err
+		}
+		items = append(items, item)
+		data = rest
+	}
+	return items, data, nil
+}
+
+func msgPackMap(data []byte, n int, depth int) (any, []byte, error) {
+	if len(data) < 2*n {
+		return nil, nil, errTruncated
+	}
+	obj := make(map[string]any, n)
+	for range n {
+		key, rest, err := decodeMsgPack(data, depth+1)
+		if err != nil {
+			return nil, nil, err
+		}
+		name, ok := key.(string)
+		if !ok {
+			return nil, nil, fmt.Errorf("map key must be a string, got %T", key)
+		}
+		obj[name], data, err = decodeMsgPack(rest, depth+1)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return obj, data, nil
+}
+
+// Entero sin signo big endian de 1 a 8 bytes
+func beUint(b []byte) uint64 {
+	var u uint64
+	for _, c := range b {
+		u = u<<8 | uint64(c)
+	}
+	return u
+}
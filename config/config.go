@@ -0,0 +1,597 @@
+// Configuracion del servidor. Precedencia: valores por defecto < archivo YAML <
+// variables de entorno < flags de la linea de comandos.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go.yaml.in/yaml/v3"
+	"io"
+	"maps"
+	"os"
+	"restServer/logging"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Variable de entorno con la ruta del archivo de configuracion (equivale a -config)
+const EnvConfig = "TASKSERVER_CONFIG"
+
+// Archivo que se usa si existe y no se indica otro
+const DefaultFile = "config.yaml"
+
+type Config struct {
+	Server      Server      `yaml:"server"`
+	Features    Features    `yaml:"features"`
+	Storage     Storage     `yaml:"storage"`
+	Reminders   Reminders   `yaml:"reminders"`
+	Webhooks    Webhooks    `yaml:"webhooks"`
+	Tracing     Tracing     `yaml:"tracing"`
+	Log         Log         `yaml:"log"`
+	RateLimit   RateLimit   `yaml:"rateLimit"`
+	GraphQL     GraphQL     `yaml:"graphql"`
+	REST        REST        `yaml:"rest"`
+	Compression Compression `yaml:"compression"`
+	HTTPCache   HTTPCache   `yaml:"httpCache"`
+}
+
+type Server struct {
+	Addr       string `yaml:"addr"`
+	Name       string `yaml:"name"`       // cabecera Server de las respuestas
+	PublicHost string `yaml:"publicHost"` // host publicado en Swagger
+	TLS        TLS    `yaml:"tls"`
+
+	Timeouts Timeouts `yaml:"timeouts"`
+}
+
+// Timeouts de http.Server y del apagado ordenado. Las duraciones usan el formato de
+// time.ParseDuration ("15s", "2m").
+type Timeouts struct {
+	ReadHeader time.Duration `yaml:"readHeader"`
+	Read       time.Duration `yaml:"read"`     // incluye el cuerpo, p.ej. una importacion grande
+	Write      time.Duration `yaml:"write"`    // 0 desactiva el limite (streams largos)
+	Idle       time.Duration `yaml:"idle"`     // conexiones keep-alive sin peticiones
+	Shutdown   time.Duration `yaml:"shutdown"` // plazo para drenar conexiones y ejecutar los hooks
+
+	// Tiempo que /readyz responde 503 antes de dejar de aceptar conexiones, para que
+	// el balanceador saque la instancia. Cuenta dentro del plazo Shutdown.
+	ShutdownDelay time.Duration `yaml:"shutdownDelay"`
+}
+
+type TLS struct {
+	Enabled bool   `yaml:"enabled"`
+	Cert    string `yaml:"cert"` // se generan con mkcert o con el subcomando gen-cert
+	Key     string `yaml:"key"`
+}
+
+// Partes del servidor que se pueden desactivar
+type Features struct {
+	REST       bool `yaml:"rest"`
+	GraphQL    bool `yaml:"graphql"`
+	Playground bool `yaml:"playground"`
+	Docs       bool `yaml:"docs"`
+	Metrics    bool `yaml:"metrics"`
+}
+
+type Storage struct {
+	DataFile string `yaml:"dataFile"` // snapshot del store, se carga al iniciar y se guarda al apagar; vacio = solo en memoria
+}
+
+type Reminders struct {
+	Offsets    string `yaml:"offsets"`   // offsets por defecto antes de Due, p.ej. "24h,1h"
+	FiredFile  string `yaml:"firedFile"` // recordatorios ya enviados
+	WebhookURL string `yaml:"webhookUrl"`
+	SMTP       SMTP   `yaml:"smtp"`
+}
+
+type SMTP struct {
+	Addr string   `yaml:"addr"` // relay local, p.ej. localhost:25
+	From string   `yaml:"from"`
+	To   []string `yaml:"to"`
+}
+
+type Webhooks struct {
+	Workers int `yaml:"workers"`
+}
+
+// Handler GraphQL: transportes, caches y limites de cada operacion
+type GraphQL struct {
+	Transports    []string `yaml:"transports"`    // get, post, multipart, websocket, sse
+	QueryCache    int      `yaml:"queryCache"`    // documentos parseados en cache; 0 = sin cache
+	APQCache      int      `yaml:"apqCache"`      // consultas de APQ en cache; 0 desactiva APQ
+	Introspection bool     `yaml:"introspection"` // la necesitan el playground y los IDEs
+
+	// Limites calculados antes de ejecutar la operacion. 0 = sin limite.
+	MaxCost  int `yaml:"maxCost"`  // segun @cost y @listSize del schema
+	MaxDepth int `yaml:"maxDepth"` // niveles de campos anidados
+
+	PersistedQueries PersistedQueries `yaml:"persistedQueries"`
+}
+
+// Manifest de consultas persistidas (hash -> documento), generado con extract-queries
+type PersistedQueries struct {
+	Manifest string `yaml:"manifest"`
+	Enforce  bool   `yaml:"enforce"` // solo ejecutar operaciones del manifest (produccion)
+}
+
+// Versiones de la API REST. v1 y las rutas sin version responden como siempre,
+// con las cabeceras Deprecation y Sunset. Las fechas tienen formato 2006-01-02.
+type REST struct {
+	V1Deprecated string `yaml:"v1Deprecated"` // desde cuando v1 esta deprecada
+	V1Sunset     string `yaml:"v1Sunset"`     // fecha prevista de retiro; vacio = sin fecha
+	V1Link       string `yaml:"v1Link"`       // guia de migracion (Link rel="deprecation"); vacio = no se envia
+}
+
+// Compresion de las respuestas segun Accept-Encoding y de los cuerpos de las
+// peticiones con Content-Encoding
+type Compression struct {
+	Enabled   bool     `yaml:"enabled"`
+	Encodings []string `yaml:"encodings"` // zstd y/o gzip, en orden de preferencia
+	MinSize   int      `yaml:"minSize"`   // bytes; las respuestas mas chicas van sin comprimir
+	MaxBody   int      `yaml:"maxBody"`   // bytes de un cuerpo descomprimido, limita las bombas de compresion
+}
+
+// Cache HTTP de las lecturas REST: ETag y Last-Modified segun la version del store,
+// GET condicional (304) y respuestas guardadas en memoria hasta el siguiente cambio
+type HTTPCache struct {
+	Enabled       bool              `yaml:"enabled"`
+	Entries       int               `yaml:"entries"`       // respuestas en memoria; 0 = solo ETag y 304
+	MaxEntryBytes int               `yaml:"maxEntryBytes"` // las respuestas mas grandes no se guardan
+	Routes        map[string]string `yaml:"routes"`        // patron GET del ServeMux -> Cache-Control ("" = sin la cabecera)
+}
+
+// Limites por cliente con token bucket. El cliente es la IP: el servidor no tiene
+// autenticacion, ratelimit.WithIdentity queda para un middleware que la agregue.
+type RateLimit struct {
+	Enabled    bool            `yaml:"enabled"`
+	TrustProxy bool            `yaml:"trustProxy"` // IP del cliente desde X-Forwarded-For
+	Default    Rate            `yaml:"default"`    // rutas HTTP sin regla propia, un bucket compartido
+	Routes     map[string]Rate `yaml:"routes"`     // por patron del ServeMux, p.ej. "POST /task/"
+	GraphQL    GraphQLRates    `yaml:"graphql"`
+	MaxBuckets int             `yaml:"maxBuckets"` // buckets en memoria, al llenarse se descarta el mas viejo
+}
+
+type GraphQLRates struct {
+	Default    Rate            `yaml:"default"`    // todas las operaciones, ademas de su regla propia
+	Operations map[string]Rate `yaml:"operations"` // nombre de operacion o campo raiz ("Mutation.createTasks")
+}
+
+// Requests por cada Per con rafagas de hasta Burst (Requests si es 0). Requests 0 = sin limite.
+type Rate struct {
+	Requests int           `yaml:"requests"`
+	Per      time.Duration `yaml:"per"`
+	Burst    int           `yaml:"burst"`
+}
+
+// Logs estructurados de log/slog
+type Log struct {
+	Level     string `yaml:"level"`     // debug, info, warn o error
+	Format    string `yaml:"format"`    // json o text
+	BodyLimit int    `yaml:"bodyLimit"` // bytes de cada cuerpo en el log de accesos (solo en debug)
+}
+
+// Trazas de OpenTelemetry exportadas por OTLP/HTTP
+type Tracing struct {
+	Enabled     bool    `yaml:"enabled"`
+	Endpoint    string  `yaml:"endpoint"` // host:puerto del collector, p.ej. localhost:4318
+	Insecure    bool    `yaml:"insecure"` // http en vez de https hacia el collector
+	ServiceName string  `yaml:"serviceName"`
+	SampleRatio float64 `yaml:"sampleRatio"` // fraccion de trazas nuevas que se muestrean, 0 a 1
+}
+
+func Default() Config {
+	return Config{
+		Server: Server{
+			Addr:       ":8443",
+			Name:       "Andres :D",
+			PublicHost: "localhost:8443",
+			TLS: TLS{
+				Enabled: true,
+				Cert:    "localhost.pem",
+				Key:     "localhost-key.pem",
+			},
+			Timeouts: Timeouts{
+				ReadHeader: 5 * time.Second,
+				Read:       30 * time.Second,
+				Write:      60 * time.Second,
+				Idle:       120 * time.Second,
+				Shutdown:   20 * time.Second,
+			},
+		},
+		Features: Features{REST: true, GraphQL: true, Playground: true, Docs: true, Metrics: true},
+		Reminders: Reminders{
+			FiredFile: "reminders_fired.json",
+		},
+		Webhooks: Webhooks{Workers: 4},
+		RateLimit: RateLimit{
+			Enabled:    true,
+			MaxBuckets: 100000,
+			Default:    Rate{Requests: 600, Per: time.Minute, Burst: 100},
+			Routes: map[string]Rate{
+				"POST /task/":             {Requests: 60, Per: time.Minute, Burst: 20},
+				"POST /task/batch":        {Requests: 20, Per: time.Minute, Burst: 5},
+				"POST /task/batch/{$}":    {Requests: 20, Per: time.Minute, Burst: 5},
+				"POST /import":            {Requests: 5, Per: time.Minute, Burst: 2},
+				"POST /v1/task/":          {Requests: 60, Per: time.Minute, Burst: 20},
+				"POST /v1/task/batch":     {Requests: 20, Per: time.Minute, Burst: 5},
+				"POST /v1/task/batch/{$}": {Requests: 20, Per: time.Minute, Burst: 5},
+				"POST /v1/import":         {Requests: 5, Per: time.Minute, Burst: 2},
+				"POST /v2/tasks":          {Requests: 60, Per: time.Minute, Burst: 20},
+				"POST /v2/tasks/batch":    {Requests: 20, Per: time.Minute, Burst: 5},
+				"POST /v2/import":         {Requests: 5, Per: time.Minute, Burst: 2},
+			},
+			GraphQL: GraphQLRates{
+				Default: Rate{Requests: 300, Per: time.Minute, Burst: 60},
+				Operations: map[string]Rate{
+					"Mutation.createTask":  {Requests: 60, Per: time.Minute, Burst: 20},
+					"Mutation.createTasks": {Requests: 20, Per: time.Minute, Burst: 5},
+					"Mutation.addTask":     {Requests: 60, Per: time.Minute, Burst: 20},
+					"Mutation.addTasks":    {Requests: 20, Per: time.Minute, Burst: 5},
+				},
+			},
+		},
+		GraphQL: GraphQL{
+			Transports:    []string{"get", "post"},
+			QueryCache:    1000,
+			APQCache:      100,
+			Introspection: true,
+			MaxCost:       5000,
+			MaxDepth:      10,
+		},
+		REST: REST{
+			V1Deprecated: "2026-10-19",
+			V1Sunset:     "2027-04-30",
+		},
+		Compression: Compression{
+			Enabled:   true,
+			Encodings: []string{"zstd", "gzip"},
+			MinSize:   1024,
+			MaxBody:   64 << 20,
+		},
+		HTTPCache: HTTPCache{
+			Enabled:       true,
+			Entries:       256,
+			MaxEntryBytes: 1 << 20,
+			Routes: map[string]string{
+				"GET /task/":                        "no-cache",
+				"GET /tag/{tag}/":                   "no-cache",
+				"GET /due/{year}/{month}/{day}/":    "no-cache",
+				"GET /v1/task/":                     "no-cache",
+				"GET /v1/tag/{tag}/":                "no-cache",
+				"GET /v1/due/{year}/{month}/{day}/": "no-cache",
+				"GET /v2/tasks":                     "no-cache",
+			},
+		},
+		Log: Log{
+			Level:     "info",
+			Format:    "json",
+			BodyLimit: 2048,
+		},
+		Tracing: Tracing{
+			Endpoint:    "localhost:4318",
+			Insecure:    true,
+			ServiceName: "taskserver",
+			SampleRatio: 1,
+		},
+	}
+}
+
+// Configuracion de un subcomando: archivo (-config, TASKSERVER_CONFIG o
+// config.yaml si existe), entorno y por ultimo los flags de args.
+func Load(name string, args []string) (Config, error) {
+	// Primera pasada solo para conocer el archivo
+	path := os.Getenv(EnvConfig)
+	probe := Default()
+	if err := flagSet(name, &probe, &path).Parse(args); err != nil {
+		return Config{}, err
+	}
+
+	c, err := FromFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+
+	// Parse solo asigna los flags presentes, el resto conserva archivo y entorno
+	fs := flagSet(name, &c, &path)
+	if err := fs.Parse(args); err != nil {
+		return Config{}, err
+	}
+	if fs.NArg() > 0 {
+		return Config{}, fmt.Errorf("%s: unexpected arguments %v", name, fs.Args())
+	}
+	return c, c.Validate()
+}
+
+// Valores por defecto, archivo y entorno, sin flags. Con path vacio se usa
+// config.yaml si existe.
+func FromFile(path string) (Config, error) {
+	c := Default()
+
+	optional := path == ""
+	if optional {
+		path = DefaultFile
+	}
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist) && optional:
+	case err != nil:
+		return Config{}, err
+	default:
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&c); err != nil && !errors.Is(err, io.EOF) {
+			return Config{}, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	if err := c.applyEnv(); err != nil {
+		return Config{}, err
+	}
+	return c, nil
+}
+
+func (c Config) Validate() error {
+	var errs []string
+	if c.Server.Addr == "" {
+		errs = append(errs, "server.addr is required")
+	}
+	if c.Server.TLS.Enabled && (c.Server.TLS.Cert == "" || c.Server.TLS.Key == "") {
+		errs = append(errs, "server.tls.cert and server.tls.key are required when tls is enabled")
+	}
+	if !c.Features.REST && !c.Features.GraphQL {
+		errs = append(errs, "at least one of features.rest and features.graphql must be enabled")
+	}
+	if c.Features.Playground && !c.Features.GraphQL {
+		errs = append(errs, "features.playground requires features.graphql")
+	}
+	if c.Features.Docs && !c.Features.REST {
+		errs = append(errs, "features.docs requires features.rest")
+	}
+	if c.Server.Timeouts.Shutdown <= 0 {
+		errs = append(errs, "server.timeouts.shutdown must be positive")
+	}
+	if c.Server.Timeouts.ShutdownDelay >= c.Server.Timeouts.Shutdown {
+		errs = append(errs, "server.timeouts.shutdownDelay must be shorter than server.timeouts.shutdown")
+	}
+	if c.Webhooks.Workers < 1 {
+		errs = append(errs, "webhooks.workers must be at least 1")
+	}
+	if c.RateLimit.Enabled {
+		errs = append(errs, c.RateLimit.validate()...)
+	}
+	if _, err := logging.New(io.Discard, c.Log.Level, c.Log.Format); err != nil {
+		errs = append(errs, err.Error())
+	}
+	if c.Features.Playground && !c.GraphQL.Introspection {
+		errs = append(errs, "features.playground requires graphql.introspection")
+	}
+	if c.GraphQL.QueryCache < 0 || c.GraphQL.APQCache < 0 {
+		errs = append(errs, "graphql.queryCache and graphql.apqCache must not be negative")
+	}
+	if c.GraphQL.MaxCost < 0 || c.GraphQL.MaxDepth < 0 {
+		errs = append(errs, "graphql.maxCost and graphql.maxDepth must not be negative")
+	}
+	if c.GraphQL.PersistedQueries.Enforce && c.GraphQL.PersistedQueries.Manifest == "" {
+		errs = append(errs, "graphql.persistedQueries.manifest is required when enforce is enabled")
+	}
+	if _, err := ParseDate(c.REST.V1Deprecated); err != nil {
+		errs = append(errs, "rest.v1Deprecated must be a date like 2006-01-02")
+	}
+	if _, err := ParseDate(c.REST.V1Sunset); err != nil {
+		errs = append(errs, "rest.v1Sunset must be a date like 2006-01-02")
+	}
+	for _, encoding := range c.Compression.Encodings {
+		if encoding != "zstd" && encoding != "gzip" {
+			errs = append(errs, "compression.encodings must contain only zstd and gzip")
+			break
+		}
+	}
+	if c.Compression.MinSize < 0 {
+		errs = append(errs, "compression.minSize must not be negative")
+	}
+	if c.Compression.MaxBody <= 0 {
+		errs = append(errs, "compression.maxBody must be positive")
+	}
+	if c.HTTPCache.Entries < 0 || c.HTTPCache.MaxEntryBytes < 0 {
+		errs = append(errs, "httpCache.entries and httpCache.maxEntryBytes must not be negative")
+	}
+	for _, route := range slices.Sorted(maps.Keys(c.HTTPCache.Routes)) {
+		if !strings.HasPrefix(route, "GET ") {
+			errs = append(errs, "httpCache.routes["+route+"] must be a GET pattern")
+		}
+	}
+	if c.Log.BodyLimit < 0 {
+		errs = append(errs, "log.bodyLimit must not be negative")
+	}
+	if c.Tracing.Enabled && c.Tracing.Endpoint == "" {
+		errs = append(errs, "tracing.endpoint is required when tracing is enabled")
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		errs = append(errs, "tracing.sampleRatio must be between 0 and 1")
+	}
+	if len(errs) > 0 {
+		return errors.New("invalid config: " + strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Fecha 2006-01-02 de la configuracion; vacia es el tiempo cero
+func ParseDate(date string) (time.Time, error) {
+	if date == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.DateOnly, date)
+}
+
+func (r RateLimit) validate() []string {
+	var errs []string
+	check := func(name string, rate Rate) {
+		if rate.Requests < 0 || rate.Burst < 0 || (rate.Requests > 0 && rate.Per <= 0) {
+			errs = append(errs, name+" must have non-negative requests and burst and a positive per")
+		}
+	}
+	if r.MaxBuckets < 1 {
+		errs = append(errs, "rateLimit.maxBuckets must be at least 1")
+	}
+	check("rateLimit.default", r.Default)
+	for route, rate := range r.Routes {
+		check("rateLimit.routes["+route+"]", rate)
+	}
+	check("rateLimit.graphql.default", r.GraphQL.Default)
+	for op, rate := range r.GraphQL.Operations {
+		check("rateLimit.graphql.operations["+op+"]", rate)
+	}
+	sort.Strings(errs)
+	return errs
+}
+
+// Variables de entorno y el campo que sobrescriben. Se mantienen los nombres
+// REMINDER_* que ya usaba el servidor.
+func (c *Config) envVars() []struct {
+	name   string
+	target any
+} {
+	return []struct {
+		name   string
+		target any
+	}{
+		{"TASKSERVER_ADDR", &c.Server.Addr},
+		{"TASKSERVER_NAME", &c.Server.Name},
+		{"TASKSERVER_PUBLIC_HOST", &c.Server.PublicHost},
+		{"TASKSERVER_TLS", &c.Server.TLS.Enabled},
+		{"TASKSERVER_TLS_CERT", &c.Server.TLS.Cert},
+		{"TASKSERVER_TLS_KEY", &c.Server.TLS.Key},
+		{"TASKSERVER_READ_HEADER_TIMEOUT", &c.Server.Timeouts.ReadHeader},
+		{"TASKSERVER_READ_TIMEOUT", &c.Server.Timeouts.Read},
+		{"TASKSERVER_WRITE_TIMEOUT", &c.Server.Timeouts.Write},
+		{"TASKSERVER_IDLE_TIMEOUT", &c.Server.Timeouts.Idle},
+		{"TASKSERVER_SHUTDOWN_TIMEOUT", &c.Server.Timeouts.Shutdown},
+		{"TASKSERVER_SHUTDOWN_DELAY", &c.Server.Timeouts.ShutdownDelay},
+		{"TASKSERVER_REST", &c.Features.REST},
+		{"TASKSERVER_GRAPHQL", &c.Features.GraphQL},
+		{"TASKSERVER_PLAYGROUND", &c.Features.Playground},
+		{"TASKSERVER_DOCS", &c.Features.Docs},
+		{"TASKSERVER_METRICS", &c.Features.Metrics},
+		{"TASKSERVER_DATA_FILE", &c.Storage.DataFile},
+		{"TASKSERVER_WEBHOOK_WORKERS", &c.Webhooks.Workers},
+		{"REMINDER_OFFSETS", &c.Reminders.Offsets},
+		{"REMINDER_FIRED_FILE", &c.Reminders.FiredFile},
+		{"REMINDER_WEBHOOK_URL", &c.Reminders.WebhookURL},
+		{"REMINDER_SMTP_ADDR", &c.Reminders.SMTP.Addr},
+		{"REMINDER_SMTP_FROM", &c.Reminders.SMTP.From},
+		{"REMINDER_SMTP_TO", &c.Reminders.SMTP.To},
+		{"TASKSERVER_RATE_LIMIT", &c.RateLimit.Enabled},
+		{"TASKSERVER_RATE_LIMIT_TRUST_PROXY", &c.RateLimit.TrustProxy},
+		{"TASKSERVER_GRAPHQL_TRANSPORTS", &c.GraphQL.Transports},
+		{"TASKSERVER_GRAPHQL_INTROSPECTION", &c.GraphQL.Introspection},
+		{"TASKSERVER_GRAPHQL_MAX_COST", &c.GraphQL.MaxCost},
+		{"TASKSERVER_GRAPHQL_MAX_DEPTH", &c.GraphQL.MaxDepth},
+		{"TASKSERVER_PERSISTED_QUERIES", &c.GraphQL.PersistedQueries.Manifest},
+		{"TASKSERVER_PERSISTED_QUERIES_ENFORCE", &c.GraphQL.PersistedQueries.Enforce},
+		{"TASKSERVER_REST_V1_DEPRECATED", &c.REST.V1Deprecated},
+		{"TASKSERVER_REST_V1_SUNSET", &c.REST.V1Sunset},
+		{"TASKSERVER_REST_V1_LINK", &c.REST.V1Link},
+		{"TASKSERVER_COMPRESSION", &c.Compression.Enabled},
+		{"TASKSERVER_COMPRESSION_ENCODINGS", &c.Compression.Encodings},
+		{"TASKSERVER_COMPRESSION_MIN_SIZE", &c.Compression.MinSize},
+		{"TASKSERVER_COMPRESSION_MAX_BODY", &c.Compression.MaxBody},
+		{"TASKSERVER_HTTP_CACHE", &c.HTTPCache.Enabled},
+		{"TASKSERVER_HTTP_CACHE_ENTRIES", &c.HTTPCache.Entries},
+		{"TASKSERVER_LOG_LEVEL", &c.Log.Level},
+		{"TASKSERVER_LOG_FORMAT", &c.Log.Format},
+		{"TASKSERVER_LOG_BODY_LIMIT", &c.Log.BodyLimit},
+		{"TASKSERVER_TRACING", &c.Tracing.Enabled},
+		{"TASKSERVER_TRACING_ENDPOINT", &c.Tracing.Endpoint},
+		{"TASKSERVER_TRACING_INSECURE", &c.Tracing.Insecure},
+		{"TASKSERVER_TRACING_SERVICE_NAME", &c.Tracing.ServiceName},
+		{"TASKSERVER_TRACING_SAMPLE_RATIO", &c.Tracing.SampleRatio},
+	}
+}
+
+func (c *Config) applyEnv() error {
+	for _, v := range c.envVars() {
+		raw, ok := os.LookupEnv(v.name)
+		if !ok {
+			continue
+		}
+		if err := set(v.target, raw); err != nil {
+			return fmt.Errorf("%s: %w", v.name, err)
+		}
+	}
+	return nil
+}
+
+func set(target any, raw string) error {
+	switch t := target.(type) {
+	case *string:
+		*t = raw
+	case *bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		*t = b
+	case *time.Duration:
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		*t = d
+	case *int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return err
+		}
+		*t = n
+	case *float64:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return err
+		}
+		*t = f
+	case *[]string:
+		*t = nil
+		for _, s := range strings.Split(raw, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				*t = append(*t, s)
+			}
+		}
+	}
+	return nil
+}
+
+func flagSet(name string, c *Config, path *string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.StringVar(path, "config", *path, "archivo de configuracion YAML (por defecto "+DefaultFile+" si existe)")
+	fs.StringVar(&c.Server.Addr, "addr", c.Server.Addr, "direccion de escucha")
+	fs.StringVar(&c.Server.Name, "name", c.Server.Name, "valor de la cabecera Server")
+	fs.StringVar(&c.Server.PublicHost, "public-host", c.Server.PublicHost, "host publicado en Swagger")
+	fs.BoolVar(&c.Server.TLS.Enabled, "tls", c.Server.TLS.Enabled, "servir HTTPS")
+	fs.StringVar(&c.Server.TLS.Cert, "tls-cert", c.Server.TLS.Cert, "certificado TLS")
+	fs.StringVar(&c.Server.TLS.Key, "tls-key", c.Server.TLS.Key, "clave privada TLS")
+	fs.DurationVar(&c.Server.Timeouts.Shutdown, "shutdown-timeout", c.Server.Timeouts.Shutdown, "plazo para el apagado ordenado")
+	fs.BoolVar(&c.Features.REST, "rest", c.Features.REST, "habilitar la API REST")
+	fs.BoolVar(&c.Features.GraphQL, "graphql", c.Features.GraphQL, "habilitar GraphQL")
+	fs.BoolVar(&c.Features.Playground, "playground", c.Features.Playground, "habilitar el playground de GraphQL")
+	fs.IntVar(&c.GraphQL.MaxCost, "graphql-max-cost", c.GraphQL.MaxCost, "costo maximo de una operacion GraphQL (0 = sin limite)")
+	fs.IntVar(&c.GraphQL.MaxDepth, "graphql-max-depth", c.GraphQL.MaxDepth, "profundidad maxima de una operacion GraphQL (0 = sin limite)")
+	fs.StringVar(&c.GraphQL.PersistedQueries.Manifest, "persisted-queries", c.GraphQL.PersistedQueries.Manifest, "manifest de consultas persistidas")
+	fs.BoolVar(&c.GraphQL.PersistedQueries.Enforce, "persisted-queries-enforce", c.GraphQL.PersistedQueries.Enforce, "solo ejecutar las operaciones del manifest")
+	fs.BoolVar(&c.Features.Docs, "docs", c.Features.Docs, "habilitar Swagger en /docs/")
+	fs.BoolVar(&c.Features.Metrics, "metrics", c.Features.Metrics, "habilitar metricas de Prometheus en /metrics")
+	fs.StringVar(&c.Storage.DataFile, "data", c.Storage.DataFile, "archivo de datos del store (vacio = solo en memoria)")
+	fs.IntVar(&c.Webhooks.Workers, "webhook-workers", c.Webhooks.Workers, "workers de entrega de webhooks")
+	fs.BoolVar(&c.RateLimit.Enabled, "rate-limit", c.RateLimit.Enabled, "limitar peticiones por cliente")
+	fs.BoolVar(&c.Compression.Enabled, "compression", c.Compression.Enabled, "comprimir respuestas (zstd, gzip) y aceptar cuerpos comprimidos")
+	fs.BoolVar(&c.HTTPCache.Enabled, "http-cache", c.HTTPCache.Enabled, "ETag, GET condicional y cache de respuestas en las lecturas REST")
+	fs.StringVar(&c.Log.Level, "log-level", c.Log.Level, "nivel de log: debug, info, warn o error")
+	fs.StringVar(&c.Log.Format, "log-format", c.Log.Format, "formato de log: json o text")
+	fs.BoolVar(&c.Tracing.Enabled, "tracing", c.Tracing.Enabled, "exportar trazas de OpenTelemetry por OTLP")
+	fs.StringVar(&c.Tracing.Endpoint, "tracing-endpoint", c.Tracing.Endpoint, "collector OTLP/HTTP (host:puerto)")
+	return fs
+}
@@ -0,0 +1,144 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, yaml string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(yaml), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// Precedencia: por defecto < archivo < entorno < flags
+func TestLoadPrecedence(t *testing.T) {
+	path := writeConfig(t, `
+server:
+  addr: ":1111"
+  name: from-file
+  timeouts:
+    shutdown: 5s
+webhooks:
+  workers: 2
+log:
+  level: warn
+`)
+	t.Setenv(EnvConfig, path)
+	t.Setenv("TASKSERVER_NAME", "from-env")
+	t.Setenv("TASKSERVER_WEBHOOK_WORKERS", "3")
+	t.Setenv("TASKSERVER_COMPRESSION_ENCODINGS", "gzip, zstd")
+
+	c, err := Load("serve", []string{"-webhook-workers", "7", "-log-level", "debug"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		field string
+		got   any
+		want  any
+	}{
+		{"default", c.Server.Timeouts.Idle, Default().Server.Timeouts.Idle},
+		{"file", c.Server.Addr, ":1111"},
+		{"file duration", c.Server.Timeouts.Shutdown, 5 * time.Second},
+		{"env over file", c.Server.Name, "from-env"},
+		{"env list", strings.Join(c.Compression.Encodings, ","), "gzip,zstd"},
+		{"flag over env", c.Webhooks.Workers, 7},
+		{"flag over file", c.Log.Level, "debug"},
+		{"file keeps the default rate limit routes", c.RateLimit.Routes["POST /import"].Requests, 5},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.field, tt.got, tt.want)
+		}
+	}
+}
+
+func TestLoadConfigFlag(t *testing.T) {
+	t.Setenv(EnvConfig, writeConfig(t, "server:\n  name: env-file\n"))
+	path := writeConfig(t, "server:\n  name: flag-file\n")
+
+	c, err := Load("serve", []string{"-config", path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Server.Name != "flag-file" {
+		t.Errorf("name = %q, -config must win over %s", c.Server.Name, EnvConfig)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		env  map[string]string
+		args []string
+		want string
+	}{
+		{"unknown yaml field", "server:\n  adress: x\n", nil, nil, "field adress not found"},
+		{"bad env value", "", map[string]string{"TASKSERVER_WEBHOOK_WORKERS": "many"}, nil, "TASKSERVER_WEBHOOK_WORKERS"},
+		{"unknown flag", "", nil, []string{"-bogus"}, "flag provided but not defined"},
+		{"extra arguments", "", nil, []string{"now"}, "unexpected arguments"},
+		{"invalid result", "", nil, []string{"-graphql=false", "-rest=false"}, "at least one of features.rest and features.graphql"},
+		{"missing file", "", map[string]string{EnvConfig: "/does/not/exist.yaml"}, nil, "no such file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(EnvConfig, writeConfig(t, tt.yaml))
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			_, err := Load("serve", tt.args)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Load() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := Default().Validate(); err != nil {
+		t.Fatalf("default config: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(c *Config)
+		want   string
+	}{
+		{"tls without files", func(c *Config) { c.Server.TLS = TLS{Enabled: true} }, "server.tls.cert and server.tls.key"},
+		{"playground without graphql", func(c *Config) { c.Features.GraphQL = false }, "features.playground requires features.graphql"},
+		{"delay longer than shutdown", func(c *Config) { c.Server.Timeouts.ShutdownDelay = time.Hour }, "shutdownDelay must be shorter"},
+		{"bad rate", func(c *Config) { c.RateLimit.Routes["POST /x"] = Rate{Requests: 1} }, "rateLimit.routes[POST /x]"},
+		{"bad rate ignored when disabled", func(c *Config) { c.RateLimit.Enabled = false; c.RateLimit.Default = Rate{Requests: -1} }, ""},
+		{"enforce without manifest", func(c *Config) { c.GraphQL.PersistedQueries.Enforce = true }, "persistedQueries.manifest is required"},
+		{"bad date", func(c *Config) { c.REST.V1Sunset = "next year" }, "rest.v1Sunset"},
+		{"unknown encoding", func(c *Config) { c.Compression.Encodings = []string{"br"} }, "compression.encodings"},
+		{"cache route not GET", func(c *Config) { c.HTTPCache.Routes = map[string]string{"POST /task/": ""} }, "must be a GET pattern"},
+		{"sample ratio", func(c *Config) { c.Tracing.SampleRatio = 2 }, "tracing.sampleRatio"},
+		{"log level", func(c *Config) { c.Log.Level = "loud" }, "loud"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Default()
+			tt.modify(&c)
+			err := c.Validate()
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("Validate() = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Validate() = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
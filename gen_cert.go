@@ -6,56 +6,101 @@ import (
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/pem"
+	"flag"
 	"fmt"
 	"math/big"
 	"net"
 	"os"
+	"restServer/config"
+	"strings"
 	"time"
 )
 
-func GenerateCert() {
-	priv, _ := rsa.GenerateKey(rand.Reader, 2048)
+// Subcomando gen-cert: certificado autofirmado para desarrollo. Por defecto
+// escribe los archivos que usa server.tls de la configuracion.
+func runGenCert(args []string) error {
+	defaults := config.Default().Server.TLS
+	fs := flag.NewFlagSet("gen-cert", flag.ContinueOnError)
+	certFile := fs.String("cert", defaults.Cert, "archivo del certificado")
+	keyFile := fs.String("key", defaults.Key, "archivo de la clave privada")
+	hosts := fs.String("hosts", "localhost,127.0.0.1", "nombres DNS e IPs separados por coma")
+	validFor := fs.Duration("valid-for", 365*24*time.Hour, "vigencia del certificado")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	return GenerateCert(*certFile, *keyFile, strings.Split(*hosts, ","), *validFor)
+}
+
+func GenerateCert(certFile, keyFile string, hosts []string, validFor time.Duration) error {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return err
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return err
+	}
 
 	template := x509.Certificate{
-		SerialNumber: big.NewInt(1),
+		SerialNumber: serial,
 		Subject: pkix.Name{
 			Organization: []string{"Local Dev"},
 		},
 		NotBefore: time.Now(),
-		NotAfter:  time.Now().Add(365 * 24 * time.Hour),
+		NotAfter:  time.Now().Add(validFor),
 
 		KeyUsage: x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
 		ExtKeyUsage: []x509.ExtKeyUsage{
 			x509.ExtKeyUsageServerAuth,
 		},
-
-		DNSNames: []string{"localhost"},
-		IPAddresses: []net.IP{
-			net.ParseIP("127.0.0.1"),
-		},
+	}
+	for _, h := range hosts {
+		h = strings.TrimSpace(h)
+		if ip := net.ParseIP(h); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else if h != "" {
+			template.DNSNames = append(template.DNSNames, h)
+		}
 	}
 
-	derBytes, _ := x509.CreateCertificate(
+	derBytes, err := x509.CreateCertificate(
 		rand.Reader,
 		&template,
 		&template,
 		&priv.PublicKey,
 		priv,
 	)
+	if err != nil {
+		return err
+	}
 
-	certOut, _ := os.Create("cert.pem")
-	pem.Encode(certOut, &pem.Block{
+	if err := writePEM(certFile, 0o644, &pem.Block{
 		Type:  "CERTIFICATE",
 		Bytes: derBytes,
-	})
-	certOut.Close()
+	}); err != nil {
+		return err
+	}
 
-	keyOut, _ := os.Create("key.pem")
-	pem.Encode(keyOut, &pem.Block{
+	if err := writePEM(keyFile, 0o600, &pem.Block{
 		Type:  "RSA PRIVATE KEY",
 		Bytes: x509.MarshalPKCS1PrivateKey(priv),
-	})
-	keyOut.Close()
+	}); err != nil {
+		return err
+	}
 
-	fmt.Println("cert.pem y key.pem generados exitosamente")
+	fmt.Printf("%s y %s generados exitosamente\n", certFile, keyFile)
+	return nil
+}
+
+func writePEM(path string, perm os.FileMode, block *pem.Block) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if err := pem.Encode(f, block); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
 }
@@ -0,0 +1,162 @@
+package graph
+
+import (
+	"encoding/base64"
+	"net/http"
+	"restServer/graph/model"
+	"restServer/problem"
+	"restServer/taskstore"
+	"sort"
+	"strings"
+)
+
+// Tamano de pagina de las listas paginadas sin first/limit (o en 0), el mismo
+// assumedSize de @listSize en el schema, y maximo que se acepta. Asi el costo que
+// estima CostLimit es una cota superior de lo que devuelven los resolvers.
+const (
+	defaultPageSize        = 100
+	defaultCommentPageSize = 50
+	maxPageSize            = 1000
+)
+
+// Largo maximo de las listas sin paginacion, el assumedSize de cada una en el
+// schema: sin el corte devolverian todo lo guardado con el costo de assumedSize
+const (
+	maxAllTasks      = 100
+	maxFilteredTasks = 50
+	maxWebhooks      = 100
+)
+
+// Primeros n elementos de list
+func truncate[T any](list []T, n int) []T {
+	return list[:min(len(list), n)]
+}
+
+// Tamano de pagina de un argumento first/limit: fallback si falta o es 0, como
+// mucho maxPageSize
+func pageSize(requested *int32, fallback int) int {
+	if requested == nil || *requested <= 0 {
+		return fallback
+	}
+	return min(int(*requested), maxPageSize)
+}
+
+// Los cursores de las conexiones son opacos: codifican el Id del elemento, asi una
+// pagina sigue despues de el aunque se agreguen o borren otros
+const cursorPrefix = "cursor:"
+
+var errInvalidCursor = problem.New(http.StatusBadRequest, "invalid_cursor", "invalid cursor")
+
+func encodeCursor(id string) string {
+	return base64.StdEncoding.EncodeToString([]byte(cursorPrefix + id))
+}
+
+func decodeCursor(cursor string) (string, error) {
+	raw, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(raw), cursorPrefix) {
+		return "", errInvalidCursor
+	}
+	id := strings.TrimPrefix(string(raw), cursorPrefix)
+	if id == "" {
+		return "", errInvalidCursor
+	}
+	return id, nil
+}
+
+// PageInfo de la pagina ids[start:end]
+func pageInfo(ids []string, start, end int) *model.PageInfo {
+	info := &model.PageInfo{
+		HasNextPage:     end < len(ids),
+		HasPreviousPage: start > 0,
+	}
+	if end > start {
+		first, last := encodeCursor(ids[start]), encodeCursor(ids[end-1])
+		info.StartCursor = &first
+		info.EndCursor = &last
+	}
+	return info
+}
+
+// Limites [start, end) de una pagina Relay sobre ids ordenados (LessId): after y
+// before acotan la ventana, aunque su elemento ya no exista, first toma los
+// primeros y last los ultimos de ella (0 da una pagina vacia). Sin first ni last
+// la pagina es de size
+func connectionBounds(ids []string, first, last *int32, after, before *string, size int) (int, int, error) {
+	start, end := 0, len(ids)
+	if after != nil {
+		id, err := decodeCursor(*after)
+		if err != nil {
+			return 0, 0, err
+		}
+		start = sort.Search(len(ids), func(i int) bool { return taskstore.LessId(id, ids[i]) })
+	}
+	if before != nil {
+		id, err := decodeCursor(*before)
+		if err != nil {
+			return 0, 0, err
+		}
+		pos := sort.Search(len(ids), func(i int) bool { return !taskstore.LessId(ids[i], id) })
+		end = max(min(pos, end), start)
+	}
+	if (first != nil && *first < 0) || (last != nil && *last < 0) {
+		return 0, 0, problem.New(http.StatusBadRequest, "invalid_input", "first and last must not be negative")
+	}
+	if first == nil && last == nil {
+		end = min(end, start+size)
+	}
+	if first != nil {
+		end = min(end, start+min(int(*first), maxPageSize))
+	}
+	if last != nil {
+		start = max(start, end-min(int(*last), maxPageSize))
+	}
+	return start, end, nil
+}
+
+// Pagina de una conexion de tareas sobre la lista completa y ordenada
+func taskConnection(tasks []taskstore.Task, first, last *int32, after, before *string) (*model.TaskConnection, error) {
+	ids := make([]string, len(tasks))
+	for i := range tasks {
+		ids[i] = tasks[i].ID
+	}
+	start, end, err := connectionBounds(ids, first, last, after, before, defaultPageSize)
+	if err != nil {
+		return nil, err
+	}
+	edges := make([]*model.TaskEdge, 0, end-start)
+	for i := start; i < end; i++ {
+		edges = append(edges, &model.TaskEdge{
+			Cursor: encodeCursor(ids[i]),
+			Node:   taskModel(tasks[i]),
+		})
+	}
+	return &model.TaskConnection{
+		Edges:      edges,
+		PageInfo:   pageInfo(ids, start, end),
+		TotalCount: int32(len(tasks)),
+	}, nil
+}
+
+// Pagina de una conexion de comentarios sobre la lista completa y ordenada
+func commentConnection(comments []taskstore.Comment, first *int32, after *string) (*model.CommentConnection, error) {
+	ids := make([]string, len(comments))
+	for i := range comments {
+		ids[i] = comments[i].ID
+	}
+	start, end, err := connectionBounds(ids, first, nil, after, nil, defaultCommentPageSize)
+	if err != nil {
+		return nil, err
+	}
+	edges := make([]*model.CommentEdge, 0, end-start)
+	for i := start; i < end; i++ {
+		edges = append(edges, &model.CommentEdge{
+			Cursor: encodeCursor(ids[i]),
+			Node:   commentModel(comments[i]),
+		})
+	}
+	return &model.CommentConnection{
+		Edges:      edges,
+		PageInfo:   pageInfo(ids, start, end),
+		TotalCount: int32(len(comments)),
+	}, nil
+}
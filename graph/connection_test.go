@@ -0,0 +1,226 @@
+package graph
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"restServer/taskstore"
+	"testing"
+	"time"
+)
+
+func TestCursor(t *testing.T) {
+	for _, id := range []string{"1", "42", "a:b"} {
+		got, err := decodeCursor(encodeCursor(id))
+		if err != nil || got != id {
+			t.Errorf("decodeCursor(encodeCursor(%q)) = %q, %v", id, got, err)
+		}
+	}
+
+	invalid := []struct {
+		name   string
+		cursor string
+	}{
+		{"not base64", "!!"},
+		{"no prefix", base64.StdEncoding.EncodeToString([]byte("1"))},
+		{"global id", globalID(nodeTask, "1")},
+		{"empty id", base64.StdEncoding.EncodeToString([]byte(cursorPrefix))},
+		{"offset cursor", base64.StdEncoding.EncodeToString([]byte("offset:3"))},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := decodeCursor(tt.cursor); !errors.Is(err, errInvalidCursor) {
+				t.Errorf("decodeCursor(%q) error = %v, want %v", tt.cursor, err, errInvalidCursor)
+			}
+		})
+	}
+}
+
+func TestConnectionBounds(t *testing.T) {
+	ids := []string{"1", "2", "4", "5", "8", "10"}
+	cursor := func(id string) *string {
+		c := encodeCursor(id)
+		return &c
+	}
+	bad := "!!"
+
+	tests := []struct {
+		name          string
+		first, last   *int32
+		after, before *string
+		size          int
+		start, end    int
+		wantErr       bool
+	}{
+		{name: "all", size: 100, start: 0, end: 6},
+		{name: "default size", size: 2, start: 0, end: 2},
+		{name: "first", first: int32Ptr(2), size: 100, start: 0, end: 2},
+		{name: "first zero", first: int32Ptr(0), size: 3, start: 0, end: 0},
+		{name: "first zero after", first: int32Ptr(0), after: cursor("2"), size: 3, start: 2, end: 2},
+		{name: "last zero", last: int32Ptr(0), size: 3, start: 6, end: 6},
+		{name: "first above the maximum", first: int32Ptr(5000), size: 100, start: 0, end: 6},
+		{name: "after", after: cursor("2"), size: 100, start: 2, end: 6},
+		{name: "after a deleted id", after: cursor("3"), size: 100, start: 2, end: 6},
+		{name: "after ids are compared as numbers", after: cursor("9"), size: 100, start: 5, end: 6},
+		{name: "after the last", after: cursor("10"), size: 100, start: 6, end: 6},
+		{name: "before", before: cursor("5"), size: 100, start: 0, end: 3},
+		{name: "before a deleted id", before: cursor("6"), size: 100, start: 0, end: 4},
+		{name: "after and before", first: int32Ptr(1), after: cursor("1"), before: cursor("8"), size: 100, start: 1, end: 2},
+		{name: "before ahead of after", after: cursor("8"), before: cursor("2"), size: 100, start: 5, end: 5},
+		{name: "last", last: int32Ptr(2), size: 100, start: 4, end: 6},
+		{name: "last before", last: int32Ptr(2), before: cursor("8"), size: 100, start: 2, end: 4},
+		{name: "negative first", first: int32Ptr(-1), size: 100, wantErr: true},
+		{name: "negative last", last: int32Ptr(-1), size: 100, wantErr: true},
+		{name: "invalid after", after: &bad, size: 100, wantErr: true},
+		{name: "invalid before", before: &bad, size: 100, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, err := connectionBounds(ids, tt.first, tt.last, tt.after, tt.before, tt.size)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("connectionBounds() error = %v, want error %v", err, tt.wantErr)
+			}
+			if err == nil && (start != tt.start || end != tt.end) {
+				t.Errorf("connectionBounds() = [%d, %d), want [%d, %d)", start, end, tt.start, tt.end)
+			}
+		})
+	}
+}
+
+// Recorre tasks de dos en dos borrando tareas entre paginas: ninguna de las que
+// quedan se repite ni se salta
+func TestTasksPagination(t *testing.T) {
+	ctx := context.Background()
+	store := taskstore.New()
+	var ids []string
+	for i := range 7 {
+		id, err := store.CreateTask(ctx, fmt.Sprint("task ", i), nil, time.Now(), nil, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+	}
+	h := newTestHandler(t, store)
+
+	// Antes de la segunda pagina se borran la tarea del cursor y la siguiente
+	deletes := map[int][]string{1: {ids[1], ids[2]}}
+	const query = `query($after: String) { tasks(first: 2, after: $after) { edges { node { databaseId } } pageInfo { hasNextPage endCursor } } }`
+
+	var seen []string
+	var after any
+	for page := 0; ; page++ {
+		for _, id := range deletes[page] {
+			if err := store.DeleteTask(ctx, id); err != nil {
+				t.Fatal(err)
+			}
+		}
+		var data struct {
+			Tasks struct {
+				Edges []struct {
+					Node struct{ DatabaseID string }
+				}
+				PageInfo struct {
+					HasNextPage bool
+					EndCursor   *string
+				}
+			}
+		}
+		decode(t, post(t, h, map[string]any{"query": query, "variables": map[string]any{"after": after}}), &data)
+		for _, e := range data.Tasks.Edges {
+			seen = append(seen, e.Node.DatabaseID)
+		}
+		if !data.Tasks.PageInfo.HasNextPage {
+			break
+		}
+		after = *data.Tasks.PageInfo.EndCursor
+	}
+
+	want := []string{ids[0], ids[1], ids[3], ids[4], ids[5], ids[6]}
+	if fmt.Sprint(seen) != fmt.Sprint(want) {
+		t.Errorf("pages = %v, want %v", seen, want)
+	}
+}
+
+// first: 0 y last: 0 dan una pagina vacia con pageInfo valido
+func TestEmptyPage(t *testing.T) {
+	store := taskstore.New()
+	for i := range 3 {
+		if _, err := store.CreateTask(context.Background(), fmt.Sprint("task ", i), nil, time.Now(), nil, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	h := newTestHandler(t, store)
+
+	tests := []struct {
+		query          string
+		next, previous bool
+	}{
+		{`{ tasks(first: 0) { edges { cursor } pageInfo { hasNextPage hasPreviousPage startCursor endCursor } totalCount } }`, true, false},
+		{`{ tasks(last: 0) { edges { cursor } pageInfo { hasNextPage hasPreviousPage startCursor endCursor } totalCount } }`, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			var data struct {
+				Tasks struct {
+					Edges    []struct{ Cursor string }
+					PageInfo struct {
+						HasNextPage, HasPreviousPage bool
+						StartCursor, EndCursor       *string
+					}
+					TotalCount int
+				}
+			}
+			decode(t, post(t, h, map[string]any{"query": tt.query}), &data)
+			page := data.Tasks
+			if len(page.Edges) != 0 || page.TotalCount != 3 {
+				t.Errorf("got %d edges of %d, want 0 of 3", len(page.Edges), page.TotalCount)
+			}
+			if page.PageInfo.HasNextPage != tt.next || page.PageInfo.HasPreviousPage != tt.previous {
+				t.Errorf("pageInfo = %+v", page.PageInfo)
+			}
+			if page.PageInfo.StartCursor != nil || page.PageInfo.EndCursor != nil {
+				t.Errorf("cursors = %v, %v, want null", page.PageInfo.StartCursor, page.PageInfo.EndCursor)
+			}
+		})
+	}
+}
+
+func TestNodes(t *testing.T) {
+	store := taskstore.New()
+	id, err := store.CreateTask(context.Background(), "task", nil, time.Now(), nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := newTestHandler(t, store)
+
+	tests := []struct {
+		name      string
+		ids       []string
+		want      string
+		wantPaths []string
+	}{
+		{"existing", []string{globalID(nodeTask, id)}, `{"nodes":[{"databaseId":"` + id + `"}]}`, nil},
+		{"missing is null", []string{globalID(nodeTask, "404")}, `{"nodes":[null]}`, nil},
+		{"malformed is null with an error", []string{"!!", globalID(nodeTask, id), "Zm9v"}, `{"nodes":[null,{"databaseId":"` + id + `"},null]}`, []string{"[nodes 0]", "[nodes 2]"}},
+		{"unknown type", []string{globalID("Other", "1")}, `{"nodes":[null]}`, []string{"[nodes 0]"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := post(t, h, map[string]any{
+				"query":     `query($ids: [ID!]!) { nodes(ids: $ids) { ... on Task { databaseId } } }`,
+				"variables": map[string]any{"ids": tt.ids},
+			})
+			if string(resp.Data) != tt.want {
+				t.Errorf("data = %s, want %s", resp.Data, tt.want)
+			}
+			var paths []string
+			for _, e := range resp.Errors {
+				paths = append(paths, fmt.Sprint(e["path"]))
+			}
+			if fmt.Sprint(paths) != fmt.Sprint(tt.wantPaths) {
+				t.Errorf("error paths = %v, want %v", paths, tt.wantPaths)
+			}
+		})
+	}
+}
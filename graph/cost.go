@@ -0,0 +1,234 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"strings"
+)
+
+const costExtension = "Cost"
+
+// Costos por defecto de los campos sin @cost
+const (
+	defaultObjectCost   = 1
+	defaultMutationCost = 10
+	// Tamano supuesto de una lista sin @listSize
+	defaultListSize = 10
+)
+
+// Costo y profundidad calculados de una operacion. Se devuelven en
+// extensions.cost de la respuesta.
+type CostStats struct {
+	Cost     int `json:"requested"`
+	MaxCost  int `json:"limit,omitempty"`
+	Depth    int `json:"depth"`
+	MaxDepth int `json:"maxDepth,omitempty"`
+}
+
+// Extension de gqlgen que rechaza operaciones demasiado costosas o profundas antes
+// de ejecutarlas. El costo sale de las directivas @cost y @listSize del schema y de
+// los argumentos de paginacion de cada consulta. 0 desactiva cada limite.
+type CostLimit struct {
+	MaxCost  int
+	MaxDepth int
+
+	schema *ast.Schema
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationContextMutator
+	graphql.ResponseInterceptor
+} = &CostLimit{}
+
+func (l *CostLimit) ExtensionName() string {
+	return costExtension
+}
+
+func (l *CostLimit) Validate(schema graphql.ExecutableSchema) error {
+	l.schema = schema.Schema()
+	return nil
+}
+
+func (l *CostLimit) MutateOperationContext(ctx context.Context, opCtx *graphql.OperationContext) *gqlerror.Error {
+	stats := Cost(l.schema, opCtx.Doc, opCtx.Operation, opCtx.Variables)
+	stats.MaxCost, stats.MaxDepth = l.MaxCost, l.MaxDepth
+	opCtx.Stats.SetExtension(costExtension, stats)
+
+	if l.MaxDepth > 0 && stats.Depth > l.MaxDepth {
+		return costError("query_too_deep",
+			fmt.Sprintf("operation has depth %d, which exceeds the limit of %d", stats.Depth, l.MaxDepth), stats)
+	}
+	if l.MaxCost > 0 && stats.Cost > l.MaxCost {
+		return costError("query_too_complex",
+			fmt.Sprintf("operation has cost %d, which exceeds the limit of %d", stats.Cost, l.MaxCost), stats)
+	}
+	return nil
+}
+
+func costError(code, message string, stats CostStats) *gqlerror.Error {
+	return &gqlerror.Error{
+		Message:    message,
+		Extensions: map[string]any{"code": code, "cost": stats},
+	}
+}
+
+func (l *CostLimit) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	resp := next(ctx)
+	if resp == nil || !graphql.HasOperationContext(ctx) {
+		return resp
+	}
+	if stats, ok := graphql.GetOperationContext(ctx).Stats.GetExtension(costExtension).(CostStats); ok {
+		if resp.Extensions == nil {
+			resp.Extensions = map[string]any{}
+		}
+		resp.Extensions["cost"] = stats
+	}
+	return resp
+}
+
+// Costo y profundidad de una operacion ya validada (sin los limites)
+func Cost(schema *ast.Schema, doc *ast.QueryDocument, op *ast.OperationDefinition, vars map[string]any) CostStats {
+	if op == nil {
+		return CostStats{}
+	}
+	w := costWalker{doc: doc, vars: vars, mutation: op.Operation == ast.Mutation}
+	cost := w.selectionSet(op.SelectionSet, nil, 1, true)
+	return CostStats{Cost: cost, Depth: w.depth}
+}
+
+type costWalker struct {
+	doc      *ast.QueryDocument
+	vars     map[string]any
+	mutation bool
+	depth    int
+}
+
+// sized son los campos de este nivel a los que el padre asigna el tamano de lista
+func (w *costWalker) selectionSet(set ast.SelectionSet, sized map[string]int, depth int, root bool) int {
+	cost := 0
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *ast.Field:
+			cost += w.field(sel, sized, depth, root)
+		case *ast.InlineFragment:
+			cost += w.selectionSet(sel.SelectionSet, sized, depth, root)
+		case *ast.FragmentSpread:
+			if def := w.doc.Fragments.ForName(sel.Name); def != nil {
+				cost += w.selectionSet(def.SelectionSet, sized, depth, root)
+			}
+		}
+	}
+	return cost
+}
+
+func (w *costWalker) field(f *ast.Field, sized map[string]int, depth int, root bool) int {
+	// La introspeccion (playground, clientes) no cuenta para costo ni profundidad
+	if strings.HasPrefix(f.Name, "__") || f.Definition == nil {
+		return 0
+	}
+	w.depth = max(w.depth, depth)
+
+	def := f.Definition
+	weight := 0
+	if len(f.SelectionSet) > 0 {
+		weight = defaultObjectCost
+	}
+	if root && w.mutation {
+		weight = defaultMutationCost
+	}
+	if d := def.Directives.ForName("cost"); d != nil {
+		weight = intArgument(d, "weight", weight)
+	}
+
+	size, hasSize := sized[f.Name]
+	if !hasSize {
+		size = 1
+	}
+	var childSized map[string]int
+	if d := def.Directives.ForName("listSize"); d != nil {
+		n := w.listSize(f, d)
+		if fields := stringsArgument(d, "sizedFields"); len(fields) > 0 {
+			childSized = make(map[string]int, len(fields))
+			for _, name := range fields {
+				childSized[name] = n
+			}
+		} else if !hasSize {
+			size = n
+		}
+	} else if def.Type.Elem != nil && !hasSize {
+		size = defaultListSize
+	}
+
+	child := w.selectionSet(f.SelectionSet, childSized, depth+1, false)
+	return size * (weight + child)
+}
+
+// Tamano de la lista segun los argumentos de paginacion de la consulta. Un
+// argumento con puntos ("input.tasks") es un campo de un input.
+func (w *costWalker) listSize(f *ast.Field, d *ast.Directive) int {
+	args := f.ArgumentMap(w.vars)
+	for _, name := range stringsArgument(d, "slicingArguments") {
+		switch v := argumentPath(args, name).(type) {
+		case int64:
+			if v > 0 {
+				return int(v)
+			}
+		case int:
+			if v > 0 {
+				return v
+			}
+		case int32:
+			if v > 0 {
+				return int(v)
+			}
+		case []any:
+			return len(v)
+		}
+	}
+	return intArgument(d, "assumedSize", defaultListSize)
+}
+
+// Valor de un argumento o de un campo de un input ("input.tasks"), nil si no viene
+func argumentPath(args map[string]any, path string) any {
+	var v any = args
+	for _, key := range strings.Split(path, ".") {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil
+		}
+		v = m[key]
+	}
+	return v
+}
+
+func intArgument(d *ast.Directive, name string, fallback int) int {
+	arg := d.Arguments.ForName(name)
+	if arg == nil {
+		return fallback
+	}
+	v, err := arg.Value.Value(nil)
+	if n, ok := v.(int64); ok && err == nil {
+		return int(n)
+	}
+	return fallback
+}
+
+func stringsArgument(d *ast.Directive, name string) []string {
+	arg := d.Arguments.ForName(name)
+	if arg == nil {
+		return nil
+	}
+	v, _ := arg.Value.Value(nil)
+	list, _ := v.([]any)
+	out := make([]string, 0, len(list))
+	for _, item := range list {
+		if s, ok := item.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
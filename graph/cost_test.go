@@ -0,0 +1,157 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"github.com/vektah/gqlparser/v2"
+	"restServer/taskstore"
+	"testing"
+	"time"
+)
+
+func TestCost(t *testing.T) {
+	schema := NewExecutableSchema(Config{Resolvers: &Resolver{}}).Schema()
+
+	tests := []struct {
+		name  string
+		query string
+		vars  map[string]any
+		want  CostStats
+	}{
+		{"object", `{ task(id: "1") { text } }`, nil, CostStats{Cost: 1, Depth: 2}},
+		{"sized fields use first", `{ tasks(first: 5) { edges { node { text } } } }`, nil, CostStats{Cost: 11, Depth: 4}},
+		{"sized fields use last from variables", `query($n: Int) { tasks(last: $n) { edges { node { text } } } }`, map[string]any{"n": 3}, CostStats{Cost: 7, Depth: 4}},
+		{"assumed size without arguments", `{ tasks { edges { node { text } } } }`, nil, CostStats{Cost: 201, Depth: 4}},
+		{"assumed size for zero", `{ tasks(first: 0) { edges { node { text } } } }`, nil, CostStats{Cost: 201, Depth: 4}},
+		{"list sliced by limit", `{ projects(limit: 20) { name } }`, nil, CostStats{Cost: 20, Depth: 2}},
+		{"list sized by its argument", `{ nodes(ids: ["a", "b", "c"]) { id } }`, nil, CostStats{Cost: 3, Depth: 2}},
+		{"list without listSize", `{ task(id: "1") { tags } }`, nil, CostStats{Cost: 1, Depth: 2}},
+		{"nested connections multiply", `{ tasks(first: 2) { edges { node { comments(first: 3) { edges { node { body } } } } } } }`, nil, CostStats{Cost: 19, Depth: 7}},
+		{"fragments", `{ task(id: "1") { ...f } } fragment f on Task { project { name } }`, nil, CostStats{Cost: 2, Depth: 3}},
+		{"mutation sized by an input field", `mutation { addTasks(input: {tasks: [{text: "a", due: "2024-01-01T00:00:00Z"}, {text: "b", due: "2024-01-01T00:00:00Z"}]}) { tasks { id } } }`, nil, CostStats{Cost: 40, Depth: 3}},
+		{"introspection is free", `{ __schema { types { name } } }`, nil, CostStats{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := gqlparser.LoadQuery(schema, tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := Cost(schema, doc, doc.Operations[0], tt.vars); got != tt.want {
+				t.Errorf("Cost() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCostLimit(t *testing.T) {
+	const query = `{ tasks(first: 5) { edges { node { text } } } }` // costo 11, profundidad 4
+
+	tests := []struct {
+		name     string
+		limit    CostLimit
+		wantCode string
+	}{
+		{"no limits", CostLimit{}, ""},
+		{"within limits", CostLimit{MaxCost: 11, MaxDepth: 4}, ""},
+		{"too complex", CostLimit{MaxCost: 10}, "query_too_complex"},
+		{"too deep", CostLimit{MaxDepth: 3}, "query_too_deep"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler(t, taskstore.New(), &tt.limit)
+			resp := post(t, h, map[string]any{"query": query})
+
+			if code := errorCode(resp); code != tt.wantCode {
+				t.Errorf("error code = %q, want %q", code, tt.wantCode)
+			}
+			if cost, _ := resp.Extensions["cost"].(map[string]any); cost["requested"] != float64(11) {
+				t.Errorf("extensions.cost = %v", resp.Extensions["cost"])
+			}
+		})
+	}
+}
+
+func TestPageSize(t *testing.T) {
+	tests := []struct {
+		name      string
+		requested *int32
+		want      int
+	}{
+		{"default", nil, defaultPageSize},
+		{"zero", int32Ptr(0), defaultPageSize},
+		{"negative", int32Ptr(-1), defaultPageSize},
+		{"requested", int32Ptr(20), 20},
+		{"capped", int32Ptr(5000), maxPageSize},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pageSize(tt.requested, defaultPageSize); got != tt.want {
+				t.Errorf("pageSize() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// Las listas sin limite devuelven a lo sumo la pagina por defecto, el tamano que
+// supone @listSize
+func TestDefaultPageSize(t *testing.T) {
+	store := taskstore.New()
+	for i := range defaultPageSize + 1 {
+		store.CreateProject(context.Background(), fmt.Sprint("project ", i), "")
+	}
+	h := newTestHandler(t, store)
+
+	tests := []struct {
+		name  string
+		query string
+		want  int
+	}{
+		{"default", `{ projects { id } }`, defaultPageSize},
+		{"limit", `{ projects(limit: 3) { id } }`, 3},
+		{"above default", `{ projects(limit: 500) { id } }`, defaultPageSize + 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var data struct {
+				Projects []struct{ ID string }
+			}
+			decode(t, post(t, h, map[string]any{"query": tt.query}), &data)
+			if len(data.Projects) != tt.want {
+				t.Errorf("got %d projects, want %d", len(data.Projects), tt.want)
+			}
+		})
+	}
+}
+
+// Las listas sin paginacion se cortan en su assumedSize
+func TestUnpaginatedLists(t *testing.T) {
+	store := taskstore.New()
+	due := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+	for i := range maxAllTasks + 1 {
+		if _, err := store.CreateTask(context.Background(), fmt.Sprint("task ", i), []string{"x"}, due, nil, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	h := newTestHandler(t, store)
+
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{`{ getAllTasks { id } }`, maxAllTasks},
+		{`{ getTasksByTag(tag: "x") { id } }`, maxFilteredTasks},
+		{`{ getTasksByDue(due: "2026-01-02T00:00:00Z") { id } }`, maxFilteredTasks},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			var data map[string][]struct{ ID string }
+			decode(t, post(t, h, map[string]any{"query": tt.query}), &data)
+			for _, list := range data {
+				if len(list) != tt.want {
+					t.Errorf("got %d tasks, want %d", len(list), tt.want)
+				}
+			}
+		})
+	}
+}
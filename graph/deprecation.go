@@ -0,0 +1,190 @@
+package graph
+
+import (
+	"context"
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"restServer/logging"
+	"restServer/metrics"
+	"sync"
+)
+
+// Cabecera con el nombre del cliente, la misma que usan Apollo Client y Apollo Studio
+const clientNameHeader = "Apollographql-Client-Name"
+
+// Limite de nombres de cliente distintos; el cliente elige la cabecera
+const maxClientNames = 100
+
+// Extension de gqlgen que registra el uso de campos, argumentos, valores de enum y
+// campos de input marcados con @deprecated, por cliente. Cada par (cliente, campo)
+// se loguea la primera vez que aparece; con un registro de metricas ademas se
+// cuenta en graphql_deprecated_fields_total para saber cuando migraron todos.
+type Deprecations struct {
+	used *metrics.CounterVec
+
+	mu      sync.Mutex
+	clients map[string]bool
+	seen    map[[2]string]bool
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationContextMutator
+} = &Deprecations{}
+
+// reg puede ser nil, entonces solo se loguea
+func NewDeprecations(reg *metrics.Registry) *Deprecations {
+	d := &Deprecations{
+		clients: make(map[string]bool),
+		seen:    make(map[[2]string]bool),
+	}
+	if reg != nil {
+		d.used = reg.NewCounterVec("graphql_deprecated_fields_total",
+			"Uses of deprecated GraphQL fields, arguments and enum values by client.",
+			"field", "client")
+	}
+	return d
+}
+
+func (d *Deprecations) ExtensionName() string {
+	return "Deprecations"
+}
+
+func (d *Deprecations) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (d *Deprecations) MutateOperationContext(ctx context.Context, opCtx *graphql.OperationContext) *gqlerror.Error {
+	if opCtx.Operation == nil {
+		return nil
+	}
+	fields := DeprecatedUsage(opCtx.Operation)
+	if len(fields) == 0 {
+		return nil
+	}
+
+	client := d.clientName(opCtx.Headers.Get(clientNameHeader))
+	for _, field := range fields {
+		if d.used != nil {
+			d.used.Inc(field, client)
+		}
+		if d.firstUse(client, field) {
+			logging.FromContext(ctx).Info("deprecated GraphQL field used", "field", field, "client", client)
+		}
+	}
+	return nil
+}
+
+// Nombre del cliente para las etiquetas; "unknown" sin cabecera, "other" pasado el limite
+func (d *Deprecations) clientName(name string) string {
+	if name == "" {
+		return "unknown"
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.clients[name] {
+		if len(d.clients) >= maxClientNames {
+			return "other"
+		}
+		d.clients[name] = true
+	}
+	return name
+}
+
+func (d *Deprecations) firstUse(client, field string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	key := [2]string{client, field}
+	if d.seen[key] {
+		return false
+	}
+	d.seen[key] = true
+	return true
+}
+
+// Elementos deprecados que usa una operacion validada, sin repetir y en orden de
+// aparicion: "Task.Text" para campos y campos de input, "Query.tasks(tag:)" para
+// argumentos y "DeleteProjectMode.REJECT" para valores de enum. Los valores que
+// llegan en variables no se revisan.
+func DeprecatedUsage(op *ast.OperationDefinition) []string {
+	w := deprecationWalker{seen: make(map[string]bool), fragments: make(map[string]bool)}
+	w.selectionSet(op.SelectionSet)
+	return w.found
+}
+
+type deprecationWalker struct {
+	found     []string
+	seen      map[string]bool
+	fragments map[string]bool
+}
+
+func (w *deprecationWalker) add(name string) {
+	if !w.seen[name] {
+		w.seen[name] = true
+		w.found = append(w.found, name)
+	}
+}
+
+func (w *deprecationWalker) selectionSet(set ast.SelectionSet) {
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *ast.Field:
+			w.field(sel)
+		case *ast.InlineFragment:
+			w.selectionSet(sel.SelectionSet)
+		case *ast.FragmentSpread:
+			if sel.Definition != nil && !w.fragments[sel.Name] {
+				w.fragments[sel.Name] = true
+				w.selectionSet(sel.Definition.SelectionSet)
+			}
+		}
+	}
+}
+
+func (w *deprecationWalker) field(f *ast.Field) {
+	def := f.Definition
+	if def == nil || f.ObjectDefinition == nil {
+		return
+	}
+	name := f.ObjectDefinition.Name + "." + def.Name
+	if isDeprecated(def.Directives) {
+		w.add(name)
+	}
+	for _, arg := range f.Arguments {
+		if argDef := def.Arguments.ForName(arg.Name); argDef != nil && isDeprecated(argDef.Directives) {
+			w.add(name + "(" + arg.Name + ":)")
+		}
+		w.value(arg.Value)
+	}
+	w.selectionSet(f.SelectionSet)
+}
+
+// Revisa valores de enum y campos de input en los argumentos literales
+func (w *deprecationWalker) value(v *ast.Value) {
+	if v == nil || v.Definition == nil {
+		return
+	}
+	switch v.Kind {
+	case ast.EnumValue:
+		if enum := v.Definition.EnumValues.ForName(v.Raw); enum != nil && isDeprecated(enum.Directives) {
+			w.add(v.Definition.Name + "." + v.Raw)
+		}
+	case ast.ObjectValue:
+		for _, child := range v.Children {
+			if field := v.Definition.Fields.ForName(child.Name); field != nil && isDeprecated(field.Directives) {
+				w.add(v.Definition.Name + "." + child.Name)
+			}
+			w.value(child.Value)
+		}
+	case ast.ListValue:
+		for _, child := range v.Children {
+			w.value(child.Value)
+		}
+	}
+}
+
+func isDeprecated(directives ast.DirectiveList) bool {
+	return directives.ForName("deprecated") != nil
+}
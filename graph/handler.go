@@ -0,0 +1,101 @@
+package graph
+
+import (
+	"fmt"
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/vektah/gqlparser/v2/ast"
+	"restServer/problem"
+	"restServer/taskstore"
+	"restServer/webhook"
+	"slices"
+	"time"
+)
+
+// Transportes HTTP de GraphQL
+const (
+	TransportGET       = "get"
+	TransportPOST      = "post"
+	TransportMultipart = "multipart"
+	TransportWebSocket = "websocket"
+	TransportSSE       = "sse"
+)
+
+// Transportes por defecto si no se indican
+var DefaultTransports = []string{TransportGET, TransportPOST}
+
+// Opciones del handler GraphQL. El valor cero da GET y POST, sin cache, sin APQ y
+// sin introspeccion.
+type HandlerOptions struct {
+	Store    *taskstore.TaskStore // compartido con REST
+	Webhooks *webhook.Dispatcher
+
+	Transports    []string // ver Transport*; vacio = DefaultTransports
+	QueryCache    int      // documentos parseados en cache; 0 = sin cache
+	APQCache      int      // consultas de APQ en cache; 0 desactiva APQ
+	Introspection bool
+
+	// Extensiones adicionales en orden de registro, antes de APQ (p.ej.
+	// PersistedQueries debe resolver los hashes antes que APQ)
+	Extensions []graphql.HandlerExtension
+}
+
+// Handler GraphQL con el schema ejecutable sobre el store indicado. Todos los
+// modos del servidor lo construyen aqui para no divergir en transportes,
+// caches ni extensiones.
+func NewHandler(opts HandlerOptions) (*handler.Server, error) {
+	srv := handler.New(NewExecutableSchema(Config{
+		Resolvers: &Resolver{Store: opts.Store, Webhooks: opts.Webhooks},
+	}))
+	srv.SetErrorPresenter(problem.GraphQLErrorPresenter)
+
+	transports := opts.Transports
+	if len(transports) == 0 {
+		transports = DefaultTransports
+	}
+	for _, name := range transports {
+		switch name {
+		case TransportGET, TransportPOST, TransportMultipart, TransportWebSocket, TransportSSE:
+		default:
+			return nil, fmt.Errorf("unknown graphql transport %q", name)
+		}
+	}
+
+	// El primer transporte que acepta la peticion la atiende: websocket y SSE van
+	// antes que GET y POST, que no miran Upgrade ni Accept
+	srv.AddTransport(transport.Options{})
+	for _, name := range []string{TransportWebSocket, TransportSSE, TransportGET, TransportPOST, TransportMultipart} {
+		if !slices.Contains(transports, name) {
+			continue
+		}
+		switch name {
+		case TransportWebSocket:
+			srv.AddTransport(transport.Websocket{KeepAlivePingInterval: 10 * time.Second})
+		case TransportSSE:
+			srv.AddTransport(transport.SSE{})
+		case TransportGET:
+			srv.AddTransport(transport.GET{})
+		case TransportPOST:
+			srv.AddTransport(transport.POST{})
+		case TransportMultipart:
+			srv.AddTransport(transport.MultipartForm{})
+		}
+	}
+
+	if opts.QueryCache > 0 {
+		srv.SetQueryCache(lru.New[*ast.QueryDocument](opts.QueryCache))
+	}
+	if opts.Introspection {
+		srv.Use(extension.Introspection{})
+	}
+	for _, ext := range opts.Extensions {
+		srv.Use(ext)
+	}
+	if opts.APQCache > 0 {
+		srv.Use(extension.AutomaticPersistedQuery{Cache: lru.New[string](opts.APQCache)})
+	}
+	return srv, nil
+}
@@ -0,0 +1,125 @@
+package graph
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"restServer/taskstore"
+	"strings"
+	"testing"
+)
+
+func TestNewHandlerTransports(t *testing.T) {
+	const query = `{ tasks { totalCount } }`
+
+	get := func() *http.Request {
+		return httptest.NewRequest(http.MethodGet, "/graphql?query="+url.QueryEscape(query), nil)
+	}
+	post := func() *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{"query": "`+query+`"}`))
+		req.Header.Set("Content-Type", "application/json")
+		return req
+	}
+	sse := func() *http.Request {
+		req := post()
+		req.Header.Set("Accept", "text/event-stream")
+		return req
+	}
+	form := func() *http.Request {
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		mw.WriteField("operations", `{"query": "`+query+`"}`)
+		mw.WriteField("map", `{}`)
+		mw.Close()
+		req := httptest.NewRequest(http.MethodPost, "/graphql", &body)
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		return req
+	}
+
+	tests := []struct {
+		name        string
+		transports  []string
+		req         func() *http.Request
+		wantStatus  int
+		contentType string
+	}{
+		{"default get", nil, get, http.StatusOK, "application/json"},
+		{"default post", nil, post, http.StatusOK, "application/json"},
+		{"default without multipart", nil, form, http.StatusBadRequest, ""},
+		{"post only", []string{TransportPOST}, get, http.StatusBadRequest, ""},
+		{"multipart", []string{TransportMultipart}, form, http.StatusOK, "application/json"},
+		{"post ignores event-stream", []string{TransportPOST}, sse, http.StatusOK, "application/graphql-response+json"},
+		{"sse before post", []string{TransportPOST, TransportSSE}, sse, http.StatusOK, "text/event-stream"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, err := NewHandler(HandlerOptions{Store: taskstore.New(), Transports: tt.transports})
+			if err != nil {
+				t.Fatal(err)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, tt.req())
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if got := rec.Header().Get("Content-Type"); tt.contentType != "" && !strings.HasPrefix(got, tt.contentType) {
+				t.Errorf("Content-Type = %q, want %q", got, tt.contentType)
+			}
+			if tt.wantStatus == http.StatusOK && !strings.Contains(rec.Body.String(), `"totalCount":0`) {
+				t.Errorf("body = %s", rec.Body)
+			}
+		})
+	}
+}
+
+func TestNewHandlerUnknownTransport(t *testing.T) {
+	_, err := NewHandler(HandlerOptions{Store: taskstore.New(), Transports: []string{TransportPOST, "grpc"}})
+	if err == nil || !strings.Contains(err.Error(), `"grpc"`) {
+		t.Errorf("NewHandler() error = %v, want unknown transport", err)
+	}
+}
+
+func TestNewHandlerIntrospection(t *testing.T) {
+	const query = `{ __schema { queryType { name } } }`
+
+	for _, enabled := range []bool{true, false} {
+		h, err := NewHandler(HandlerOptions{Store: taskstore.New(), Introspection: enabled})
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp := post(t, h, map[string]any{"query": query})
+		if got := len(resp.Errors) == 0; got != enabled {
+			t.Errorf("introspection %v: errors = %v", enabled, resp.Errors)
+		}
+	}
+}
+
+func TestNewHandlerAPQ(t *testing.T) {
+	// Solo el hash de una consulta que el servidor no conoce
+	params := map[string]any{"extensions": map[string]any{
+		"persistedQuery": map[string]any{"version": 1, "sha256Hash": "5b0bd8bc1ebd0a4ec5c27ab1fe20d27e8e1b8f3cd6ba5e3b3f4ef0f4c1c0b5b0"},
+	}}
+
+	tests := []struct {
+		name     string
+		apqCache int
+		want     string
+	}{
+		{"enabled", 10, "PERSISTED_QUERY_NOT_FOUND"},
+		{"disabled", 0, "GRAPHQL_VALIDATION_FAILED"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, err := NewHandler(HandlerOptions{Store: taskstore.New(), APQCache: tt.apqCache})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := errorCode(post(t, h, params)); got != tt.want {
+				t.Errorf("code = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package graph
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/99designs/gqlgen/graphql"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"restServer/taskstore"
+	"restServer/tracing"
+	"testing"
+)
+
+// Exportador en memoria del proveedor global. El tracer del paquete se enlaza con
+// el primer proveedor que se instala, por eso se instala una sola vez.
+var spans = tracetest.NewInMemoryExporter()
+
+func TestMain(m *testing.M) {
+	tracing.Install(sdktrace.NewSimpleSpanProcessor(spans), tracing.Options{ServiceName: "test", SampleRatio: 1})
+	os.Exit(m.Run())
+}
+
+// Respuesta GraphQL decodificada con los errores como mapas
+type response struct {
+	Data       json.RawMessage  `json:"data"`
+	Errors     []map[string]any `json:"errors"`
+	Extensions map[string]any   `json:"extensions"`
+}
+
+// extensions.code del primer error, "" sin errores
+func errorCode(resp response) string {
+	if len(resp.Errors) == 0 {
+		return ""
+	}
+	ext, _ := resp.Errors[0]["extensions"].(map[string]any)
+	code, _ := ext["code"].(string)
+	return code
+}
+
+// Decodifica data en v; falla si la respuesta trae errores
+func decode(t *testing.T, resp response, v any) {
+	t.Helper()
+	if len(resp.Errors) > 0 {
+		t.Fatalf("errors: %v", resp.Errors)
+	}
+	if err := json.Unmarshal(resp.Data, v); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func int32Ptr(n int32) *int32 {
+	return &n
+}
+
+// Handler sobre el store con solo el transporte POST y las extensiones indicadas
+func newTestHandler(t *testing.T, store *taskstore.TaskStore, extensions ...graphql.HandlerExtension) http.Handler {
+	t.Helper()
+	h, err := NewHandler(HandlerOptions{
+		Store:      store,
+		Transports: []string{TransportPOST},
+		Extensions: extensions,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+// POST de params (query, variables, extensions...) a h
+func post(t *testing.T, h http.Handler, params map[string]any) response {
+	t.Helper()
+	body, err := json.Marshal(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	var resp response
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decoding %q: %v", rec.Body.String(), err)
+	}
+	return resp
+}
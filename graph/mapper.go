@@ -0,0 +1,82 @@
+package graph
+
+import (
+	"restServer/graph/model"
+	"restServer/taskstore"
+)
+
+// Conversiones entre los tipos del dominio (taskstore) y los modelos de gqlgen
+// (graph/model, enlazados en gqlgen.yml). El schema puede cambiar sin tocar el
+// formato del store ni el de REST.
+
+func taskModel(task taskstore.Task) *model.Task {
+	return &model.Task{
+		ID:          task.ID,
+		Text:        task.Text,
+		Tags:        task.Tags,
+		Due:         task.Due,
+		Attachments: attachmentModels(task.Attachments),
+		ProjectID:   task.ProjectID,
+	}
+}
+
+func taskModels(tasks []taskstore.Task) []*model.Task {
+	result := make([]*model.Task, 0, len(tasks))
+	for _, task := range tasks {
+		result = append(result, taskModel(task))
+	}
+	return result
+}
+
+func attachmentModels(attachments []*taskstore.Attachment) []*model.Attachment {
+	if attachments == nil {
+		return nil
+	}
+	result := make([]*model.Attachment, 0, len(attachments))
+	for _, a := range attachments {
+		result = append(result, &model.Attachment{Name: a.Name, Date: a.Date, Contents: a.Contents})
+	}
+	return result
+}
+
+// Convierte los adjuntos de NewTask en adjuntos del dominio
+func newAttachments(input []*model.NewAttachment) []*taskstore.Attachment {
+	attachments := make([]*taskstore.Attachment, 0, len(input))
+	for _, a := range input {
+		attachments = append(attachments, &taskstore.Attachment{Name: a.Name, Date: a.Date, Contents: a.Contents})
+	}
+	return attachments
+}
+
+// Convierte los adjuntos de AddTaskInput en adjuntos del dominio
+func attachmentInputs(input []*model.AttachmentInput) []*taskstore.Attachment {
+	attachments := make([]*taskstore.Attachment, 0, len(input))
+	for _, a := range input {
+		attachments = append(attachments, &taskstore.Attachment{Name: a.Name, Date: a.Date, Contents: a.Contents})
+	}
+	return attachments
+}
+
+func projectModel(project taskstore.Project) *model.Project {
+	return &model.Project{
+		ID:          project.ID,
+		Name:        project.Name,
+		Description: project.Description,
+		TaskCount:   int32(project.TaskCount),
+	}
+}
+
+func commentModel(comment taskstore.Comment) *model.Comment {
+	return &model.Comment{
+		ID:        comment.ID,
+		TaskID:    comment.TaskID,
+		Author:    comment.Author,
+		Body:      comment.Body,
+		CreatedAt: comment.CreatedAt,
+		UpdatedAt: comment.UpdatedAt,
+	}
+}
+
+func reminderModel(config taskstore.ReminderConfig) *model.ReminderConfig {
+	return &model.ReminderConfig{Offsets: config.Offsets, Channels: config.Channels}
+}
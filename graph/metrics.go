@@ -0,0 +1,114 @@
+package graph
+
+import (
+	"context"
+	"restServer/metrics"
+	"sync"
+	"time"
+
+	"github.com/99designs/gqlgen/complexity"
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// Limite de nombres de operacion distintos; los clientes eligen el nombre y sin
+// limite cada nombre nuevo seria una serie nueva
+const maxOperationNames = 100
+
+const metricsExtension = "Metrics"
+
+// Extension de gqlgen con metricas por operacion: cantidad, latencia y complejidad
+type Metrics struct {
+	operations *metrics.CounterVec
+	duration   *metrics.HistogramVec
+	complexity *metrics.HistogramVec
+
+	es graphql.ExecutableSchema
+
+	mu    sync.Mutex
+	names map[string]bool
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationContextMutator
+	graphql.ResponseInterceptor
+} = &Metrics{}
+
+func NewMetrics(reg *metrics.Registry) *Metrics {
+	return &Metrics{
+		operations: reg.NewCounterVec("graphql_operations_total",
+			"Total number of GraphQL operations by name, type and result.",
+			"operation", "type", "result"),
+		duration: reg.NewHistogramVec("graphql_operation_duration_seconds",
+			"GraphQL operation latency by name and type.",
+			metrics.DefBuckets, "operation", "type"),
+		complexity: reg.NewHistogramVec("graphql_operation_complexity",
+			"Calculated complexity of GraphQL operations by name.",
+			[]float64{1, 5, 10, 25, 50, 100, 250, 500, 1000}, "operation"),
+		names: make(map[string]bool),
+	}
+}
+
+func (m *Metrics) ExtensionName() string {
+	return metricsExtension
+}
+
+func (m *Metrics) Validate(schema graphql.ExecutableSchema) error {
+	m.es = schema
+	return nil
+}
+
+func (m *Metrics) MutateOperationContext(ctx context.Context, opCtx *graphql.OperationContext) *gqlerror.Error {
+	// Con CostLimit instalado se usa su costo, el mismo que ve el cliente
+	value := complexity.Calculate(ctx, m.es, opCtx.Operation, opCtx.Variables)
+	if stats, ok := opCtx.Stats.GetExtension(costExtension).(CostStats); ok {
+		value = stats.Cost
+	}
+	opCtx.Stats.SetExtension(metricsExtension, value)
+	m.complexity.Observe(float64(value), m.operationName(opCtx))
+	return nil
+}
+
+func (m *Metrics) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	resp := next(ctx)
+	if !graphql.HasOperationContext(ctx) {
+		return resp
+	}
+
+	opCtx := graphql.GetOperationContext(ctx)
+	name := m.operationName(opCtx)
+	opType := "unknown"
+	if opCtx.Operation != nil {
+		opType = string(opCtx.Operation.Operation)
+	}
+	result := "ok"
+	if resp == nil || len(resp.Errors) > 0 {
+		result = "error"
+	}
+
+	m.operations.Inc(name, opType, result)
+	m.duration.Observe(time.Since(opCtx.Stats.OperationStart).Seconds(), name, opType)
+	return resp
+}
+
+// Nombre de la operacion para las etiquetas; "invalid" si no paso el parseo o la validacion
+func (m *Metrics) operationName(opCtx *graphql.OperationContext) string {
+	if opCtx.Operation == nil {
+		return "invalid"
+	}
+	name := opCtx.Operation.Name
+	if name == "" {
+		return "anonymous"
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.names[name] {
+		if len(m.names) >= maxOperationNames {
+			return "other"
+		}
+		m.names[name] = true
+	}
+	return name
+}
@@ -0,0 +1,55 @@
+package graph
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"restServer/metrics"
+	"restServer/taskstore"
+	"strings"
+	"testing"
+)
+
+func TestMetrics(t *testing.T) {
+	reg := metrics.NewRegistry()
+	h := newTestHandler(t, taskstore.New(), NewMetrics(reg))
+
+	post(t, h, map[string]any{"query": `query List { tasks(first: 5) { totalCount } }`})
+	post(t, h, map[string]any{"query": `{ __typename }`})
+	post(t, h, map[string]any{"query": `query Missing { task(id: "404") { text } }`})
+	post(t, h, map[string]any{"query": `query Broken { nope }`})
+
+	rec := httptest.NewRecorder()
+	reg.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := rec.Body.String()
+	for _, want := range []string{
+		`graphql_operations_total{operation="List",type="query",result="ok"} 1`,
+		`graphql_operations_total{operation="anonymous",type="query",result="ok"} 1`,
+		`graphql_operations_total{operation="Missing",type="query",result="error"} 1`,
+		`graphql_operations_total{operation="invalid",type="unknown",result="error"} 1`,
+		`graphql_operation_complexity_count{operation="List"} 1`,
+	} {
+		if !strings.Contains(body, want+"\n") {
+			t.Errorf("missing %s in\n%s", want, body)
+		}
+	}
+}
+
+// Los nombres de operacion los elige el cliente, a partir del limite van a "other"
+func TestMetricsOperationNames(t *testing.T) {
+	reg := metrics.NewRegistry()
+	h := newTestHandler(t, taskstore.New(), NewMetrics(reg))
+	for i := range maxOperationNames + 5 {
+		post(t, h, map[string]any{"query": fmt.Sprintf("query Op%d { __typename }", i)})
+	}
+
+	rec := httptest.NewRecorder()
+	reg.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := rec.Body.String()
+	if want := `graphql_operations_total{operation="other",type="query",result="ok"} 5`; !strings.Contains(body, want+"\n") {
+		t.Errorf("missing %s", want)
+	}
+	if n := strings.Count(body, "graphql_operations_total{"); n != maxOperationNames+1 {
+		t.Errorf("%d operation series, want %d", n, maxOperationNames+1)
+	}
+}
@@ -0,0 +1,66 @@
+package model
+
+import "time"
+
+// Modelos de los tipos con Node o con campos deprecados: el esquema expone cada
+// campo en camelCase y en PascalCase, y gqlgen enlaza ambos nombres al mismo
+// campo de Go. Solo los usa GraphQL: REST tiene sus DTOs en server y el store sus
+// propios tipos (Task, Project, Comment, ReminderConfig), las conversiones estan
+// en graph/mapper.go.
+
+type Attachment struct {
+	Name     string    `json:"Name"`
+	Date     time.Time `json:"Date"`
+	Contents string    `json:"Contents"`
+}
+
+type Comment struct {
+	ID        string    `json:"ID"`
+	TaskID    string    `json:"TaskId"`
+	Author    string    `json:"Author"`
+	Body      string    `json:"Body"`
+	CreatedAt time.Time `json:"CreatedAt"`
+	UpdatedAt time.Time `json:"UpdatedAt"`
+}
+
+func (Comment) IsNode()            {}
+func (this Comment) GetID() string { return this.ID }
+
+type Project struct {
+	ID          string `json:"ID"`
+	Name        string `json:"Name"`
+	Description string `json:"Description"`
+	TaskCount   int32  `json:"TaskCount"`
+}
+
+func (Project) IsNode()            {}
+func (this Project) GetID() string { return this.ID }
+
+type ReminderConfig struct {
+	Offsets  []string `json:"Offsets"`
+	Channels []string `json:"Channels"`
+}
+
+type Task struct {
+	ID          string        `json:"ID"`
+	Text        string        `json:"Text"`
+	Tags        []string      `json:"Tags,omitempty"`
+	Due         time.Time     `json:"Due"`
+	Attachments []*Attachment `json:"Attachments,omitempty"`
+	ProjectID   *string       `json:"ProjectId,omitempty"`
+}
+
+func (Task) IsNode()            {}
+func (this Task) GetID() string { return this.ID }
+
+type Webhook struct {
+	ID        string    `json:"Id"`
+	URL       string    `json:"Url"`
+	Events    []string  `json:"Events"`
+	Active    bool      `json:"Active"`
+	CreatedAt time.Time `json:"CreatedAt"`
+	Secret    *string   `json:"Secret,omitempty"`
+}
+
+func (Webhook) IsNode()            {}
+func (this Webhook) GetID() string { return this.ID }
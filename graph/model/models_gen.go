@@ -3,13 +3,130 @@
 package model
 
 import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
 	"time"
 )
 
-type Attachment struct {
-	Name     string    `json:"Name"`
-	Date     time.Time `json:"Date"`
-	Contents string    `json:"Contents"`
+type Node interface {
+	IsNode()
+	GetID() string
+}
+
+type AddProjectInput struct {
+	ClientMutationID *string `json:"clientMutationId,omitempty"`
+	Name             string  `json:"name"`
+	Description      *string `json:"description,omitempty"`
+}
+
+type AddProjectPayload struct {
+	ClientMutationID *string  `json:"clientMutationId,omitempty"`
+	Project          *Project `json:"project"`
+}
+
+type AddTaskCommentInput struct {
+	ClientMutationID *string `json:"clientMutationId,omitempty"`
+	TaskID           string  `json:"taskId"`
+	Author           string  `json:"author"`
+	Body             string  `json:"body"`
+}
+
+type AddTaskCommentPayload struct {
+	ClientMutationID *string  `json:"clientMutationId,omitempty"`
+	Comment          *Comment `json:"comment"`
+	Task             *Task    `json:"task"`
+}
+
+type AddTaskInput struct {
+	ClientMutationID *string            `json:"clientMutationId,omitempty"`
+	Text             string             `json:"text"`
+	Tags             []string           `json:"tags,omitempty"`
+	Due              time.Time          `json:"due"`
+	Attachments      []*AttachmentInput `json:"attachments,omitempty"`
+	ProjectID        *string            `json:"projectId,omitempty"`
+}
+
+type AddTaskPayload struct {
+	ClientMutationID *string `json:"clientMutationId,omitempty"`
+	Task             *Task   `json:"task"`
+}
+
+type AddTasksInput struct {
+	ClientMutationID *string      `json:"clientMutationId,omitempty"`
+	Tasks            []*TaskInput `json:"tasks"`
+}
+
+type AddTasksPayload struct {
+	ClientMutationID *string `json:"clientMutationId,omitempty"`
+	Tasks            []*Task `json:"tasks"`
+}
+
+type AddWebhookInput struct {
+	ClientMutationID *string  `json:"clientMutationId,omitempty"`
+	URL              string   `json:"url"`
+	Events           []string `json:"events,omitempty"`
+	Secret           *string  `json:"secret,omitempty"`
+}
+
+type AddWebhookPayload struct {
+	ClientMutationID *string  `json:"clientMutationId,omitempty"`
+	Webhook          *Webhook `json:"webhook"`
+}
+
+type AttachmentInput struct {
+	Name     string    `json:"name"`
+	Date     time.Time `json:"date"`
+	Contents string    `json:"contents"`
+}
+
+type CommentConnection struct {
+	Edges      []*CommentEdge `json:"edges"`
+	PageInfo   *PageInfo      `json:"pageInfo"`
+	TotalCount int32          `json:"totalCount"`
+}
+
+type CommentEdge struct {
+	Cursor string   `json:"cursor"`
+	Node   *Comment `json:"node"`
+}
+
+type EditCommentInput struct {
+	ClientMutationID *string `json:"clientMutationId,omitempty"`
+	ID               string  `json:"id"`
+	Body             string  `json:"body"`
+}
+
+type EditCommentPayload struct {
+	ClientMutationID *string  `json:"clientMutationId,omitempty"`
+	Comment          *Comment `json:"comment"`
+}
+
+type EditProjectInput struct {
+	ClientMutationID *string `json:"clientMutationId,omitempty"`
+	ID               string  `json:"id"`
+	Name             string  `json:"name"`
+	Description      *string `json:"description,omitempty"`
+}
+
+type EditProjectPayload struct {
+	ClientMutationID *string  `json:"clientMutationId,omitempty"`
+	Project          *Project `json:"project"`
+}
+
+type EditWebhookInput struct {
+	ClientMutationID *string  `json:"clientMutationId,omitempty"`
+	ID               string   `json:"id"`
+	URL              string   `json:"url"`
+	Events           []string `json:"events,omitempty"`
+	Active           bool     `json:"active"`
+	Secret           *string  `json:"secret,omitempty"`
+}
+
+type EditWebhookPayload struct {
+	ClientMutationID *string  `json:"clientMutationId,omitempty"`
+	Webhook          *Webhook `json:"webhook"`
 }
 
 type Mutation struct {
@@ -21,21 +138,183 @@ type NewAttachment struct {
 	Contents string    `json:"Contents"`
 }
 
+type NewComment struct {
+	Author string `json:"Author"`
+	Body   string `json:"Body"`
+}
+
+type NewProject struct {
+	Name        string  `json:"Name"`
+	Description *string `json:"Description,omitempty"`
+}
+
 type NewTask struct {
 	Text        string           `json:"Text"`
 	Tags        []string         `json:"Tags,omitempty"`
 	Due         time.Time        `json:"Due"`
 	Attachments []*NewAttachment `json:"Attachments,omitempty"`
+	ProjectID   *string          `json:"ProjectId,omitempty"`
+}
+
+type NewWebhook struct {
+	URL    string   `json:"Url"`
+	Events []string `json:"Events,omitempty"`
+	Secret *string  `json:"Secret,omitempty"`
+}
+
+type PageInfo struct {
+	HasNextPage     bool    `json:"hasNextPage"`
+	HasPreviousPage bool    `json:"hasPreviousPage"`
+	StartCursor     *string `json:"startCursor,omitempty"`
+	EndCursor       *string `json:"endCursor,omitempty"`
 }
 
 type Query struct {
-	
 }
 
-type Task struct {
-	ID          string        `json:"ID"`
-	Text        string        `json:"Text"`
-	Tags        []string      `json:"Tags,omitempty"`
-	Due         time.Time     `json:"Due"`
-	Attachments []*Attachment `json:"Attachments,omitempty"`
+type ReminderInput struct {
+	Offsets  []string `json:"Offsets"`
+	Channels []string `json:"Channels,omitempty"`
+}
+
+type RemoveAllTasksInput struct {
+	ClientMutationID *string `json:"clientMutationId,omitempty"`
+}
+
+type RemoveAllTasksPayload struct {
+	ClientMutationID *string `json:"clientMutationId,omitempty"`
+}
+
+type RemoveCommentInput struct {
+	ClientMutationID *string `json:"clientMutationId,omitempty"`
+	ID               string  `json:"id"`
+}
+
+type RemoveCommentPayload struct {
+	ClientMutationID *string `json:"clientMutationId,omitempty"`
+	DeletedID        string  `json:"deletedId"`
+}
+
+type RemoveProjectInput struct {
+	ClientMutationID *string            `json:"clientMutationId,omitempty"`
+	ID               string             `json:"id"`
+	Mode             *DeleteProjectMode `json:"mode,omitempty"`
+}
+
+type RemoveProjectPayload struct {
+	ClientMutationID *string `json:"clientMutationId,omitempty"`
+	DeletedID        string  `json:"deletedId"`
+}
+
+type RemoveTaskInput struct {
+	ClientMutationID *string `json:"clientMutationId,omitempty"`
+	ID               string  `json:"id"`
+}
+
+type RemoveTaskPayload struct {
+	ClientMutationID *string `json:"clientMutationId,omitempty"`
+	DeletedID        string  `json:"deletedId"`
+}
+
+type RemoveWebhookInput struct {
+	ClientMutationID *string `json:"clientMutationId,omitempty"`
+	ID               string  `json:"id"`
+}
+
+type RemoveWebhookPayload struct {
+	ClientMutationID *string `json:"clientMutationId,omitempty"`
+	DeletedID        string  `json:"deletedId"`
+}
+
+type SetTaskRemindersInput struct {
+	ClientMutationID *string  `json:"clientMutationId,omitempty"`
+	TaskID           string   `json:"taskId"`
+	Offsets          []string `json:"offsets"`
+	Channels         []string `json:"channels,omitempty"`
+}
+
+type SetTaskRemindersPayload struct {
+	ClientMutationID *string         `json:"clientMutationId,omitempty"`
+	Reminders        *ReminderConfig `json:"reminders"`
+}
+
+type TaskConnection struct {
+	Edges      []*TaskEdge `json:"edges"`
+	PageInfo   *PageInfo   `json:"pageInfo"`
+	TotalCount int32       `json:"totalCount"`
+}
+
+type TaskEdge struct {
+	Cursor string `json:"cursor"`
+	Node   *Task  `json:"node"`
+}
+
+type TaskInput struct {
+	Text        string             `json:"text"`
+	Tags        []string           `json:"tags,omitempty"`
+	Due         time.Time          `json:"due"`
+	Attachments []*AttachmentInput `json:"attachments,omitempty"`
+	ProjectID   *string            `json:"projectId,omitempty"`
+}
+
+type UpdateWebhook struct {
+	URL    string   `json:"Url"`
+	Events []string `json:"Events,omitempty"`
+	Active bool     `json:"Active"`
+	Secret *string  `json:"Secret,omitempty"`
+}
+
+type DeleteProjectMode string
+
+const (
+	DeleteProjectModeReject  DeleteProjectMode = "REJECT"
+	DeleteProjectModeCascade DeleteProjectMode = "CASCADE"
+)
+
+var AllDeleteProjectMode = []DeleteProjectMode{
+	DeleteProjectModeReject,
+	DeleteProjectModeCascade,
+}
+
+func (e DeleteProjectMode) IsValid() bool {
+	switch e {
+	case DeleteProjectModeReject, DeleteProjectModeCascade:
+		return true
+	}
+	return false
+}
+
+func (e DeleteProjectMode) String() string {
+	return string(e)
+}
+
+func (e *DeleteProjectMode) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = DeleteProjectMode(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid DeleteProjectMode", str)
+	}
+	return nil
+}
+
+func (e DeleteProjectMode) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *DeleteProjectMode) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e DeleteProjectMode) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
 }
@@ -0,0 +1,123 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"io"
+	"net/http"
+	"os"
+)
+
+// Manifest de consultas persistidas: sha256 (hex) del documento -> documento.
+// Se genera con el subcomando extract-queries, que guarda cada documento en forma
+// canonica (con sus fragmentos) y escribe aparte el hash de cada operacion por
+// nombre: los clientes envian ese hash, no el de su propio texto.
+type Manifest map[string]string
+
+// Hash de un documento. Con APQ el cliente calcula el de su texto; para el
+// manifest es el del documento canonico que escribio extract-queries
+func QueryHash(query string) string {
+	sum := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(sum[:])
+}
+
+// Lee un manifest y comprueba que cada hash corresponda a su documento
+func LoadManifest(path string) (Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	for hash, query := range m {
+		if QueryHash(query) != hash {
+			return nil, fmt.Errorf("%s: hash %s does not match its document", path, hash)
+		}
+	}
+	return m, nil
+}
+
+// Escribe el manifest como JSON con las claves ordenadas
+func (m Manifest) Write(w io.Writer) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(m); err != nil {
+		return err
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// Extension de gqlgen que resuelve los hashes del manifest. Con Enforce solo se
+// ejecutan las operaciones registradas, enviadas por hash o con el documento
+// completo; sin Enforce las demas siguen su curso (APQ o consulta normal).
+// Debe registrarse antes de AutomaticPersistedQuery.
+type PersistedQueries struct {
+	Manifest Manifest
+	Enforce  bool
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationParameterMutator
+} = PersistedQueries{}
+
+func (PersistedQueries) ExtensionName() string {
+	return "PersistedQueries"
+}
+
+func (PersistedQueries) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (p PersistedQueries) MutateOperationParameters(ctx context.Context, params *graphql.RawParams) *gqlerror.Error {
+	hash := persistedHash(params.Extensions)
+	if hash == "" {
+		if p.Enforce && params.Query != "" {
+			if _, ok := p.Manifest[QueryHash(params.Query)]; !ok {
+				return notPersisted()
+			}
+		}
+		return nil
+	}
+
+	query, ok := p.Manifest[hash]
+	switch {
+	case !ok && p.Enforce:
+		return notPersisted()
+	case !ok:
+		return nil
+	case params.Query == "":
+		params.Query = query
+	case QueryHash(params.Query) != hash:
+		return &gqlerror.Error{
+			Message:    "provided sha256Hash does not match the query",
+			Extensions: map[string]any{"code": "persisted_query_mismatch", "status": http.StatusBadRequest},
+		}
+	}
+	return nil
+}
+
+func notPersisted() *gqlerror.Error {
+	return &gqlerror.Error{
+		Message:    "operation is not in the persisted query manifest",
+		Extensions: map[string]any{"code": "persisted_query_not_allowed", "status": http.StatusForbidden},
+	}
+}
+
+// sha256Hash de extensions.persistedQuery, vacio si no viene
+func persistedHash(extensions map[string]any) string {
+	pq, _ := extensions["persistedQuery"].(map[string]any)
+	hash, _ := pq["sha256Hash"].(string)
+	return hash
+}
@@ -0,0 +1,94 @@
+package graph
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"restServer/taskstore"
+	"testing"
+)
+
+const persistedQuery = "query Name {\n  __typename\n}"
+
+func TestLoadManifest(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string // sin archivo si es vacio
+		wantErr bool
+	}{
+		{"valid", `{"` + QueryHash(persistedQuery) + `": "query Name {\n  __typename\n}"}`, false},
+		{"empty", `{}`, false},
+		{"hash mismatch", `{"` + QueryHash("other") + `": "query Name {\n  __typename\n}"}`, true},
+		{"not json", `[`, true},
+		{"missing file", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "manifest.json")
+			if tt.data != "" {
+				if err := os.WriteFile(path, []byte(tt.data), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			_, err := LoadManifest(path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("LoadManifest() error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestManifestWrite(t *testing.T) {
+	m := Manifest{QueryHash(persistedQuery): persistedQuery}
+	var buf bytes.Buffer
+	if err := m.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadManifest(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got[QueryHash(persistedQuery)] != persistedQuery {
+		t.Errorf("LoadManifest() = %v, want %v", got, m)
+	}
+}
+
+func TestPersistedQueries(t *testing.T) {
+	manifest := Manifest{QueryHash(persistedQuery): persistedQuery}
+	byHash := func(hash string) map[string]any {
+		return map[string]any{"persistedQuery": map[string]any{"version": 1, "sha256Hash": hash}}
+	}
+	const other = "query Other {\n  __typename\n}"
+
+	tests := []struct {
+		name     string
+		enforce  bool
+		params   map[string]any
+		wantCode string
+	}{
+		{"hash only", false, map[string]any{"extensions": byHash(QueryHash(persistedQuery))}, ""},
+		{"hash and query", false, map[string]any{"query": persistedQuery, "extensions": byHash(QueryHash(persistedQuery))}, ""},
+		{"hash of another query", false, map[string]any{"query": other, "extensions": byHash(QueryHash(persistedQuery))}, "persisted_query_mismatch"},
+		{"query not in manifest", false, map[string]any{"query": other}, ""},
+		{"enforce hash only", true, map[string]any{"extensions": byHash(QueryHash(persistedQuery))}, ""},
+		{"enforce registered query", true, map[string]any{"query": persistedQuery}, ""},
+		{"enforce unregistered query", true, map[string]any{"query": other}, "persisted_query_not_allowed"},
+		{"enforce unknown hash", true, map[string]any{"extensions": byHash(QueryHash(other))}, "persisted_query_not_allowed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler(t, taskstore.New(), PersistedQueries{Manifest: manifest, Enforce: tt.enforce})
+			resp := post(t, h, tt.params)
+			if code := errorCode(resp); code != tt.wantCode {
+				t.Fatalf("error code = %q, want %q (%v)", code, tt.wantCode, resp.Errors)
+			}
+			if tt.wantCode == "" && string(resp.Data) != `{"__typename":"Query"}` {
+				t.Errorf("data = %s", resp.Data)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package graph
+
+import (
+	"context"
+	"restServer/taskstore"
+	"testing"
+	"time"
+)
+
+func TestProjectMutations(t *testing.T) {
+	ctx := context.Background()
+	store := taskstore.New()
+	withTasks := store.CreateProject(ctx, "work", "")
+	empty := store.CreateProject(ctx, "home", "")
+	store.CreateTask(ctx, "task", nil, time.Now(), nil, withTasks)
+	h := newTestHandler(t, store)
+
+	const remove = `mutation($id: ID!, $mode: DeleteProjectMode) { removeProject(input: {id: $id, mode: $mode, clientMutationId: "m"}) { clientMutationId deletedId } }`
+
+	tests := []struct {
+		name     string
+		query    string
+		vars     map[string]any
+		want     string
+		wantCode string
+	}{
+		{"add", `mutation { addProject(input: {name: "new", clientMutationId: "m"}) { clientMutationId project { name taskCount } } }`, nil,
+			`{"addProject":{"clientMutationId":"m","project":{"name":"new","taskCount":0}}}`, ""},
+		{"task count", `query($id: ID!) { project(id: $id) { name taskCount } }`, map[string]any{"id": withTasks},
+			`{"project":{"name":"work","taskCount":1}}`, ""},
+		{"edit", `mutation($id: ID!) { editProject(input: {id: $id, name: "office"}) { project { name } } }`, map[string]any{"id": globalID(nodeProject, withTasks)},
+			`{"editProject":{"project":{"name":"office"}}}`, ""},
+		{"reject with tasks", remove, map[string]any{"id": globalID(nodeProject, withTasks)}, `null`, "project_has_tasks"},
+		{"reject empty", remove, map[string]any{"id": globalID(nodeProject, empty)},
+			`{"removeProject":{"clientMutationId":"m","deletedId":"` + globalID(nodeProject, empty) + `"}}`, ""},
+		{"cascade", remove, map[string]any{"id": globalID(nodeProject, withTasks), "mode": "CASCADE"},
+			`{"removeProject":{"clientMutationId":"m","deletedId":"` + globalID(nodeProject, withTasks) + `"}}`, ""},
+		{"task id is not a project", remove, map[string]any{"id": globalID(nodeTask, "0")}, `null`, "invalid_id"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := post(t, h, map[string]any{"query": tt.query, "variables": tt.vars})
+			if code := errorCode(resp); code != tt.wantCode {
+				t.Fatalf("error code = %q, want %q (%v)", code, tt.wantCode, resp.Errors)
+			}
+			if string(resp.Data) != tt.want {
+				t.Errorf("data = %s, want %s", resp.Data, tt.want)
+			}
+		})
+	}
+
+	if tasks, _ := store.GetAllTasks(ctx); len(tasks) != 0 {
+		t.Errorf("%d tasks left after the cascade", len(tasks))
+	}
+}
@@ -0,0 +1,97 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"net/http"
+	"restServer/logging"
+	"restServer/ratelimit"
+	"slices"
+	"strings"
+)
+
+// Extension de gqlgen con limites por operacion. Las claves de Operations son el
+// nombre de la operacion ("ListTasks") o un campo raiz ("Mutation.createTasks");
+// el campo raiz no depende del nombre que elija el cliente. Toda operacion gasta
+// de Default y ademas de cada regla que coincida: el nombre lo elige el cliente y
+// no puede servir para saltarse Default.
+type RateLimit struct {
+	Limiter    ratelimit.Limiter
+	Default    ratelimit.Rule
+	Operations map[string]ratelimit.Rule
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationContextMutator
+} = RateLimit{}
+
+func (RateLimit) ExtensionName() string {
+	return "RateLimit"
+}
+
+func (RateLimit) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (l RateLimit) MutateOperationContext(ctx context.Context, opCtx *graphql.OperationContext) *gqlerror.Error {
+	client, header := ratelimit.ClientFromContext(ctx)
+	if client == "" || opCtx.Operation == nil {
+		return nil
+	}
+
+	for _, name := range l.matches(opCtx.Operation) {
+		rule := l.Default
+		if name != "" {
+			rule = l.Operations[name]
+		}
+		if !rule.Enabled() {
+			continue
+		}
+
+		res, err := l.Limiter.Allow(ctx, "graphql:"+name+"|"+client, rule, 1)
+		if err != nil {
+			logging.FromContext(ctx).Warn("rate limit backend", "err", err)
+			return nil
+		}
+		if header != nil {
+			ratelimit.SetHeaders(header, rule, res)
+		}
+		if !res.Allowed {
+			logging.FromContext(ctx).Info("rate limited", "client", client, "operation", name)
+			return &gqlerror.Error{
+				Message: fmt.Sprintf("rate limit of %d requests per %s exceeded", rule.Requests, rule.Per),
+				Extensions: map[string]any{
+					"code":       "rate_limited",
+					"status":     http.StatusTooManyRequests,
+					"retryAfter": header.Get("Retry-After"),
+				},
+			}
+		}
+	}
+	return nil
+}
+
+// Reglas que aplican a la operacion: "" (Default) siempre y despues las de Operations
+func (l RateLimit) matches(op *ast.OperationDefinition) []string {
+	names := []string{""}
+	if _, ok := l.Operations[op.Name]; ok && op.Name != "" {
+		names = append(names, op.Name)
+	}
+	root := strings.ToUpper(string(op.Operation[:1])) + string(op.Operation[1:])
+	for _, sel := range op.SelectionSet {
+		field, ok := sel.(*ast.Field)
+		if !ok {
+			continue
+		}
+		// Un campo repetido con alias cuenta una sola vez
+		key := root + "." + field.Name
+		if _, ok := l.Operations[key]; ok && !slices.Contains(names, key) {
+			names = append(names, key)
+		}
+	}
+	return names
+}
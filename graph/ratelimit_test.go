@@ -0,0 +1,48 @@
+package graph
+
+import (
+	"net/http"
+	"restServer/ratelimit"
+	"restServer/taskstore"
+	"testing"
+	"time"
+)
+
+func TestRateLimit(t *testing.T) {
+	const (
+		list   = `query ListTasks { tasks(first: 1) { totalCount } }`
+		create = `mutation AddTask { addTask(input: {text: "x", due: "2026-01-02T15:04:05Z"}) { task { id } } }`
+	)
+	perMinute := func(n int) ratelimit.Rule { return ratelimit.Rule{Requests: n, Per: time.Minute} }
+
+	tests := []struct {
+		name       string
+		operations map[string]ratelimit.Rule
+		queries    []string
+		wantCodes  []string // extensions.code de cada respuesta, "" = sin error
+	}{
+		{"default", nil, []string{list, list, list}, []string{"", "", "rate_limited"}},
+		{"operation rule", map[string]ratelimit.Rule{"Mutation.addTask": perMinute(1)}, []string{create, create}, []string{"", "rate_limited"}},
+		// Una regla propia mas generosa no saca a la operacion de Default
+		{"renamed operation still pays default", map[string]ratelimit.Rule{"ListTasks": perMinute(100)}, []string{list, list, list}, []string{"", "", "rate_limited"}},
+		{"operation rule also spends default", map[string]ratelimit.Rule{"Mutation.addTask": perMinute(1)}, []string{list, create, list}, []string{"", "", "rate_limited"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gql := newTestHandler(t, taskstore.New(), RateLimit{
+				Limiter:    ratelimit.NewMemory(100),
+				Default:    perMinute(2),
+				Operations: tt.operations,
+			})
+			h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gql.ServeHTTP(w, r.WithContext(ratelimit.WithClient(r.Context(), "ip:192.0.2.1", w.Header())))
+			})
+
+			for i, query := range tt.queries {
+				if got := errorCode(post(t, h, map[string]any{"query": query})); got != tt.wantCodes[i] {
+					t.Errorf("request %d code = %q, want %q", i, got, tt.wantCodes[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package graph
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"restServer/graph/model"
+	"restServer/problem"
+	"restServer/taskstore"
+	"strings"
+)
+
+// Tipos de los Id globales
+const (
+	nodeTask    = "Task"
+	nodeProject = "Project"
+	nodeComment = "Comment"
+	nodeWebhook = "Webhook"
+)
+
+var errInvalidID = problem.New(http.StatusBadRequest, "invalid_id", "invalid global id")
+
+// Id global de Relay: base64 de "Tipo:Id". Es opaco para el cliente.
+func globalID(typ, id string) string {
+	return base64.StdEncoding.EncodeToString([]byte(typ + ":" + id))
+}
+
+func parseGlobalID(gid string) (string, string, error) {
+	raw, err := base64.StdEncoding.DecodeString(gid)
+	if err != nil {
+		return "", "", errInvalidID
+	}
+	typ, id, ok := strings.Cut(string(raw), ":")
+	if !ok || typ == "" || id == "" {
+		return "", "", errInvalidID
+	}
+	return typ, id, nil
+}
+
+// Id de REST de un Id global que debe ser del tipo indicado
+func localID(gid, typ string) (string, error) {
+	t, id, err := parseGlobalID(gid)
+	if err != nil {
+		return "", err
+	}
+	if t != typ {
+		return "", problem.New(http.StatusBadRequest, "invalid_id", "global id is not a "+typ)
+	}
+	return id, nil
+}
+
+// Como localID para un argumento opcional ("" si no se envia)
+func optionalLocalID(gid *string, typ string) (string, error) {
+	if gid == nil {
+		return "", nil
+	}
+	return localID(*gid, typ)
+}
+
+// Objeto de un Id global; nil si no existe
+func (r *Resolver) node(ctx context.Context, gid string) (model.Node, error) {
+	typ, id, err := parseGlobalID(gid)
+	if err != nil {
+		return nil, err
+	}
+
+	var node model.Node
+	switch typ {
+	case nodeTask:
+		var task taskstore.Task
+		task, err = r.Store.GetTask(ctx, id)
+		node = taskModel(task)
+	case nodeProject:
+		var project taskstore.Project
+		project, err = r.Store.GetProject(ctx, id)
+		node = projectModel(project)
+	case nodeComment:
+		var comment taskstore.Comment
+		comment, err = r.Store.GetComment(ctx, id)
+		node = commentModel(comment)
+	case nodeWebhook:
+		sub, werr := r.Webhooks.Get(id)
+		node, err = webhookModel(sub, false), werr
+	default:
+		return nil, errInvalidID
+	}
+	if errors.Is(err, taskstore.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return node, nil
+}
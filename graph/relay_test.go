@@ -0,0 +1,57 @@
+package graph
+
+import (
+	"context"
+	"restServer/taskstore"
+	"restServer/webhook"
+	"testing"
+	"time"
+)
+
+func TestRemoveMutations(t *testing.T) {
+	ctx := context.Background()
+	store := taskstore.New()
+	store.CreateTask(ctx, "task", nil, time.Now(), nil, "")
+	store.CreateTask(ctx, "task", nil, time.Now(), nil, "")
+	webhooks := webhook.NewDispatcher(1)
+	sub, err := webhooks.Create("https://example.com/hook", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := NewHandler(HandlerOptions{Store: store, Webhooks: webhooks})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const removeWebhook = `mutation($id: ID!) { removeWebhook(input: {id: $id, clientMutationId: "m"}) { clientMutationId deletedId } }`
+
+	tests := []struct {
+		name     string
+		query    string
+		vars     map[string]any
+		want     string
+		wantCode string
+	}{
+		{"remove webhook", removeWebhook, map[string]any{"id": globalID(nodeWebhook, sub.ID)},
+			`{"removeWebhook":{"clientMutationId":"m","deletedId":"` + globalID(nodeWebhook, sub.ID) + `"}}`, ""},
+		{"remove missing webhook", removeWebhook, map[string]any{"id": globalID(nodeWebhook, sub.ID)}, `null`, "webhook_not_found"},
+		{"task id is not a webhook", removeWebhook, map[string]any{"id": globalID(nodeTask, "0")}, `null`, "invalid_id"},
+		{"remove all tasks", `mutation { removeAllTasks(input: {clientMutationId: "m"}) { clientMutationId } }`, nil,
+			`{"removeAllTasks":{"clientMutationId":"m"}}`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := post(t, h, map[string]any{"query": tt.query, "variables": tt.vars})
+			if code := errorCode(resp); code != tt.wantCode {
+				t.Fatalf("error code = %q, want %q (%v)", code, tt.wantCode, resp.Errors)
+			}
+			if string(resp.Data) != tt.want {
+				t.Errorf("data = %s, want %s", resp.Data, tt.want)
+			}
+		})
+	}
+
+	if tasks, _ := store.GetAllTasks(ctx); len(tasks) != 0 {
+		t.Errorf("%d tasks left after removeAllTasks", len(tasks))
+	}
+}
@@ -1,6 +1,14 @@
 package graph
 
-import "restServer/taskstore"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"restServer/graph/model"
+	"restServer/taskstore"
+	"restServer/validation"
+	"restServer/webhook"
+)
 
 // This file will not be regenerated automatically.
 //
@@ -8,5 +16,75 @@ import "restServer/taskstore"
 // here.
 
 type Resolver struct {
-	Store *taskstore.TaskStore
+	Store    *taskstore.TaskStore
+	Webhooks *webhook.Dispatcher
+}
+
+// Convierte un argumento String opcional en string ("" si no se envia)
+func stringArg(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
+
+// Valida una entrada NewTask con las mismas reglas que REST. Los punteros de los
+// errores apuntan al argumento GraphQL, p.ej. /input/Tags/0.
+func validateNewTask(input *model.NewTask, prefix string) (validation.Task, error) {
+	task := validation.Task{
+		Text:        input.Text,
+		Tags:        input.Tags,
+		Due:         input.Due,
+		Attachments: newAttachments(input.Attachments),
+	}
+	errs := validation.DefaultTaskRules.Check(&task, validation.FieldsPascal)
+	return task, errs.Prefix(prefix).Err()
+}
+
+// Crea las tareas de ops en un solo Batch atomico y las devuelve. El error de una
+// operacion indica su posicion en arg, la lista del argumento GraphQL.
+func (r *Resolver) createTasks(ctx context.Context, ops []taskstore.BatchOp, arg string) ([]*model.Task, error) {
+	results, err := r.Store.Batch(ctx, ops, true)
+	if err != nil {
+		for i, res := range results {
+			if res.Error != nil && !errors.Is(res.Error, taskstore.ErrBatchAborted) {
+				return nil, fmt.Errorf("%s[%d]: %w", arg, i, res.Error)
+			}
+		}
+		return nil, err
+	}
+
+	tasks := make([]*model.Task, 0, len(results))
+	for _, res := range results {
+		task, err := r.Store.GetTask(ctx, res.ID)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, taskModel(task))
+	}
+	return tasks, nil
+}
+
+// Convierte un argumento Int opcional de GraphQL en int (0 si no se envia)
+func intArg(v *int32) int {
+	if v == nil {
+		return 0
+	}
+	return int(*v)
+}
+
+// Convierte una suscripcion en el modelo GraphQL, el secreto solo se incluye si se pide
+func webhookModel(sub webhook.Subscription, withSecret bool) *model.Webhook {
+	w := &model.Webhook{
+		ID:        sub.ID,
+		URL:       sub.URL,
+		Events:    sub.Events,
+		Active:    sub.Active,
+		CreatedAt: sub.CreatedAt,
+	}
+	if withSecret {
+		secret := sub.Secret
+		w.Secret = &secret
+	}
+	return w
 }
@@ -7,31 +7,75 @@ package graph
 
 import (
 	"context"
+	"errors"
 	"restServer/graph/model"
+	"restServer/logging"
+	"restServer/taskstore"
+	"restServer/validation"
 	"time"
+
+	"github.com/99designs/gqlgen/graphql"
 )
 
+// ID is the resolver for the id field.
+func (r *commentResolver) ID(ctx context.Context, obj *model.Comment) (string, error) {
+	return globalID(nodeComment, obj.ID), nil
+}
+
+// Task is the resolver for the task field.
+func (r *commentResolver) Task(ctx context.Context, obj *model.Comment) (*model.Task, error) {
+	task, err := r.Store.GetTask(ctx, obj.TaskID)
+	if err != nil {
+		return nil, err
+	}
+	return taskModel(task), nil
+}
+
 // CreateTodo is the resolver for the createTodo field.
 func (r *mutationResolver) CreateTask(ctx context.Context, input model.NewTask) (*model.Task, error) {
-	attachments := make([]*model.Attachment, 0, len(input.Attachments))
-	for _, a := range input.Attachments {
-		attachments = append(attachments, &model.Attachment{
-			Name:     a.Name,
-			Date:     a.Date,
-			Contents: a.Contents,
-		})
+	valid, err := validateNewTask(&input, "/input")
+	if err != nil {
+		return nil, err
+	}
+	id, err := r.Store.CreateTask(ctx, valid.Text, valid.Tags, valid.Due, valid.Attachments, stringArg(input.ProjectID))
+	if err != nil {
+		return nil, err
 	}
-	id := r.Store.CreateTask(input.Text, input.Tags, input.Due, attachments)
-	task, err := r.Store.GetTask(id)
+	task, err := r.Store.GetTask(ctx, id)
 	if err != nil {
 		return nil, err
 	}
-	return &task, nil
+	return taskModel(task), nil
+}
+
+// CreateTasks is the resolver for the createTasks field.
+func (r *mutationResolver) CreateTasks(ctx context.Context, inputs []*model.NewTask) ([]*model.Task, error) {
+	ops := make([]taskstore.BatchOp, 0, len(inputs))
+	var fields validation.Errors
+	for i, input := range inputs {
+		valid, err := validateNewTask(input, validation.Pointer("inputs", i))
+		if err != nil {
+			fields = append(fields, err.(validation.Errors)...)
+			continue
+		}
+		ops = append(ops, taskstore.BatchOp{
+			Op:          taskstore.BatchCreate,
+			Text:        valid.Text,
+			Tags:        valid.Tags,
+			Due:         valid.Due,
+			Attachments: valid.Attachments,
+			ProjectID:   stringArg(input.ProjectID),
+		})
+	}
+	if len(fields) > 0 {
+		return nil, fields
+	}
+	return r.createTasks(ctx, ops, "inputs")
 }
 
 // DeleteTask is the resolver for the deleteTask field.
 func (r *mutationResolver) DeleteTask(ctx context.Context, id string) (*bool, error) {
-	err := r.Store.DeleteTask(id)
+	err := r.Store.DeleteTask(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -41,7 +85,7 @@ func (r *mutationResolver) DeleteTask(ctx context.Context, id string) (*bool, er
 
 // DeleteAllTasks is the resolver for the deleteAllTasks field.
 func (r *mutationResolver) DeleteAllTasks(ctx context.Context) (*bool, error) {
-	err := r.Store.DeleteAllTasks()
+	err := r.Store.DeleteAllTasks(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -49,79 +93,555 @@ func (r *mutationResolver) DeleteAllTasks(ctx context.Context) (*bool, error) {
 	return &success, nil
 }
 
-// GetAllTasks is the resolver for the getAllTasks field.
-func (r *queryResolver) GetAllTasks(ctx context.Context) ([]*model.Task, error) {
-	println("RESOLVER DEBUG: GetAllTasks llamado")
-	println("RESOLVER DEBUG: Store pointer:", r.Store)
+// CreateProject is the resolver for the createProject field.
+func (r *mutationResolver) CreateProject(ctx context.Context, input model.NewProject) (*model.Project, error) {
+	description := stringArg(input.Description)
+	id := r.Store.CreateProject(ctx, input.Name, description)
+	project, err := r.Store.GetProject(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return projectModel(project), nil
+}
+
+// UpdateProject is the resolver for the updateProject field.
+func (r *mutationResolver) UpdateProject(ctx context.Context, id string, input model.NewProject) (*model.Project, error) {
+	description := stringArg(input.Description)
+	project, err := r.Store.UpdateProject(ctx, id, input.Name, description)
+	if err != nil {
+		return nil, err
+	}
+	return projectModel(project), nil
+}
+
+// DeleteProject is the resolver for the deleteProject field.
+func (r *mutationResolver) DeleteProject(ctx context.Context, id string, mode *model.DeleteProjectMode) (*bool, error) {
+	deleteMode := taskstore.DeleteReject
+	if mode != nil && *mode == model.DeleteProjectModeCascade {
+		deleteMode = taskstore.DeleteCascade
+	}
+	err := r.Store.DeleteProject(ctx, id, deleteMode)
+	if err != nil {
+		return nil, err
+	}
+	success := true
+	return &success, nil
+}
 
-	tasks, err := r.Store.GetAllTasks()
+// AddComment is the resolver for the addComment field.
+func (r *mutationResolver) AddComment(ctx context.Context, taskID string, input model.NewComment) (*model.Comment, error) {
+	comment, err := r.Store.AddComment(ctx, taskID, input.Author, input.Body)
 	if err != nil {
-		println("RESOLVER DEBUG: Error al obtener tareas:", err.Error())
 		return nil, err
 	}
+	return commentModel(comment), nil
+}
 
-	println("RESOLVER DEBUG: Total tareas obtenidas:", len(tasks))
+// UpdateComment is the resolver for the updateComment field.
+func (r *mutationResolver) UpdateComment(ctx context.Context, id string, body string) (*model.Comment, error) {
+	comment, err := r.Store.UpdateComment(ctx, id, body)
+	if err != nil {
+		return nil, err
+	}
+	return commentModel(comment), nil
+}
 
-	result := make([]*model.Task, 0, len(tasks))
-	for i := range tasks {
-		task := tasks[i]
-		result = append(result, &task)
+// DeleteComment is the resolver for the deleteComment field.
+func (r *mutationResolver) DeleteComment(ctx context.Context, id string) (*bool, error) {
+	err := r.Store.DeleteComment(ctx, id)
+	if err != nil {
+		return nil, err
 	}
-	return result, nil
+	success := true
+	return &success, nil
 }
 
-// GetTask is the resolver for the getTask field.
-func (r *queryResolver) GetTask(ctx context.Context, id string) (*model.Task, error) {
-	task, err := r.Store.GetTask(id)
+// SetReminders is the resolver for the setReminders field.
+func (r *mutationResolver) SetReminders(ctx context.Context, taskID string, input model.ReminderInput) (*model.ReminderConfig, error) {
+	config, err := r.Store.SetReminders(ctx, taskID, input.Offsets, input.Channels)
 	if err != nil {
 		return nil, err
 	}
-	return &task, nil
+	return reminderModel(config), nil
 }
 
-// GetTasksByTag is the resolver for the getTasksByTag field.
-func (r *queryResolver) GetTasksByTag(ctx context.Context, tag string) ([]*model.Task, error) {
-	println("RESOLVER DEBUG: GetTasksByTag llamado con tag:", tag)
-	println("RESOLVER DEBUG: Store pointer:", r.Store)
+// CreateWebhook is the resolver for the createWebhook field.
+func (r *mutationResolver) CreateWebhook(ctx context.Context, input model.NewWebhook) (*model.Webhook, error) {
+	secret := stringArg(input.Secret)
+	sub, err := r.Webhooks.Create(input.URL, secret, input.Events)
+	if err != nil {
+		return nil, err
+	}
+	return webhookModel(sub, true), nil
+}
+
+// UpdateWebhook is the resolver for the updateWebhook field.
+func (r *mutationResolver) UpdateWebhook(ctx context.Context, id string, input model.UpdateWebhook) (*model.Webhook, error) {
+	secret := stringArg(input.Secret)
+	sub, err := r.Webhooks.Update(id, input.URL, secret, input.Events, input.Active)
+	if err != nil {
+		return nil, err
+	}
+	return webhookModel(sub, false), nil
+}
+
+// DeleteWebhook is the resolver for the deleteWebhook field.
+func (r *mutationResolver) DeleteWebhook(ctx context.Context, id string) (*bool, error) {
+	err := r.Webhooks.Delete(id)
+	if err != nil {
+		return nil, err
+	}
+	success := true
+	return &success, nil
+}
+
+// AddTask is the resolver for the addTask field.
+func (r *mutationResolver) AddTask(ctx context.Context, input model.AddTaskInput) (*model.AddTaskPayload, error) {
+	projectID, err := optionalLocalID(input.ProjectID, nodeProject)
+	if err != nil {
+		return nil, err
+	}
+	task := validation.Task{
+		Text:        input.Text,
+		Tags:        input.Tags,
+		Due:         input.Due,
+		Attachments: attachmentInputs(input.Attachments),
+	}
+	if errs := validation.DefaultTaskRules.Check(&task, validation.FieldsCamel); len(errs) > 0 {
+		return nil, errs.Prefix("/input")
+	}
+
+	id, err := r.Store.CreateTask(ctx, task.Text, task.Tags, task.Due, task.Attachments, projectID)
+	if err != nil {
+		return nil, err
+	}
+	created, err := r.Store.GetTask(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &model.AddTaskPayload{ClientMutationID: input.ClientMutationID, Task: taskModel(created)}, nil
+}
+
+// RemoveTask is the resolver for the removeTask field.
+func (r *mutationResolver) RemoveTask(ctx context.Context, input model.RemoveTaskInput) (*model.RemoveTaskPayload, error) {
+	id, err := localID(input.ID, nodeTask)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.Store.DeleteTask(ctx, id); err != nil {
+		return nil, err
+	}
+	return &model.RemoveTaskPayload{ClientMutationID: input.ClientMutationID, DeletedID: input.ID}, nil
+}
+
+// RemoveAllTasks is the resolver for the removeAllTasks field.
+func (r *mutationResolver) RemoveAllTasks(ctx context.Context, input model.RemoveAllTasksInput) (*model.RemoveAllTasksPayload, error) {
+	if err := r.Store.DeleteAllTasks(ctx); err != nil {
+		return nil, err
+	}
+	return &model.RemoveAllTasksPayload{ClientMutationID: input.ClientMutationID}, nil
+}
+
+// AddProject is the resolver for the addProject field.
+func (r *mutationResolver) AddProject(ctx context.Context, input model.AddProjectInput) (*model.AddProjectPayload, error) {
+	id := r.Store.CreateProject(ctx, input.Name, stringArg(input.Description))
+	project, err := r.Store.GetProject(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &model.AddProjectPayload{ClientMutationID: input.ClientMutationID, Project: projectModel(project)}, nil
+}
+
+// EditProject is the resolver for the editProject field.
+func (r *mutationResolver) EditProject(ctx context.Context, input model.EditProjectInput) (*model.EditProjectPayload, error) {
+	id, err := localID(input.ID, nodeProject)
+	if err != nil {
+		return nil, err
+	}
+	project, err := r.Store.UpdateProject(ctx, id, input.Name, stringArg(input.Description))
+	if err != nil {
+		return nil, err
+	}
+	return &model.EditProjectPayload{ClientMutationID: input.ClientMutationID, Project: projectModel(project)}, nil
+}
+
+// RemoveProject is the resolver for the removeProject field.
+func (r *mutationResolver) RemoveProject(ctx context.Context, input model.RemoveProjectInput) (*model.RemoveProjectPayload, error) {
+	id, err := localID(input.ID, nodeProject)
+	if err != nil {
+		return nil, err
+	}
+	mode := taskstore.DeleteReject
+	if input.Mode != nil && *input.Mode == model.DeleteProjectModeCascade {
+		mode = taskstore.DeleteCascade
+	}
+	if err := r.Store.DeleteProject(ctx, id, mode); err != nil {
+		return nil, err
+	}
+	return &model.RemoveProjectPayload{ClientMutationID: input.ClientMutationID, DeletedID: input.ID}, nil
+}
+
+// AddTaskComment is the resolver for the addTaskComment field.
+func (r *mutationResolver) AddTaskComment(ctx context.Context, input model.AddTaskCommentInput) (*model.AddTaskCommentPayload, error) {
+	taskID, err := localID(input.TaskID, nodeTask)
+	if err != nil {
+		return nil, err
+	}
+	comment, err := r.Store.AddComment(ctx, taskID, input.Author, input.Body)
+	if err != nil {
+		return nil, err
+	}
+	task, err := r.Store.GetTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	return &model.AddTaskCommentPayload{ClientMutationID: input.ClientMutationID, Comment: commentModel(comment), Task: taskModel(task)}, nil
+}
+
+// EditComment is the resolver for the editComment field.
+func (r *mutationResolver) EditComment(ctx context.Context, input model.EditCommentInput) (*model.EditCommentPayload, error) {
+	id, err := localID(input.ID, nodeComment)
+	if err != nil {
+		return nil, err
+	}
+	comment, err := r.Store.UpdateComment(ctx, id, input.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &model.EditCommentPayload{ClientMutationID: input.ClientMutationID, Comment: commentModel(comment)}, nil
+}
+
+// RemoveComment is the resolver for the removeComment field.
+func (r *mutationResolver) RemoveComment(ctx context.Context, input model.RemoveCommentInput) (*model.RemoveCommentPayload, error) {
+	id, err := localID(input.ID, nodeComment)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.Store.DeleteComment(ctx, id); err != nil {
+		return nil, err
+	}
+	return &model.RemoveCommentPayload{ClientMutationID: input.ClientMutationID, DeletedID: input.ID}, nil
+}
+
+// AddTasks is the resolver for the addTasks field.
+func (r *mutationResolver) AddTasks(ctx context.Context, input model.AddTasksInput) (*model.AddTasksPayload, error) {
+	ops := make([]taskstore.BatchOp, 0, len(input.Tasks))
+	var fields validation.Errors
+	for i, in := range input.Tasks {
+		projectID, err := optionalLocalID(in.ProjectID, nodeProject)
+		if err != nil {
+			return nil, err
+		}
+		task := validation.Task{
+			Text:        in.Text,
+			Tags:        in.Tags,
+			Due:         in.Due,
+			Attachments: attachmentInputs(in.Attachments),
+		}
+		if errs := validation.DefaultTaskRules.Check(&task, validation.FieldsCamel); len(errs) > 0 {
+			fields = append(fields, errs.Prefix(validation.Pointer("input", "tasks", i))...)
+			continue
+		}
+		ops = append(ops, taskstore.BatchOp{
+			Op:          taskstore.BatchCreate,
+			Text:        task.Text,
+			Tags:        task.Tags,
+			Due:         task.Due,
+			Attachments: task.Attachments,
+			ProjectID:   projectID,
+		})
+	}
+	if len(fields) > 0 {
+		return nil, fields
+	}
+
+	tasks, err := r.createTasks(ctx, ops, "input.tasks")
+	if err != nil {
+		return nil, err
+	}
+	return &model.AddTasksPayload{ClientMutationID: input.ClientMutationID, Tasks: tasks}, nil
+}
+
+// SetTaskReminders is the resolver for the setTaskReminders field.
+func (r *mutationResolver) SetTaskReminders(ctx context.Context, input model.SetTaskRemindersInput) (*model.SetTaskRemindersPayload, error) {
+	taskID, err := localID(input.TaskID, nodeTask)
+	if err != nil {
+		return nil, err
+	}
+	config, err := r.Store.SetReminders(ctx, taskID, input.Offsets, input.Channels)
+	if err != nil {
+		return nil, err
+	}
+	return &model.SetTaskRemindersPayload{ClientMutationID: input.ClientMutationID, Reminders: reminderModel(config)}, nil
+}
+
+// AddWebhook is the resolver for the addWebhook field.
+func (r *mutationResolver) AddWebhook(ctx context.Context, input model.AddWebhookInput) (*model.AddWebhookPayload, error) {
+	sub, err := r.Webhooks.Create(input.URL, stringArg(input.Secret), input.Events)
+	if err != nil {
+		return nil, err
+	}
+	return &model.AddWebhookPayload{ClientMutationID: input.ClientMutationID, Webhook: webhookModel(sub, true)}, nil
+}
+
+// EditWebhook is the resolver for the editWebhook field.
+func (r *mutationResolver) EditWebhook(ctx context.Context, input model.EditWebhookInput) (*model.EditWebhookPayload, error) {
+	id, err := localID(input.ID, nodeWebhook)
+	if err != nil {
+		return nil, err
+	}
+	sub, err := r.Webhooks.Update(id, input.URL, stringArg(input.Secret), input.Events, input.Active)
+	if err != nil {
+		return nil, err
+	}
+	return &model.EditWebhookPayload{ClientMutationID: input.ClientMutationID, Webhook: webhookModel(sub, false)}, nil
+}
+
+// RemoveWebhook is the resolver for the removeWebhook field.
+func (r *mutationResolver) RemoveWebhook(ctx context.Context, input model.RemoveWebhookInput) (*model.RemoveWebhookPayload, error) {
+	id, err := localID(input.ID, nodeWebhook)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.Webhooks.Delete(id); err != nil {
+		return nil, err
+	}
+	return &model.RemoveWebhookPayload{ClientMutationID: input.ClientMutationID, DeletedID: input.ID}, nil
+}
+
+// ID is the resolver for the id field.
+func (r *projectResolver) ID(ctx context.Context, obj *model.Project) (string, error) {
+	return globalID(nodeProject, obj.ID), nil
+}
+
+// TasksConnection is the resolver for the tasksConnection field.
+func (r *projectResolver) TasksConnection(ctx context.Context, obj *model.Project, first *int32, after *string, last *int32, before *string) (*model.TaskConnection, error) {
+	tasks, err := r.Store.ListTasks(ctx, taskstore.TaskFilter{ProjectID: obj.ID})
+	if err != nil {
+		return nil, err
+	}
+	return taskConnection(tasks, first, last, after, before)
+}
+
+// Tasks is the resolver for the Tasks field.
+func (r *projectResolver) Tasks(ctx context.Context, obj *model.Project, offset *int32, limit *int32) ([]*model.Task, error) {
+	tasks, _, err := r.Store.GetTasksByProject(ctx, obj.ID, intArg(offset), pageSize(limit, defaultPageSize))
+	if err != nil {
+		return nil, err
+	}
+	return taskModels(tasks), nil
+}
 
-	tasks, err := r.Store.GetTasksByTag(tag)
+// Task is the resolver for the task field.
+func (r *queryResolver) Task(ctx context.Context, id string) (*model.Task, error) {
+	task, err := r.Store.GetTask(ctx, id)
 	if err != nil {
-		println("RESOLVER DEBUG: Error al obtener tareas:", err.Error())
 		return nil, err
 	}
+	return taskModel(task), nil
+}
 
-	println("RESOLVER DEBUG: Tareas obtenidas del store:", len(tasks))
+// Projects is the resolver for the projects field.
+func (r *queryResolver) Projects(ctx context.Context, offset *int32, limit *int32) ([]*model.Project, error) {
+	projects, _, err := r.Store.GetAllProjects(ctx, intArg(offset), pageSize(limit, defaultPageSize))
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*model.Project, 0, len(projects))
+	for i := range projects {
+		result = append(result, projectModel(projects[i]))
+	}
+	return result, nil
+}
 
-	result := make([]*model.Task, 0, len(tasks))
-	for i := range tasks {
-		task := tasks[i]
-		result = append(result, &task)
+// Project is the resolver for the project field.
+func (r *queryResolver) Project(ctx context.Context, id string) (*model.Project, error) {
+	project, err := r.Store.GetProject(ctx, id)
+	if err != nil {
+		return nil, err
 	}
+	return projectModel(project), nil
+}
 
-	println("RESOLVER DEBUG: Tareas a retornar:", len(result))
+// Webhooks is the resolver for the webhooks field.
+func (r *queryResolver) Webhooks(ctx context.Context) ([]*model.Webhook, error) {
+	subs := truncate(r.Resolver.Webhooks.List(), maxWebhooks)
+	result := make([]*model.Webhook, 0, len(subs))
+	for _, sub := range subs {
+		result = append(result, webhookModel(sub, false))
+	}
 	return result, nil
 }
 
+// Webhook is the resolver for the webhook field.
+func (r *queryResolver) Webhook(ctx context.Context, id string) (*model.Webhook, error) {
+	sub, err := r.Resolver.Webhooks.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	return webhookModel(sub, false), nil
+}
+
+// GetAllTasks is the resolver for the getAllTasks field.
+func (r *queryResolver) GetAllTasks(ctx context.Context) ([]*model.Task, error) {
+	tasks, err := r.Store.GetAllTasks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	logging.FromContext(ctx).Debug("resolved getAllTasks", "tasks", len(tasks))
+
+	return taskModels(truncate(tasks, maxAllTasks)), nil
+}
+
+// GetTask is the resolver for the getTask field.
+func (r *queryResolver) GetTask(ctx context.Context, id string) (*model.Task, error) {
+	return r.Task(ctx, id)
+}
+
+// GetTasksByTag is the resolver for the getTasksByTag field.
+func (r *queryResolver) GetTasksByTag(ctx context.Context, tag string) ([]*model.Task, error) {
+	tasks, err := r.Store.GetTasksByTag(ctx, tag)
+	if err != nil {
+		return nil, err
+	}
+	logging.FromContext(ctx).Debug("resolved getTasksByTag", "tag", tag, "tasks", len(tasks))
+
+	return taskModels(truncate(tasks, maxFilteredTasks)), nil
+}
+
 // GetTasksByDue is the resolver for the getTasksByDue field.
 func (r *queryResolver) GetTasksByDue(ctx context.Context, due time.Time) ([]*model.Task, error) {
 	y, m, d := due.Date()
-	tasks, err := r.Store.GetTasksByDue(y, m, d)
+	tasks, err := r.Store.GetTasksByDue(ctx, y, m, d)
 	if err != nil {
 		return nil, err
 	}
-	result := make([]*model.Task, 0, len(tasks))
-	for i := range tasks {
-		task := tasks[i]
-		result = append(result, &task)
+	return taskModels(truncate(tasks, maxFilteredTasks)), nil
+}
+
+// GetAllProjects is the resolver for the getAllProjects field.
+func (r *queryResolver) GetAllProjects(ctx context.Context, offset *int32, limit *int32) ([]*model.Project, error) {
+	return r.Projects(ctx, offset, limit)
+}
+
+// GetProject is the resolver for the getProject field.
+func (r *queryResolver) GetProject(ctx context.Context, id string) (*model.Project, error) {
+	return r.Project(ctx, id)
+}
+
+// GetWebhooks is the resolver for the getWebhooks field.
+func (r *queryResolver) GetWebhooks(ctx context.Context) ([]*model.Webhook, error) {
+	return r.Webhooks(ctx)
+}
+
+// GetWebhook is the resolver for the getWebhook field.
+func (r *queryResolver) GetWebhook(ctx context.Context, id string) (*model.Webhook, error) {
+	return r.Webhook(ctx, id)
+}
+
+// Node is the resolver for the node field.
+func (r *queryResolver) Node(ctx context.Context, id string) (model.Node, error) {
+	return r.node(ctx, id)
+}
+
+// Nodes is the resolver for the nodes field.
+func (r *queryResolver) Nodes(ctx context.Context, ids []string) ([]model.Node, error) {
+	nodes := make([]model.Node, len(ids))
+	for i, id := range ids {
+		node, err := r.node(ctx, id)
+		if errors.Is(err, errInvalidID) {
+			// Como con node: null para ese Id, con el error en su posicion
+			graphql.AddError(graphql.WithPathContext(ctx, graphql.NewPathWithIndex(i)), err)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		nodes[i] = node
 	}
-	return result, nil
+	return nodes, nil
 }
 
+// Tasks is the resolver for the tasks field.
+func (r *queryResolver) Tasks(ctx context.Context, first *int32, after *string, last *int32, before *string, tag *string, due *time.Time, projectID *string) (*model.TaskConnection, error) {
+	filter := taskstore.TaskFilter{Tag: stringArg(tag), Due: due}
+	var err error
+	if filter.ProjectID, err = optionalLocalID(projectID, nodeProject); err != nil {
+		return nil, err
+	}
+	tasks, err := r.Store.ListTasks(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return taskConnection(tasks, first, last, after, before)
+}
+
+// ID is the resolver for the id field.
+func (r *taskResolver) ID(ctx context.Context, obj *model.Task) (string, error) {
+	return globalID(nodeTask, obj.ID), nil
+}
+
+// Project is the resolver for the project field.
+func (r *taskResolver) Project(ctx context.Context, obj *model.Task) (*model.Project, error) {
+	if obj.ProjectID == nil {
+		return nil, nil
+	}
+	project, err := r.Store.GetProject(ctx, *obj.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	return projectModel(project), nil
+}
+
+// Comments is the resolver for the comments field.
+func (r *taskResolver) Comments(ctx context.Context, obj *model.Task, first *int32, after *string) (*model.CommentConnection, error) {
+	comments, _, err := r.Store.GetComments(ctx, obj.ID, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	return commentConnection(comments, first, after)
+}
+
+// Reminders is the resolver for the Reminders field.
+func (r *taskResolver) Reminders(ctx context.Context, obj *model.Task) (*model.ReminderConfig, error) {
+	config, own, err := r.Store.GetReminders(ctx, obj.ID)
+	if err != nil || !own {
+		return nil, err
+	}
+	return reminderModel(config), nil
+}
+
+// LegacyReminders is the resolver for the Reminders field.
+func (r *taskResolver) LegacyReminders(ctx context.Context, obj *model.Task) (*model.ReminderConfig, error) {
+	return r.Reminders(ctx, obj)
+}
+
+// ID is the resolver for the id field.
+func (r *webhookResolver) ID(ctx context.Context, obj *model.Webhook) (string, error) {
+	return globalID(nodeWebhook, obj.ID), nil
+}
+
+// Comment returns CommentResolver implementation.
+func (r *Resolver) Comment() CommentResolver { return &commentResolver{r} }
+
 // Mutation returns MutationResolver implementation.
 func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
 
+// Project returns ProjectResolver implementation.
+func (r *Resolver) Project() ProjectResolver { return &projectResolver{r} }
+
 // Query returns QueryResolver implementation.
 func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
 
+// Task returns TaskResolver implementation.
+func (r *Resolver) Task() TaskResolver { return &taskResolver{r} }
+
+// Webhook returns WebhookResolver implementation.
+func (r *Resolver) Webhook() WebhookResolver { return &webhookResolver{r} }
+
+type commentResolver struct{ *Resolver }
 type mutationResolver struct{ *Resolver }
+type projectResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
+type taskResolver struct{ *Resolver }
+type webhookResolver struct{ *Resolver }
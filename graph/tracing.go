@@ -0,0 +1,92 @@
+package graph
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/99designs/gqlgen/graphql"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("restServer/graph")
+
+// Extension de gqlgen que abre un span por operacion y uno hijo por cada campo con
+// resolver. Los campos triviales (lectura de un struct) no generan span.
+type Tracing struct{}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.ResponseInterceptor
+	graphql.FieldInterceptor
+} = Tracing{}
+
+func (Tracing) ExtensionName() string {
+	return "Tracing"
+}
+
+func (Tracing) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (Tracing) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	if !graphql.HasOperationContext(ctx) {
+		return next(ctx)
+	}
+	opCtx := graphql.GetOperationContext(ctx)
+	if opCtx.Operation == nil {
+		return next(ctx)
+	}
+
+	name := opCtx.Operation.Name
+	if name == "" {
+		name = "anonymous"
+	}
+	ctx, span := tracer.Start(ctx, fmt.Sprintf("%s %s", opCtx.Operation.Operation, name),
+		trace.WithAttributes(
+			attribute.String("graphql.operation.type", string(opCtx.Operation.Operation)),
+			attribute.String("graphql.operation.name", opCtx.Operation.Name),
+			// El documento puede traer secretos en literales, solo va su hash
+			attribute.String("graphql.document.hash", documentHash(opCtx.RawQuery)),
+		),
+	)
+	defer span.End()
+
+	resp := next(ctx)
+	if resp != nil && len(resp.Errors) > 0 {
+		span.SetStatus(codes.Error, resp.Errors.Error())
+	}
+	return resp
+}
+
+// sha256 del texto del documento en hexadecimal, el mismo que calculan los clientes
+// de APQ para la consulta
+func documentHash(query string) string {
+	sum := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(sum[:])
+}
+
+func (Tracing) InterceptField(ctx context.Context, next graphql.Resolver) (any, error) {
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil || !fc.IsResolver {
+		return next(ctx)
+	}
+
+	ctx, span := tracer.Start(ctx, fc.Object+"."+fc.Field.Name,
+		trace.WithAttributes(
+			attribute.String("graphql.field.path", fc.Path().String()),
+			attribute.String("graphql.field.type", fc.Field.Definition.Type.String()),
+		),
+	)
+	defer span.End()
+
+	res, err := next(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return res, err
+}
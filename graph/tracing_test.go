@@ -0,0 +1,73 @@
+package graph
+
+import (
+	"context"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"restServer/taskstore"
+	"testing"
+	"time"
+)
+
+func TestTracing(t *testing.T) {
+	store := taskstore.New()
+	id, err := store.CreateTask(context.Background(), "write tests", nil, time.Now(), nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := newTestHandler(t, store, Tracing{})
+
+	tests := []struct {
+		name      string
+		query     string
+		wantSpans []string // hijos primero: terminan antes que la operacion
+		wantError bool
+	}{
+		{"named query", `query One($id: ID!) { task(id: $id) { text } }`, []string{"TaskStore.GetTask", "Query.task", "query One"}, false},
+		{"invalid document is not traced", `{ task(id: $id) { text } }`, nil, false},
+		{"anonymous operation", `query($id: ID!) { task(id: $id) { text } }`, []string{"TaskStore.GetTask", "Query.task", "query anonymous"}, false},
+		{"resolver error", `query Missing { task(id: "404") { text } }`, []string{"TaskStore.GetTask", "Query.task", "query Missing"}, true},
+		{"trivial fields have no span", `query Name { __typename }`, []string{"query Name"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spans.Reset()
+			post(t, h, map[string]any{"query": tt.query, "variables": map[string]any{"id": id}})
+
+			got := spans.GetSpans()
+			if len(got) != len(tt.wantSpans) {
+				t.Fatalf("got %d spans, want %d", len(got), len(tt.wantSpans))
+			}
+			for i, name := range tt.wantSpans {
+				if got[i].Name != name {
+					t.Errorf("span %d = %q, want %q", i, got[i].Name, name)
+				}
+			}
+			if len(got) == 0 {
+				return
+			}
+
+			op := got[len(got)-1]
+			if hash := attr(op.Attributes, "graphql.document.hash"); hash != documentHash(tt.query) {
+				t.Errorf("graphql.document.hash = %q, want %q", hash, documentHash(tt.query))
+			}
+			if isError := op.Status.Code == codes.Error; isError != tt.wantError {
+				t.Errorf("error status = %v, want %v", isError, tt.wantError)
+			}
+			for _, child := range got[:len(got)-1] {
+				if child.SpanContext.TraceID() != op.SpanContext.TraceID() {
+					t.Errorf("span %q is not in the operation trace", child.Name)
+				}
+			}
+		})
+	}
+}
+
+func attr(attrs []attribute.KeyValue, key string) string {
+	for _, kv := range attrs {
+		if string(kv.Key) == key {
+			return kv.Value.Emit()
+		}
+	}
+	return ""
+}
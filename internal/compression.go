@@ -0,0 +1,344 @@
+package internal
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"github.com/klauspost/compress/zstd"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"restServer/problem"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Compresion de respuestas segun Accept-Encoding y descompresion de los cuerpos
+// de las peticiones con Content-Encoding.
+//
+// La decision de comprimir se toma con los primeros MinSize bytes: las respuestas
+// mas chicas, sin cuerpo (204, 304, HEAD), ya codificadas (Content-Encoding), de
+// tipos ya comprimidos (imagenes, zip, ...) o con Cache-Control: no-transform se
+// envian tal cual. Un Flush antes de llegar a MinSize (SSE, NDJSON) decide en ese
+// momento y cada Flush siguiente vacia tambien el compresor, asi los eventos
+// llegan al cliente sin esperar. Los websockets no se tocan.
+type Compression struct {
+	Encodings []string // "zstd" y/o "gzip", en orden de preferencia
+	MinSize   int
+	MaxBody   int64 // bytes de un cuerpo descomprimido, limita las bombas de compresion
+}
+
+func (c Compression) Middleware(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !c.decodeRequest(w, r) {
+			return
+		}
+		if r.Header.Get("Upgrade") != "" {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Add("Vary", "Accept-Encoding")
+		encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"), c.Encodings)
+		if encoding == "" || r.Method == http.MethodHead {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		cw := &compressWriter{ResponseWriter: w, encoding: encoding, minSize: c.MinSize}
+		defer cw.close()
+		h.ServeHTTP(cw, r)
+	})
+}
+
+// Reemplaza r.Body por el cuerpo descomprimido. Responde 415 si la codificacion no
+// esta soportada y 400 si el cuerpo no es valido.
+func (c Compression) decodeRequest(w http.ResponseWriter, r *http.Request) bool {
+	encoding := strings.ToLower(strings.TrimSpace(r.Header.Get("Content-Encoding")))
+	if encoding == "" || encoding == "identity" || r.Body == nil || r.Body == http.NoBody {
+		return true
+	}
+
+	var body io.ReadCloser
+	switch encoding {
+	case "gzip", "x-gzip":
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			problem.Write(w, r, problem.New(http.StatusBadRequest, "invalid_body", "invalid gzip body: "+err.Error()))
+			return false
+		}
+		body = zr
+	case "zstd":
+		zr, err := zstd.NewReader(r.Body, zstd.WithDecoderConcurrency(1), zstd.WithDecoderMaxMemory(uint64(c.MaxBody)))
+		if err != nil {
+			problem.Write(w, r, problem.New(http.StatusBadRequest, "invalid_body", "invalid zstd body: "+err.Error()))
+			return false
+		}
+		body = zr.IOReadCloser()
+	default:
+		w.Header().Set("Accept-Encoding", "gzip, zstd")
+		problem.Write(w, r, problem.New(http.StatusUnsupportedMediaType, "unsupported_content_encoding",
+			"Content-Encoding must be gzip or zstd"))
+		return false
+	}
+
+	r.Body = http.MaxBytesReader(w, body, c.MaxBody)
+	r.Header.Del("Content-Encoding")
+	r.Header.Del("Content-Length")
+	r.ContentLength = -1
+	return true
+}
+
+// Codificacion de offers con mayor calidad en Accept-Encoding, a igual calidad la
+// primera de offers. "*" cubre las que no se nombran; "" si ninguna es aceptable.
+func negotiateEncoding(accept string, offers []string) string {
+	if accept == "" {
+		return ""
+	}
+	quality := make(map[string]float64)
+	for _, part := range strings.Split(accept, ",") {
+		name, params, _ := strings.Cut(part, ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		q := 1.0
+		if raw, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			var err error
+			if q, err = strconv.ParseFloat(raw, 64); err != nil {
+				continue
+			}
+		}
+		quality[name] = q
+	}
+
+	best, bestQ := "", 0.0
+	for _, offer := range offers {
+		q, ok := quality[offer]
+		if !ok {
+			q = quality["*"]
+		}
+		if q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+	return best
+}
+
+// Compresores reutilizables, crear uno de zstd es caro
+var (
+	gzipPool = sync.Pool{New: func() any { return gzip.NewWriter(nil) }}
+	zstdPool = sync.Pool{New: func() any {
+		enc, _ := zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1), zstd.WithLowerEncoderMem(true))
+		return enc
+	}}
+)
+
+// Compresor con Flush, lo cumplen *gzip.Writer y *zstd.Encoder
+type flushWriter interface {
+	io.WriteCloser
+	Flush() error
+}
+
+// ResponseWriter que guarda los primeros bytes hasta decidir si comprime
+type compressWriter struct {
+	http.ResponseWriter
+	encoding string
+	minSize  int
+
+	status   int
+	buf      []byte
+	decided  bool
+	hijacked bool
+	enc      flushWriter // nil si la respuesta va sin comprimir
+}
+
+func (w *compressWriter) WriteHeader(status int) {
+	if w.decided || w.hijacked {
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+	// Respuestas informativas (103 Early Hints) pasan directo
+	if status < http.StatusOK {
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+	if w.status == 0 {
+		w.status = status
+	}
+	if !w.compressible() {
+		w.decide(false)
+	}
+}
+
+func (w *compressWriter) Write(data []byte) (int, error) {
+	if !w.decided {
+		if w.status == 0 {
+			w.status = http.StatusOK
+		}
+		w.buf = append(w.buf, data...)
+		if len(w.buf) < w.minSize {
+			return len(data), nil
+		}
+		if err := w.decide(w.compressible()); err != nil {
+			return 0, err
+		}
+		return len(data), nil
+	}
+	if w.enc != nil {
+		return w.enc.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *compressWriter) Flush() {
+	if w.hijacked {
+		return
+	}
+	if !w.decided {
+		if w.status == 0 {
+			w.status = http.StatusOK
+		}
+		// Stream: el tamaño final no se conoce, se comprime si el tipo lo permite
+		if err := w.decide(w.compressible()); err != nil {
+			return
+		}
+	}
+	if w.enc != nil {
+		if err := w.enc.Flush(); err != nil {
+			return
+		}
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hj, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("hijack not supported")
+	}
+	w.hijacked = true
+	return hj.Hijack()
+}
+
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Al terminar el handler: envia lo pendiente y cierra el compresor (trailer de gzip)
+func (w *compressWriter) close() {
+	if w.hijacked {
+		return
+	}
+	if !w.decided {
+		if w.status == 0 && len(w.buf) == 0 {
+			// El handler no escribio nada, net/http responde 200 vacio como siempre
+			return
+		}
+		if err := w.decide(false); err != nil {
+			return
+		}
+	}
+	if w.enc == nil {
+		return
+	}
+	_ = w.enc.Close()
+	switch enc := w.enc.(type) {
+	case *gzip.Writer:
+		enc.Reset(nil)
+		gzipPool.Put(enc)
+	case *zstd.Encoder:
+		enc.Reset(nil)
+		zstdPool.Put(enc)
+	}
+	w.enc = nil
+}
+
+// Si la respuesta se puede comprimir segun el estado y las cabeceras
+func (w *compressWriter) compressible() bool {
+	header := w.Header()
+	if w.status == http.StatusNoContent || w.status == http.StatusNotModified || w.status == http.StatusPartialContent {
+		return false
+	}
+	if header.Get("Content-Encoding") != "" || header.Get("Content-Range") != "" {
+		return false
+	}
+	if strings.Contains(strings.ToLower(header.Get("Cache-Control")), "no-transform") {
+		return false
+	}
+	if raw := header.Get("Content-Length"); raw != "" {
+		if n, err := strconv.Atoi(raw); err == nil && n < w.minSize {
+			return false
+		}
+	}
+
+	contentType := header.Get("Content-Type")
+	if contentType == "" && len(w.buf) > 0 {
+		// Lo mismo que haria net/http con el cuerpo sin comprimir
+		contentType = http.DetectContentType(w.buf)
+		header.Set("Content-Type", contentType)
+	}
+	return !compressedType(contentType)
+}
+
+// Tipos que ya vienen comprimidos y no ganan nada
+func compressedType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	typ, subtype, _ := strings.Cut(mediaType, "/")
+	switch typ {
+	case "image":
+		return subtype != "svg+xml"
+	case "audio", "video":
+		return true
+	case "font":
+		return strings.HasPrefix(subtype, "woff")
+	}
+	switch mediaType {
+	case "application/zip", "application/gzip", "application/x-gzip", "application/zstd",
+		"application/x-7z-compressed", "application/x-rar-compressed", "application/pdf":
+		return true
+	}
+	return false
+}
+
+// Escribe las cabeceras, con o sin Content-Encoding, y los bytes guardados
+func (w *compressWriter) decide(compress bool) error {
+	w.decided = true
+	if compress {
+		header := w.Header()
+		header.Del("Content-Length")
+		header.Set("Content-Encoding", w.encoding)
+		// El cuerpo cambia, un ETag fuerte ya no lo identifica byte a byte
+		if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+			header.Set("ETag", "W/"+etag)
+		}
+		switch w.encoding {
+		case "zstd":
+			enc := zstdPool.Get().(*zstd.Encoder)
+			enc.Reset(w.ResponseWriter)
+			w.enc = enc
+		default:
+			enc := gzipPool.Get().(*gzip.Writer)
+			enc.Reset(w.ResponseWriter)
+			w.enc = enc
+		}
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+
+	buf := w.buf
+	w.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	var err error
+	if w.enc != nil {
+		_, err = w.enc.Write(buf)
+	} else {
+		_, err = w.ResponseWriter.Write(buf)
+	}
+	return err
+}
@@ -0,0 +1,221 @@
+package internal
+
+import (
+	"bytes"
+	"compress/gzip"
+	"github.com/klauspost/compress/zstd"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	offers := []string{"zstd", "gzip"}
+	tests := []struct {
+		accept string
+		want   string
+	}{
+		{"", ""},
+		{"identity", ""},
+		{"gzip", "gzip"},
+		{"gzip, zstd", "zstd"},
+		{"GZIP, deflate, br", "gzip"},
+		{"zstd;q=0.5, gzip", "gzip"},
+		{"zstd;q=0, gzip;q=0", ""},
+		{"*", "zstd"},
+		{"*;q=0.1, gzip;q=0.5", "gzip"},
+		{"zstd;q=0, *", "gzip"},
+		{"gzip;q=x", ""},
+	}
+	for _, tt := range tests {
+		if got := negotiateEncoding(tt.accept, offers); got != tt.want {
+			t.Errorf("negotiateEncoding(%q) = %q, want %q", tt.accept, got, tt.want)
+		}
+	}
+}
+
+func TestCompression(t *testing.T) {
+	c := Compression{Encodings: []string{"zstd", "gzip"}, MinSize: 100, MaxBody: 1 << 20}
+	large := strings.Repeat(`{"text":"task"}`, 50)
+
+	tests := []struct {
+		name         string
+		method       string
+		accept       string
+		handler      http.HandlerFunc
+		wantEncoding string
+		wantETag     string
+	}{
+		{"gzip", "GET", "gzip", writeBody("application/json", http.StatusOK, large), "gzip", ""},
+		{"zstd preferred", "GET", "gzip, zstd", writeBody("application/json", http.StatusOK, large), "zstd", ""},
+		{"not accepted", "GET", "br", writeBody("application/json", http.StatusOK, large), "", ""},
+		{"small body", "GET", "gzip", writeBody("application/json", http.StatusOK, "{}"), "", ""},
+		{"error status", "GET", "gzip", writeBody("application/problem+json", http.StatusInternalServerError, large), "gzip", ""},
+		{"compressed type", "GET", "gzip", writeBody("image/png", http.StatusOK, large), "", ""},
+		{"svg is text", "GET", "gzip", writeBody("image/svg+xml", http.StatusOK, large), "gzip", ""},
+		{"detected type", "GET", "gzip", writeBody("", http.StatusOK, large), "gzip", ""},
+		{"not modified", "GET", "gzip", writeBody("application/json", http.StatusNotModified, ""), "", ""},
+		{"head", "HEAD", "gzip", writeBody("application/json", http.StatusOK, large), "", ""},
+		{"no-transform", "GET", "gzip", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Cache-Control", "no-transform")
+			writeBody("application/json", http.StatusOK, large)(w, r)
+		}, "", ""},
+		{"already encoded", "GET", "gzip", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Encoding", "br")
+			writeBody("application/json", http.StatusOK, large)(w, r)
+		}, "br", ""},
+		{"strong etag becomes weak", "GET", "gzip", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("ETag", `"v1"`)
+			writeBody("application/json", http.StatusOK, large)(w, r)
+		}, "gzip", `W/"v1"`},
+		{"uncompressed etag is kept", "GET", "", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("ETag", `"v1"`)
+			writeBody("application/json", http.StatusOK, large)(w, r)
+		}, "", `"v1"`},
+		{"stream flushed before min size", "GET", "gzip", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			io.WriteString(w, "data: 1\n\n")
+			w.(http.Flusher).Flush()
+			io.WriteString(w, "data: 2\n\n")
+		}, "gzip", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, "/", nil)
+			r.Header.Set("Accept-Encoding", tt.accept)
+			rec := httptest.NewRecorder()
+			c.Middleware(tt.handler).ServeHTTP(rec, r)
+
+			if got := rec.Header().Get("Content-Encoding"); got != tt.wantEncoding {
+				t.Errorf("Content-Encoding = %q, want %q", got, tt.wantEncoding)
+			}
+			if got := rec.Header().Get("ETag"); got != tt.wantETag {
+				t.Errorf("ETag = %q, want %q", got, tt.wantETag)
+			}
+			if !strings.Contains(rec.Header().Get("Vary"), "Accept-Encoding") {
+				t.Errorf("Vary = %q", rec.Header().Get("Vary"))
+			}
+
+			// El cuerpo decodificado es el que escribio el handler
+			want := httptest.NewRecorder()
+			tt.handler(want, httptest.NewRequest(tt.method, "/", nil))
+			if rec.Code != want.Code {
+				t.Errorf("status %d, want %d", rec.Code, want.Code)
+			}
+			if tt.method == "HEAD" {
+				return
+			}
+			if got := decodeResponse(t, tt.wantEncoding, rec.Body.Bytes()); got != want.Body.String() {
+				t.Errorf("body = %q, want %q", got, want.Body.String())
+			}
+		})
+	}
+}
+
+func writeBody(contentType string, status int, body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if contentType != "" {
+			w.Header().Set("Content-Type", contentType)
+		}
+		w.WriteHeader(status)
+		io.WriteString(w, body)
+	}
+}
+
+func decodeResponse(t *testing.T, encoding string, body []byte) string {
+	t.Helper()
+	var r io.Reader
+	switch encoding {
+	case "gzip":
+		zr, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		r = zr
+	case "zstd":
+		zr, err := zstd.NewReader(bytes.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer zr.Close()
+		r = zr
+	default:
+		return string(body)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestDecompressRequest(t *testing.T) {
+	c := Compression{Encodings: []string{"gzip"}, MinSize: 100, MaxBody: 4096}
+	gz := func(data []byte) []byte {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		zw.Write(data)
+		zw.Close()
+		return buf.Bytes()
+	}
+	zs := func(data []byte) []byte {
+		enc, _ := zstd.NewWriter(nil)
+		return enc.EncodeAll(data, nil)
+	}
+	body := []byte(`{"text":"task"}`)
+	bomb := make([]byte, 1<<20)
+
+	tests := []struct {
+		name       string
+		encoding   string
+		body       []byte
+		wantStatus int
+		wantBody   string
+		tooLarge   bool // el handler recibe un error al leer
+	}{
+		{"identity", "", body, http.StatusOK, string(body), false},
+		{"gzip", "gzip", gz(body), http.StatusOK, string(body), false},
+		{"x-gzip", "x-gzip", gz(body), http.StatusOK, string(body), false},
+		{"zstd", "zstd", zs(body), http.StatusOK, string(body), false},
+		{"gzip bomb", "gzip", gz(bomb), http.StatusOK, "", true},
+		{"zstd bomb", "zstd", zs(bomb), http.StatusOK, "", true},
+		{"invalid gzip", "gzip", body, http.StatusBadRequest, "", false},
+		{"unsupported", "br", body, http.StatusUnsupportedMediaType, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []byte
+			var readErr error
+			h := c.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Content-Encoding") != "" {
+					t.Error("Content-Encoding is still set")
+				}
+				got, readErr = io.ReadAll(r.Body)
+			}))
+			r := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(tt.body))
+			if tt.encoding != "" {
+				r.Header.Set("Content-Encoding", tt.encoding)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, r)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			if tt.tooLarge {
+				if readErr == nil {
+					t.Errorf("read %d bytes, want an error past MaxBody", len(got))
+				}
+				return
+			}
+			if readErr != nil || string(got) != tt.wantBody {
+				t.Errorf("body = %q, %v, want %q", got, readErr, tt.wantBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package internal
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Cabeceras de una version deprecada de la API: Deprecation (RFC 9745) con la
+// fecha desde la que lo esta, Sunset (RFC 8594) con la fecha de retiro y Link a
+// la guia de migracion. Sin Since se envia "Deprecation: true" como en los
+// borradores previos al RFC; Sunset cero y Link vacio no se envian.
+type Deprecation struct {
+	Since  time.Time
+	Sunset time.Time
+	Link   string
+}
+
+func (d Deprecation) Middleware(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := w.Header()
+		if d.Since.IsZero() {
+			header.Set("Deprecation", "true")
+		} else {
+			header.Set("Deprecation", "@"+strconv.FormatInt(d.Since.Unix(), 10))
+		}
+		if !d.Sunset.IsZero() {
+			header.Set("Sunset", d.Sunset.UTC().Format(http.TimeFormat))
+		}
+		if d.Link != "" {
+			header.Add("Link", "<"+d.Link+`>; rel="deprecation"`)
+		}
+		h.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,37 @@
+package internal
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestDeprecation(t *testing.T) {
+	since := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2027, 4, 30, 12, 0, 0, 0, time.FixedZone("", -3*3600))
+
+	tests := []struct {
+		name        string
+		deprecation Deprecation
+		want        map[string]string
+	}{
+		{"without date", Deprecation{}, map[string]string{"Deprecation": "true", "Sunset": "", "Link": ""}},
+		{"since", Deprecation{Since: since}, map[string]string{"Deprecation": "@1792368000", "Sunset": "", "Link": ""}},
+		{"sunset in GMT", Deprecation{Since: since, Sunset: sunset}, map[string]string{"Sunset": "Fri, 30 Apr 2027 15:00:00 GMT"}},
+		{"link", Deprecation{Link: "/docs/migration"}, map[string]string{"Link": `</docs/migration>; rel="deprecation"`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h := tt.deprecation.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/task/", nil))
+
+			for name, value := range tt.want {
+				if got := rec.Header().Get(name); got != value {
+					t.Errorf("%s = %q, want %q", name, got, value)
+				}
+			}
+		})
+	}
+}
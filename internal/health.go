@@ -0,0 +1,205 @@
+package internal
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// Rutas de los probes, no pasan por autenticacion ni por el log de accesos
+var probePaths = map[string]bool{
+	"/healthz": true,
+	"/readyz":  true,
+	"/version": true,
+}
+
+func IsProbe(path string) bool {
+	return probePaths[path]
+}
+
+// Chequeo de readiness de un componente, nil si esta listo
+type Check func(ctx context.Context) error
+
+// Health atiende /healthz y /readyz. Readiness falla en cuanto empieza el apagado
+// para que el orquestador deje de enviar trafico mientras se drenan las conexiones.
+type Health struct {
+	lifecycle *Lifecycle
+
+	mu     sync.Mutex
+	names  []string
+	checks map[string]Check
+
+	// Tiempo maximo de cada chequeo de readiness
+	Timeout time.Duration
+}
+
+func NewHealth(lifecycle *Lifecycle) *Health {
+	return &Health{
+		lifecycle: lifecycle,
+		checks:    make(map[string]Check),
+		Timeout:   2 * time.Second,
+	}
+}
+
+func (h *Health) Add(name string, check Check) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.checks[name]; !ok {
+		h.names = append(h.names, name)
+	}
+	h.checks[name] = check
+}
+
+type checkResult struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type readiness struct {
+	Status string                 `json:"status"`
+	Checks map[string]checkResult `json:"checks"`
+}
+
+// Liveness godoc
+// @Summary Liveness
+// @Description Responde 200 mientras el proceso pueda atender peticiones
+// @Tags health
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Router /healthz [get]
+func (h *Health) Liveness(w http.ResponseWriter, r *http.Request) {
+	writeProbe(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// Readiness godoc
+// @Summary Readiness
+// @Description Ejecuta los chequeos (store, certificado, workers); 503 si alguno falla o si el servidor se esta apagando
+// @Tags health
+// @Produce json
+// @Success 200 {object} object
+// @Failure 503 {object} object
+// @Router /readyz [get]
+func (h *Health) Readiness(w http.ResponseWriter, r *http.Request) {
+	select {
+	case <-h.lifecycle.Stopping():
+		writeProbe(w, http.StatusServiceUnavailable, readiness{Status: "shutting down", Checks: map[string]checkResult{}})
+		return
+	default:
+	}
+
+	h.mu.Lock()
+	names := append([]string(nil), h.names...)
+	checks := make([]Check, len(names))
+	for i, name := range names {
+		checks[i] = h.checks[name]
+	}
+	h.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.Timeout)
+	defer cancel()
+
+	// Los chequeos corren en paralelo, uno colgado no bloquea la respuesta
+	errs := make([]error, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = runCheck(ctx, check)
+		}()
+	}
+	wg.Wait()
+
+	res := readiness{Status: "ready", Checks: make(map[string]checkResult, len(names))}
+	status := http.StatusOK
+	for i, name := range names {
+		if errs[i] != nil {
+			res.Checks[name] = checkResult{Status: "fail", Error: errs[i].Error()}
+			res.Status = "not ready"
+			status = http.StatusServiceUnavailable
+			continue
+		}
+		res.Checks[name] = checkResult{Status: "ok"}
+	}
+	writeProbe(w, status, res)
+}
+
+func runCheck(ctx context.Context, check Check) error {
+	done := make(chan error, 1)
+	go func() { done <- check(ctx) }()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("timed out: %w", ctx.Err())
+	}
+}
+
+// Chequeo de la ventana de validez de un certificado
+func CertCheck(cert *x509.Certificate) Check {
+	return func(ctx context.Context) error {
+		now := time.Now()
+		if now.Before(cert.NotBefore) {
+			return fmt.Errorf("certificate not valid before %s", cert.NotBefore.Format(time.RFC3339))
+		}
+		if now.After(cert.NotAfter) {
+			return fmt.Errorf("certificate expired at %s", cert.NotAfter.Format(time.RFC3339))
+		}
+		return nil
+	}
+}
+
+// Informacion de compilacion
+type buildInfo struct {
+	Path      string `json:"path"`
+	Version   string `json:"version"`
+	GoVersion string `json:"goVersion"`
+	Revision  string `json:"revision,omitempty"`
+	Time      string `json:"time,omitempty"`
+	Modified  bool   `json:"modified,omitempty"`
+}
+
+// Version godoc
+// @Summary Version
+// @Description Informacion de compilacion (modulo, version de Go y revision de VCS)
+// @Tags health
+// @Produce json
+// @Success 200 {object} object
+// @Router /version [get]
+func Version(w http.ResponseWriter, r *http.Request) {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		writeProbe(w, http.StatusOK, buildInfo{Version: "unknown"})
+		return
+	}
+
+	res := buildInfo{Path: info.Main.Path, Version: info.Main.Version, GoVersion: info.GoVersion}
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			res.Revision = s.Value
+		case "vcs.time":
+			res.Time = s.Value
+		case "vcs.modified":
+			res.Modified = s.Value == "true"
+		}
+	}
+	writeProbe(w, http.StatusOK, res)
+}
+
+func writeProbe(w http.ResponseWriter, status int, v any) {
+	js, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_, _ = w.Write(js)
+}
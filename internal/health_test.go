@@ -0,0 +1,114 @@
+package internal
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestReadiness(t *testing.T) {
+	ok := func(ctx context.Context) error { return nil }
+	failing := func(ctx context.Context) error { return errors.New("disk full") }
+	hung := func(ctx context.Context) error { <-ctx.Done(); time.Sleep(time.Second); return nil }
+
+	tests := []struct {
+		name       string
+		checks     map[string]Check
+		stopping   bool
+		wantStatus int
+		want       readiness
+	}{
+		{"ready", map[string]Check{"store": ok}, false, http.StatusOK,
+			readiness{Status: "ready", Checks: map[string]checkResult{"store": {Status: "ok"}}}},
+		{"failing check", map[string]Check{"store": ok, "dataFile": failing}, false, http.StatusServiceUnavailable,
+			readiness{Status: "not ready", Checks: map[string]checkResult{"store": {Status: "ok"}, "dataFile": {Status: "fail", Error: "disk full"}}}},
+		{"hung check times out", map[string]Check{"webhooks": hung}, false, http.StatusServiceUnavailable,
+			readiness{Status: "not ready", Checks: map[string]checkResult{"webhooks": {Status: "fail", Error: "timed out: context deadline exceeded"}}}},
+		{"shutting down", map[string]Check{"store": ok}, true, http.StatusServiceUnavailable,
+			readiness{Status: "shutting down", Checks: map[string]checkResult{}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lifecycle := NewLifecycle()
+			health := NewHealth(lifecycle)
+			health.Timeout = 20 * time.Millisecond
+			for name, check := range tt.checks {
+				health.Add(name, check)
+			}
+			if tt.stopping {
+				lifecycle.BeginShutdown()
+			}
+
+			rec := httptest.NewRecorder()
+			start := time.Now()
+			health.Readiness(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+			if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+				t.Errorf("readiness took %s", elapsed)
+			}
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Header().Get("Cache-Control"); got != "no-store" {
+				t.Errorf("Cache-Control = %q", got)
+			}
+			var got readiness
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			if got.Status != tt.want.Status || len(got.Checks) != len(tt.want.Checks) {
+				t.Fatalf("readiness = %+v, want %+v", got, tt.want)
+			}
+			for name, want := range tt.want.Checks {
+				if got.Checks[name] != want {
+					t.Errorf("check %s = %+v, want %+v", name, got.Checks[name], want)
+				}
+			}
+		})
+	}
+}
+
+func TestLiveness(t *testing.T) {
+	lifecycle := NewLifecycle()
+	lifecycle.BeginShutdown()
+	rec := httptest.NewRecorder()
+	NewHealth(lifecycle).Liveness(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != `{"status":"ok"}` {
+		t.Errorf("liveness while stopping = %d %s", rec.Code, rec.Body)
+	}
+}
+
+func TestCertCheck(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name      string
+		notBefore time.Time
+		notAfter  time.Time
+		wantErr   bool
+	}{
+		{"valid", now.Add(-time.Hour), now.Add(time.Hour), false},
+		{"not yet valid", now.Add(time.Hour), now.Add(2 * time.Hour), true},
+		{"expired", now.Add(-2 * time.Hour), now.Add(-time.Hour), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CertCheck(&x509.Certificate{NotBefore: tt.notBefore, NotAfter: tt.notAfter})(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CertCheck() = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestIsProbe(t *testing.T) {
+	for path, want := range map[string]bool{"/healthz": true, "/readyz": true, "/version": true, "/metrics": false, "/healthz/": false} {
+		if got := IsProbe(path); got != want {
+			t.Errorf("IsProbe(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
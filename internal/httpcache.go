@@ -0,0 +1,276 @@
+package internal
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/hashicorp/golang-lru/v2"
+	"hash/fnv"
+	"net/http"
+	"restServer/metrics"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache HTTP de lecturas basado en la version del store: cada cambio aumenta la
+// version, que forma el ETag y da el Last-Modified de todas las rutas cacheadas.
+// La version vuelve a empezar con cada proceso, por eso el ETag lleva tambien el
+// instante de arranque (epoch): un ETag de antes de reiniciar nunca coincide.
+// Un GET condicional que coincide responde 304 sin ejecutar el handler, y las
+// respuestas 200 se guardan en memoria (LRU) mientras la version no cambie; el
+// primer acceso con una version nueva vacia el cache.
+//
+// Va dentro de Negotiate: el ETag depende tambien del Accept, asi cada formato
+// tiene el suyo.
+type HTTPCache struct {
+	version       func() (uint64, time.Time)
+	routes        map[string]string
+	maxEntryBytes int
+	results       *metrics.CounterVec
+	epoch         string
+
+	mu      sync.Mutex
+	current uint64
+	entries *lru.Cache[string, cachedResponse] // nil = sin cache de respuestas
+}
+
+type cachedResponse struct {
+	header http.Header // solo las cabeceras que puso el handler
+	body   []byte
+}
+
+// version es la del store (TaskStore.Version); routes va de patron del ServeMux a
+// Cache-Control. entries 0 deja solo ETag y 304. reg puede ser nil.
+func NewHTTPCache(version func() (uint64, time.Time), routes map[string]string, entries, maxEntryBytes int, reg *metrics.Registry) *HTTPCache {
+	c := &HTTPCache{
+		version:       version,
+		routes:        routes,
+		maxEntryBytes: maxEntryBytes,
+		epoch:         strconv.FormatInt(time.Now().UnixNano(), 36),
+	}
+	if entries > 0 {
+		c.entries, _ = lru.New[string, cachedResponse](entries)
+	}
+	if reg != nil {
+		c.results = reg.NewCounterVec("http_cache_requests_total",
+			"Requests to cached REST routes by route pattern and result (hit, miss, not_modified).",
+			"route", "result")
+	}
+	return c
+}
+
+// Envuelve el handler de pattern si la ruta tiene politica; si no (o con c nil) lo devuelve tal cual
+func (c *HTTPCache) Route(pattern string, h http.Handler) http.Handler {
+	if c == nil {
+		return h
+	}
+	policy, ok := c.routes[pattern]
+	if !ok {
+		return h
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Primero la version: si los datos cambian mientras corre el handler la
+		// respuesta queda con una version vieja y nunca se vuelve a servir
+		version, modified := c.version()
+		etag := entityTag(c.epoch, version, r.Header.Get("Accept"))
+
+		header := w.Header()
+		validators := func() {
+			header.Set("ETag", etag)
+			header.Set("Last-Modified", modified.UTC().Format(http.TimeFormat))
+			if policy != "" {
+				header.Set("Cache-Control", policy)
+			}
+		}
+
+		if notModified(r, etag, modified) {
+			c.count(pattern, "not_modified")
+			validators()
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		// Los validadores solo acompañan a una respuesta 2xx: un error no es una
+		// version de la lista
+		vw := &validatorWriter{ResponseWriter: w, set: validators}
+		if r.Method != http.MethodGet || c.entries == nil {
+			h.ServeHTTP(vw, r)
+			vw.finish()
+			return
+		}
+
+		key := r.URL.RequestURI() + "\n" + r.Header.Get("Accept")
+		if cached, ok := c.get(version, key); ok {
+			c.count(pattern, "hit")
+			validators()
+			for name, values := range cached.header {
+				header[name] = values
+			}
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write(cached.body)
+			return
+		}
+
+		c.count(pattern, "miss")
+		rec := &cacheRecorder{ResponseWriter: vw, before: header.Clone(), limit: c.maxEntryBytes}
+		h.ServeHTTP(rec, r)
+		vw.finish()
+		if rec.status == http.StatusOK && !rec.overflow {
+			c.add(version, key, cachedResponse{header: rec.header, body: rec.body.Bytes()})
+		}
+	})
+}
+
+func (c *HTTPCache) count(route, result string) {
+	if c.results != nil {
+		c.results.Inc(route, result)
+	}
+}
+
+func (c *HTTPCache) get(version uint64, key string) (cachedResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.advance(version)
+	if version != c.current {
+		return cachedResponse{}, false
+	}
+	return c.entries.Get(key)
+}
+
+func (c *HTTPCache) add(version uint64, key string, resp cachedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.advance(version)
+	if version == c.current {
+		c.entries.Add(key, resp)
+	}
+}
+
+// Con una version mas nueva las respuestas guardadas ya no sirven; requiere c.mu
+func (c *HTTPCache) advance(version uint64) {
+	if version > c.current {
+		c.current = version
+		c.entries.Purge()
+	}
+}
+
+// ETag fuerte del arranque, la version del store y el Accept de la peticion. Es
+// fuerte porque los listados se generan siempre en el mismo orden (por Id).
+func entityTag(epoch string, version uint64, accept string) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(accept))
+	return fmt.Sprintf(`"%s-%d-%08x"`, epoch, version, h.Sum32())
+}
+
+// If-None-Match (comparacion debil, la compresion vuelve debil el ETag) y, solo si
+// no viene, If-Modified-Since
+func notModified(r *http.Request, etag string, modified time.Time) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		for _, candidate := range strings.Split(inm, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+				return true
+			}
+		}
+		return false
+	}
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" {
+		since, err := http.ParseTime(ims)
+		return err == nil && !modified.Truncate(time.Second).After(since)
+	}
+	return false
+}
+
+// Copia la respuesta mientras la escribe, hasta limit bytes
+type cacheRecorder struct {
+	http.ResponseWriter
+	before http.Header
+
+	status   int
+	header   http.Header
+	body     bytes.Buffer
+	limit    int
+	overflow bool
+}
+
+func (w *cacheRecorder) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+		header := w.Header()
+		w.header = make(http.Header)
+		for name, values := range header {
+			if !slices.Equal(w.before[name], values) {
+				w.header[name] = slices.Clone(values)
+			}
+		}
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *cacheRecorder) Write(data []byte) (int, error) {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	if !w.overflow {
+		if w.body.Len()+len(data) > w.limit {
+			w.overflow = true
+			w.body = bytes.Buffer{}
+		} else {
+			w.body.Write(data)
+		}
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *cacheRecorder) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *cacheRecorder) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Pone ETag, Last-Modified y Cache-Control al escribir el estado, solo si es 2xx
+type validatorWriter struct {
+	http.ResponseWriter
+	set     func()
+	written bool
+}
+
+func (w *validatorWriter) WriteHeader(status int) {
+	if !w.written {
+		w.written = true
+		if status >= 200 && status < 300 {
+			w.set()
+		}
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Un handler que no escribe nada responde 200 vacio
+func (w *validatorWriter) finish() {
+	if !w.written {
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+func (w *validatorWriter) Write(data []byte) (int, error) {
+	if !w.written {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *validatorWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *validatorWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,260 @@
+package internal
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNotModified(t *testing.T) {
+	const etag = `"e-3-0"`
+	modified := time.Date(2024, 1, 2, 3, 4, 5, 500, time.UTC)
+
+	tests := []struct {
+		name   string
+		header http.Header
+		want   bool
+	}{
+		{"no conditions", nil, false},
+		{"matching etag", http.Header{"If-None-Match": {etag}}, true},
+		{"weak etag", http.Header{"If-None-Match": {"W/" + etag}}, true},
+		{"etag in a list", http.Header{"If-None-Match": {`"other", ` + etag}}, true},
+		{"any", http.Header{"If-None-Match": {"*"}}, true},
+		{"other etag", http.Header{"If-None-Match": {`"e-2-0"`}}, false},
+		{"same second", http.Header{"If-Modified-Since": {modified.Format(http.TimeFormat)}}, true},
+		{"later", http.Header{"If-Modified-Since": {modified.Add(time.Hour).Format(http.TimeFormat)}}, true},
+		{"earlier", http.Header{"If-Modified-Since": {modified.Add(-time.Second).Format(http.TimeFormat)}}, false},
+		{"invalid date", http.Header{"If-Modified-Since": {"yesterday"}}, false},
+		{"etag wins over date", http.Header{"If-None-Match": {`"e-2-0"`}, "If-Modified-Since": {modified.Format(http.TimeFormat)}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header = tt.header
+			if r.Header == nil {
+				r.Header = http.Header{}
+			}
+			if got := notModified(r, etag, modified); got != tt.want {
+				t.Errorf("notModified() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEntityTag(t *testing.T) {
+	base := entityTag("e", 1, "application/json")
+	tests := []struct {
+		name  string
+		other string
+	}{
+		{"epoch", entityTag("f", 1, "application/json")},
+		{"version", entityTag("e", 2, "application/json")},
+		{"accept", entityTag("e", 1, "application/cbor")},
+	}
+	for _, tt := range tests {
+		if tt.other == base {
+			t.Errorf("changing the %s keeps the ETag %s", tt.name, base)
+		}
+	}
+	if !strings.HasPrefix(base, `"e-1-`) || !strings.HasSuffix(base, `"`) {
+		t.Errorf("entityTag() = %s", base)
+	}
+}
+
+// Store falso: version y cantidad de llamadas al handler
+type cacheFixture struct {
+	version uint64
+	calls   int
+	status  int
+	body    string
+}
+
+func (f *cacheFixture) handler(w http.ResponseWriter, r *http.Request) {
+	f.calls++
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(f.status)
+	w.Write([]byte(f.body))
+}
+
+func TestHTTPCache(t *testing.T) {
+	type step struct {
+		version    uint64 // version del store antes de la peticion
+		status     int    // respuesta del handler
+		body       string
+		method     string
+		accept     string
+		ifNone     string // "etag" envia el ETag de la respuesta anterior
+		wantStatus int
+		wantCall   bool // el handler se ejecuta
+	}
+	get := func(version uint64) step {
+		return step{version: version, status: http.StatusOK, body: "[]", method: http.MethodGet, wantStatus: http.StatusOK}
+	}
+	with := func(s step, fn func(*step)) step {
+		fn(&s)
+		return s
+	}
+
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{"miss then hit", []step{
+			with(get(1), func(s *step) { s.wantCall = true }),
+			get(1),
+		}},
+		{"each accept has its entry", []step{
+			with(get(1), func(s *step) { s.wantCall = true }),
+			with(get(1), func(s *step) { s.accept = "application/cbor"; s.wantCall = true }),
+			with(get(1), func(s *step) { s.accept = "application/cbor" }),
+		}},
+		{"new version purges", []step{
+			with(get(1), func(s *step) { s.wantCall = true }),
+			with(get(2), func(s *step) { s.wantCall = true }),
+			get(2),
+		}},
+		{"matching etag", []step{
+			with(get(1), func(s *step) { s.wantCall = true }),
+			with(get(1), func(s *step) { s.ifNone = "etag"; s.wantStatus = http.StatusNotModified }),
+		}},
+		{"weak etag from compression", []step{
+			with(get(1), func(s *step) { s.wantCall = true }),
+			with(get(1), func(s *step) { s.ifNone = "W/etag"; s.wantStatus = http.StatusNotModified }),
+		}},
+		{"stale etag", []step{
+			with(get(1), func(s *step) { s.wantCall = true }),
+			with(get(2), func(s *step) { s.ifNone = "etag"; s.wantCall = true }),
+		}},
+		{"errors are not cached", []step{
+			with(get(1), func(s *step) { s.status = http.StatusInternalServerError; s.wantStatus = s.status; s.wantCall = true }),
+			with(get(1), func(s *step) { s.wantCall = true }),
+		}},
+		{"large bodies are not cached", []step{
+			with(get(1), func(s *step) { s.body = strings.Repeat("x", 101); s.wantCall = true }),
+			with(get(1), func(s *step) { s.body = strings.Repeat("x", 101); s.wantCall = true }),
+		}},
+		{"head is not cached", []step{
+			with(get(1), func(s *step) { s.method = http.MethodHead; s.wantCall = true }),
+			with(get(1), func(s *step) { s.method = http.MethodHead; s.wantCall = true }),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &cacheFixture{}
+			modified := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+			c := NewHTTPCache(func() (uint64, time.Time) { return f.version, modified }, map[string]string{"GET /tasks": "no-cache"}, 10, 100, nil)
+			h := c.Route("GET /tasks", http.HandlerFunc(f.handler))
+
+			var etag string
+			for i, st := range tt.steps {
+				f.version, f.status, f.body = st.version, st.status, st.body
+				calls := f.calls
+
+				r := httptest.NewRequest(st.method, "/tasks", nil)
+				r.Header.Set("Accept", st.accept)
+				if st.ifNone != "" {
+					r.Header.Set("If-None-Match", strings.Replace(st.ifNone, "etag", etag, 1))
+				}
+				rec := httptest.NewRecorder()
+				h.ServeHTTP(rec, r)
+
+				if rec.Code != st.wantStatus {
+					t.Errorf("step %d: status %d, want %d", i, rec.Code, st.wantStatus)
+				}
+				if called := f.calls > calls; called != st.wantCall {
+					t.Errorf("step %d: handler called = %v, want %v", i, called, st.wantCall)
+				}
+				if rec.Code == http.StatusOK && st.method == http.MethodGet {
+					if rec.Body.String() != st.body || rec.Header().Get("Content-Type") != "application/json" {
+						t.Errorf("step %d: response %q %q", i, rec.Header().Get("Content-Type"), rec.Body)
+					}
+				}
+				if rec.Code == http.StatusOK && rec.Header().Get("Cache-Control") != "no-cache" {
+					t.Errorf("step %d: Cache-Control = %q", i, rec.Header().Get("Cache-Control"))
+				}
+				etag = rec.Header().Get("ETag")
+			}
+		})
+	}
+}
+
+// ETag, Last-Modified y Cache-Control solo acompañan a las respuestas 2xx, con y
+// sin cache de respuestas y tambien en HEAD
+func TestHTTPCacheValidators(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries int
+		method  string
+		status  int
+	}{
+		{"get", 10, http.MethodGet, http.StatusOK},
+		{"get not found", 10, http.MethodGet, http.StatusNotFound},
+		{"get error", 10, http.MethodGet, http.StatusInternalServerError},
+		{"no entries", 0, http.MethodGet, http.StatusOK},
+		{"no entries not found", 0, http.MethodGet, http.StatusNotFound},
+		{"no entries error", 0, http.MethodGet, http.StatusInternalServerError},
+		{"head", 10, http.MethodHead, http.StatusOK},
+		{"head not found", 10, http.MethodHead, http.StatusNotFound},
+		{"head error", 0, http.MethodHead, http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &cacheFixture{version: 1, status: tt.status, body: "[]"}
+			c := NewHTTPCache(func() (uint64, time.Time) { return f.version, time.Now() }, map[string]string{"GET /tasks": "no-cache"}, tt.entries, 100, nil)
+			rec := httptest.NewRecorder()
+			c.Route("GET /tasks", http.HandlerFunc(f.handler)).ServeHTTP(rec, httptest.NewRequest(tt.method, "/tasks", nil))
+
+			if rec.Code != tt.status {
+				t.Fatalf("status %d, want %d", rec.Code, tt.status)
+			}
+			want := tt.status == http.StatusOK
+			for _, name := range []string{"ETag", "Last-Modified", "Cache-Control"} {
+				if got := rec.Header().Get(name) != ""; got != want {
+					t.Errorf("%s = %q with status %d", name, rec.Header().Get(name), tt.status)
+				}
+			}
+		})
+	}
+}
+
+// Con otro proceso (otro epoch) la version vuelve a empezar: el ETag anterior no sirve
+func TestHTTPCacheEpoch(t *testing.T) {
+	version := func() (uint64, time.Time) { return 1, time.Time{} }
+	routes := map[string]string{"GET /tasks": ""}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	before := httptest.NewRecorder()
+	NewHTTPCache(version, routes, 0, 0, nil).Route("GET /tasks", handler).ServeHTTP(before, httptest.NewRequest(http.MethodGet, "/tasks", nil))
+	time.Sleep(time.Microsecond)
+
+	r := httptest.NewRequest(http.MethodGet, "/tasks", nil)
+	r.Header.Set("If-None-Match", before.Header().Get("ETag"))
+	after := httptest.NewRecorder()
+	NewHTTPCache(version, routes, 0, 0, nil).Route("GET /tasks", handler).ServeHTTP(after, r)
+
+	if after.Code != http.StatusOK {
+		t.Errorf("status %d with the ETag of a previous process, want 200", after.Code)
+	}
+	if after.Header().Get("ETag") == before.Header().Get("ETag") {
+		t.Errorf("same ETag %s in both processes", after.Header().Get("ETag"))
+	}
+}
+
+func TestHTTPCacheRoutes(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	c := NewHTTPCache(func() (uint64, time.Time) { return 1, time.Time{} }, map[string]string{"GET /tasks": ""}, 0, 0, nil)
+
+	rec := httptest.NewRecorder()
+	c.Route("GET /other", h).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/other", nil))
+	if rec.Header().Get("ETag") != "" {
+		t.Error("route without policy has an ETag")
+	}
+	var nilCache *HTTPCache
+	rec = httptest.NewRecorder()
+	nilCache.Route("GET /tasks", h).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tasks", nil))
+	if rec.Header().Get("ETag") != "" {
+		t.Error("nil cache sets an ETag")
+	}
+}
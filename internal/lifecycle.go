@@ -0,0 +1,127 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Ciclo de vida del servidor: hooks que se ejecutan al apagar y cierre de las
+// conexiones de larga duracion (SSE, websockets) para que el drenado no espere por ellas.
+type Lifecycle struct {
+	mu       sync.Mutex
+	hooks    []hook
+	flushes  []hook
+	stopping chan struct{}
+	once     sync.Once
+}
+
+type hook struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+func NewLifecycle() *Lifecycle {
+	return &Lifecycle{stopping: make(chan struct{})}
+}
+
+// Registra un hook de apagado. Se ejecutan en orden inverso al registro, como defer,
+// asi lo ultimo en iniciarse (el servidor HTTP) es lo primero en detenerse.
+func (l *Lifecycle) OnShutdown(name string, fn func(ctx context.Context) error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.hooks = append(l.hooks, hook{name: name, fn: fn})
+}
+
+// Registra un hook que guarda estado (p.ej. el archivo de datos). Se ejecutan despues
+// de todos los hooks de apagado y nunca se abandonan, aunque venza el plazo.
+func (l *Lifecycle) OnFlush(name string, fn func() error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.flushes = append(l.flushes, hook{name: name, fn: func(context.Context) error { return fn() }})
+}
+
+// Canal que se cierra cuando empieza el apagado
+func (l *Lifecycle) Stopping() <-chan struct{} {
+	return l.stopping
+}
+
+// Marca el inicio del apagado y cierra los streams abiertos. Se puede llamar mas de una vez.
+func (l *Lifecycle) BeginShutdown() {
+	l.once.Do(func() { close(l.stopping) })
+}
+
+// Ejecuta todos los hooks aunque alguno falle o se agote ctx, luego los de flush,
+// y devuelve los errores juntos
+func (l *Lifecycle) Shutdown(ctx context.Context) error {
+	l.BeginShutdown()
+
+	l.mu.Lock()
+	hooks := make([]hook, len(l.hooks))
+	copy(hooks, l.hooks)
+	flushes := make([]hook, len(l.flushes))
+	copy(flushes, l.flushes)
+	l.mu.Unlock()
+
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		h := hooks[i]
+		slog.Info("shutdown", "hook", h.name)
+		if err := runHook(ctx, h); err != nil {
+			slog.Error("shutdown hook failed", "hook", h.name, "err", err)
+			errs = append(errs, fmt.Errorf("%s: %w", h.name, err))
+		}
+	}
+	for _, h := range flushes {
+		slog.Info("shutdown", "hook", h.name)
+		if err := h.fn(context.Background()); err != nil {
+			slog.Error("shutdown hook failed", "hook", h.name, "err", err)
+			errs = append(errs, fmt.Errorf("%s: %w", h.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Un hook que no termina antes del deadline se abandona para no bloquear a los siguientes
+func runHook(ctx context.Context, h hook) error {
+	done := make(chan error, 1)
+	go func() { done <- h.fn(ctx) }()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Middleware que cancela el contexto de las peticiones de streaming (SSE y
+// websockets) al empezar el apagado. http.Server.Shutdown no las corta y sin esto
+// el drenado esperaria hasta el deadline.
+func (l *Lifecycle) Streams(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isStream(r) {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+		go func() {
+			select {
+			case <-l.stopping:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+		h.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func isStream(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "text/event-stream") ||
+		strings.EqualFold(r.Header.Get("Upgrade"), "websocket")
+}
@@ -0,0 +1,95 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestShutdownOrder(t *testing.T) {
+	l := NewLifecycle()
+	var ran []string
+	record := func(name string, err error) func(ctx context.Context) error {
+		return func(ctx context.Context) error {
+			ran = append(ran, name)
+			return err
+		}
+	}
+	l.OnFlush("save data", func() error { ran = append(ran, "save data"); return nil })
+	l.OnShutdown("webhooks", record("webhooks", nil))
+	l.OnShutdown("reminders", record("reminders", errors.New("stuck")))
+	l.OnShutdown("http", record("http", nil))
+
+	err := l.Shutdown(context.Background())
+	if want := "[http reminders webhooks save data]"; fmt.Sprint(ran) != want {
+		t.Errorf("order = %v, want %v", ran, want)
+	}
+	if err == nil || err.Error() != "reminders: stuck" {
+		t.Errorf("Shutdown() = %v", err)
+	}
+	select {
+	case <-l.Stopping():
+	default:
+		t.Error("Stopping() is not closed after Shutdown")
+	}
+}
+
+// Un hook que no termina se abandona al vencer el plazo, y los flush corren igual
+func TestShutdownDeadline(t *testing.T) {
+	l := NewLifecycle()
+	flushed := false
+	l.OnFlush("save data", func() error { flushed = true; return nil })
+	release := make(chan struct{})
+	defer close(release)
+	l.OnShutdown("hung", func(ctx context.Context) error { <-release; return nil })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := l.Shutdown(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Shutdown() = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if !flushed {
+		t.Error("flush hook did not run after the deadline")
+	}
+}
+
+func TestStreams(t *testing.T) {
+	tests := []struct {
+		name       string
+		header     string
+		value      string
+		wantCancel bool
+	}{
+		{"sse", "Accept", "text/event-stream", true},
+		{"websocket", "Upgrade", "WebSocket", true},
+		{"plain request", "Accept", "application/json", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewLifecycle()
+			canceled := make(chan bool, 1)
+			h := l.Streams(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				l.BeginShutdown()
+				l.BeginShutdown() // se puede llamar mas de una vez
+				select {
+				case <-r.Context().Done():
+					canceled <- true
+				case <-time.After(50 * time.Millisecond):
+					canceled <- false
+				}
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/graphql", nil)
+			req.Header.Set(tt.header, tt.value)
+			h.ServeHTTP(httptest.NewRecorder(), req)
+			if got := <-canceled; got != tt.wantCancel {
+				t.Errorf("request canceled = %v, want %v", got, tt.wantCancel)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package internal
+
+import (
+	"net/http"
+	"restServer/metrics"
+	"strconv"
+	"time"
+)
+
+// Metricas HTTP por patron del ServeMux ("GET /task/{id}/") y no por ruta, asi
+// los Ids no generan una serie por tarea
+type HTTPMetrics struct {
+	requests *metrics.CounterVec
+	duration *metrics.HistogramVec
+}
+
+func NewHTTPMetrics(reg *metrics.Registry) *HTTPMetrics {
+	return &HTTPMetrics{
+		requests: reg.NewCounterVec("http_requests_total",
+			"Total number of HTTP requests by method, route pattern and status code.",
+			"method", "route", "status"),
+		duration: reg.NewHistogramVec("http_request_duration_seconds",
+			"HTTP request latency by method and route pattern.",
+			metrics.DefBuckets, "method", "route"),
+	}
+}
+
+// Debe envolver directamente al mux: el patron lo fija ServeMux en la misma *http.Request
+func (m *HTTPMetrics) Middleware(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		sw := &statusWriter{ResponseWriter: w}
+
+		h.ServeHTTP(sw, r)
+
+		route := r.Pattern
+		if route == "" {
+			route = "unmatched"
+		}
+		status := sw.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		m.requests.Inc(r.Method, route, strconv.Itoa(status))
+		m.duration.Observe(time.Since(start).Seconds(), r.Method, route)
+	})
+}
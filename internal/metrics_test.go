@@ -0,0 +1,43 @@
+package internal
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"restServer/metrics"
+	"strings"
+	"testing"
+)
+
+func TestHTTPMetrics(t *testing.T) {
+	reg := metrics.NewRegistry()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /task/{id}/", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("id") == "404" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	h := NewHTTPMetrics(reg).Middleware(mux)
+
+	for _, path := range []string{"/task/1/", "/task/2/", "/task/404/", "/nothing"} {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	rec := httptest.NewRecorder()
+	reg.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := rec.Body.String()
+
+	// Una serie por patron, no por Id
+	for _, want := range []string{
+		`http_requests_total{method="GET",route="GET /task/{id}/",status="200"} 2`,
+		`http_requests_total{method="GET",route="GET /task/{id}/",status="404"} 1`,
+		`http_requests_total{method="GET",route="unmatched",status="404"} 1`,
+		`http_request_duration_seconds_count{method="GET",route="GET /task/{id}/"} 3`,
+	} {
+		if !strings.Contains(body, want+"\n") {
+			t.Errorf("missing %s in\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "/task/1/") {
+		t.Errorf("series by path:\n%s", body)
+	}
+}
@@ -1,42 +1,165 @@
 package internal
 
 import (
+	"bufio"
 	"bytes"
-	"log"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
 	"net/http"
+	"regexp"
+	"restServer/logging"
+	"restServer/problem"
+	"restServer/ratelimit"
 	"time"
 )
 
-type customResponse struct {
+// Cabecera con el Id de la peticion, se acepta del cliente o se genera
+const HeaderRequestID = "X-Request-ID"
+
+// Ids entrantes aceptados; cualquier otro se reemplaza para no ensuciar los logs
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// ResponseWriter que guarda el status y los bytes escritos, y opcionalmente una
+// copia recortada del cuerpo. Conserva Flush y Hijack para SSE y websockets.
+type statusWriter struct {
 	http.ResponseWriter
-	status int
-	body   *bytes.Buffer
+	status  int
+	written int
+
+	body  *bytes.Buffer // nil si no se guarda el cuerpo
+	limit int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(data []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	if w.body != nil && w.body.Len() <= w.limit {
+		w.body.Write(data[:min(len(data), w.limit+1-w.body.Len())])
+	}
+	n, err := w.ResponseWriter.Write(data)
+	w.written += n
+	return n, err
+}
+
+func (w *statusWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
 }
 
-func newCustomResponse(w http.ResponseWriter) *customResponse {
-	return &customResponse{ResponseWriter: w, body: &bytes.Buffer{}}
+func (w *statusWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hj, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("hijack not supported")
+	}
+	if w.status == 0 {
+		w.status = http.StatusSwitchingProtocols
+	}
+	return hj.Hijack()
 }
 
-func (rw *customResponse) WriteHeader(status int) {
-	rw.status = status
-	rw.ResponseWriter.WriteHeader(status)
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
 }
 
-func (rw *customResponse) Write(data []byte) (int, error) {
-	rw.body.Write(data)
-	return rw.ResponseWriter.Write(data)
+// Lee el cuerpo de la peticion guardando hasta limit+1 bytes para el log
+type teeBody struct {
+	io.ReadCloser
+	buf   bytes.Buffer
+	limit int
+}
+
+func (b *teeBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if room := b.limit + 1 - b.buf.Len(); room > 0 {
+		b.buf.Write(p[:min(n, room)])
+	}
+	return n, err
 }
 
 // Middlewares
-func Logging(h http.Handler) http.Handler {
+
+// Asigna el request ID (el del cliente si es valido), lo devuelve en la respuesta
+// y deja en el contexto un logger que lo incluye
+func RequestID(h http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(HeaderRequestID)
+		if !validRequestID.MatchString(id) {
+			id = newRequestID()
+		}
+		w.Header().Set(HeaderRequestID, id)
+		h.ServeHTTP(w, r.WithContext(logging.WithRequestID(r.Context(), id)))
+	})
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Log de accesos. Los cuerpos de peticion y respuesta solo se guardan con nivel
+// debug, recortados a bodyLimit bytes y sin campos sensibles.
+func Logging(h http.Handler, bodyLimit int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Los probes del orquestador llenarian el log
+		if IsProbe(r.URL.Path) {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		logger := logging.FromContext(r.Context())
+		debug := logger.Enabled(r.Context(), slog.LevelDebug)
+
 		start := time.Now()
-		rw := newCustomResponse(w)
+		rw := &statusWriter{ResponseWriter: w}
+		var reqBody *teeBody
+		if debug {
+			rw.body, rw.limit = &bytes.Buffer{}, bodyLimit
+			if r.Body != nil && r.Body != http.NoBody {
+				reqBody = &teeBody{ReadCloser: r.Body, limit: bodyLimit}
+				r.Body = reqBody
+			}
+		}
 
-		h.ServeHTTP(w, r)
+		h.ServeHTTP(rw, r)
 
-		log.Printf("method=%s path=%s status=%d time=%v response=%s\n",
-			r.Method, r.URL.Path, rw.status, time.Since(start), rw.body)
+		status := rw.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		attrs := []slog.Attr{
+			slog.String("method", r.Method),
+			slog.String("path", r.URL.Path),
+			slog.Int("status", status),
+			slog.Float64("duration_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.Int("bytes", rw.written),
+			slog.String("remote", r.RemoteAddr),
+		}
+		if debug {
+			if reqBody != nil {
+				attrs = append(attrs, slog.String("request_body", logging.Body(reqBody.buf.Bytes(), bodyLimit)))
+			}
+			attrs = append(attrs, slog.String("response_body", logging.Body(rw.body.Bytes(), bodyLimit)))
+		}
+
+		level := slog.LevelInfo
+		if status >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+		logger.LogAttrs(r.Context(), level, "request", attrs...)
 	})
 }
 
@@ -50,14 +173,19 @@ func NameResponseServer(h http.Handler, nameServer string) http.Handler {
 // Basic Auth
 func BasicAuth(uusername, password string, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if IsProbe(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
 		u, p, ok := r.BasicAuth()
 
 		if !ok || u != uusername || p != password {
 			w.Header().Set("WWW-Authenticate", `Basic realm="Restricted"`)
-			w.WriteHeader(401)
-			w.Write([]byte("Unauthorised.\n"))
+			problem.Write(w, r, problem.New(http.StatusUnauthorized, "unauthorized", "Unauthorised."))
 			return
 		}
-		next.ServeHTTP(w, r)
+		// Usuario verificado, los limites por cliente que vengan despues lo usan
+		next.ServeHTTP(w, r.WithContext(ratelimit.WithIdentity(r.Context(), u)))
 	})
 }
@@ -0,0 +1,106 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"restServer/logging"
+	"strings"
+	"testing"
+)
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name     string
+		incoming string
+		keep     bool
+	}{
+		{"generated", "", false},
+		{"client id", "req-42.a:b", true},
+		{"invalid client id", "bad id\r\nX-Injected: 1", false},
+		{"too long", strings.Repeat("a", 129), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var inContext string
+			h := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				inContext = logging.RequestID(r.Context())
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.incoming != "" {
+				req.Header.Set(HeaderRequestID, tt.incoming)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			got := rec.Header().Get(HeaderRequestID)
+			if got != inContext || !validRequestID.MatchString(got) {
+				t.Errorf("header %q, context %q", got, inContext)
+			}
+			if (got == tt.incoming) != tt.keep {
+				t.Errorf("request id = %q, keep client id %v", got, tt.keep)
+			}
+		})
+	}
+}
+
+func TestLogging(t *testing.T) {
+	tests := []struct {
+		name      string
+		level     string
+		path      string
+		status    int
+		wantLevel string
+		wantBody  bool
+	}{
+		{"info has no bodies", "info", "/task/", http.StatusOK, "INFO", false},
+		{"debug has redacted bodies", "debug", "/task/", http.StatusCreated, "INFO", true},
+		{"server errors", "info", "/task/", http.StatusInternalServerError, "ERROR", false},
+		{"probes are not logged", "info", "/healthz", http.StatusOK, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger, _ := logging.New(&buf, tt.level, logging.FormatJSON)
+			h := withLogger(logger, RequestID(Logging(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				io.ReadAll(r.Body)
+				w.WriteHeader(tt.status)
+				w.Write([]byte(`{"id":"1","secret":"out"}`))
+			}), 64)))
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(`{"text":"x","password":"in"}`))
+			h.ServeHTTP(httptest.NewRecorder(), req)
+
+			if tt.wantLevel == "" {
+				if buf.Len() > 0 {
+					t.Errorf("logged %s", buf.String())
+				}
+				return
+			}
+			var line map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+				t.Fatalf("log %q: %v", buf.String(), err)
+			}
+			if line["level"] != tt.wantLevel || line["status"] != float64(tt.status) || line["request_id"] == nil {
+				t.Errorf("log line = %v", line)
+			}
+			_, hasBody := line["request_body"]
+			if hasBody != tt.wantBody {
+				t.Errorf("request_body logged = %v, want %v", hasBody, tt.wantBody)
+			}
+			if tt.wantBody && (line["request_body"] != `{"text":"x","password":"[REDACTED]"}` || line["response_body"] != `{"id":"1","secret":"[REDACTED]"}`) {
+				t.Errorf("bodies = %v, %v", line["request_body"], line["response_body"])
+			}
+		})
+	}
+}
+
+// Usa logger en lugar del logger por defecto; RequestID le agrega el request ID
+func withLogger(logger *slog.Logger, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.ServeHTTP(w, r.WithContext(logging.WithLogger(r.Context(), logger)))
+	})
+}
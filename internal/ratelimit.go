@@ -0,0 +1,69 @@
+package internal
+
+import (
+	"fmt"
+	"net/http"
+	"restServer/logging"
+	"restServer/problem"
+	"restServer/ratelimit"
+)
+
+// Limites HTTP: Routes por patron del ServeMux ("POST /task/") y Default para el
+// resto. Cada regla de ruta tiene su propio bucket; Default es un bucket compartido
+// por todas las rutas sin regla.
+type RatePolicy struct {
+	Limiter    ratelimit.Limiter
+	Default    ratelimit.Rule
+	Routes     map[string]ratelimit.Rule
+	TrustProxy bool // tomar la IP del cliente de X-Forwarded-For
+}
+
+// Middleware de rate limit. Recibe el mux para conocer el patron antes de despachar
+// y lo deja en r.Pattern en las respuestas 429 para que metricas y trazas lo vean;
+// por eso no cambia la peticion y va pegado al mux.
+func RateLimit(mux *http.ServeMux, policy RatePolicy) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if IsProbe(r.URL.Path) {
+			mux.ServeHTTP(w, r)
+			return
+		}
+
+		client := ratelimit.ClientID(r, policy.TrustProxy)
+		_, pattern := mux.Handler(r)
+		rule, ok := policy.Routes[pattern]
+		key := "route:" + pattern + "|" + client
+		if !ok {
+			rule, key = policy.Default, "default|"+client
+		}
+		if !rule.Enabled() {
+			mux.ServeHTTP(w, r)
+			return
+		}
+
+		res, err := policy.Limiter.Allow(r.Context(), key, rule, 1)
+		if err != nil {
+			// Con el backend caido se deja pasar antes que cortar el servicio
+			logging.FromContext(r.Context()).Warn("rate limit backend", "err", err)
+			mux.ServeHTTP(w, r)
+			return
+		}
+		ratelimit.SetHeaders(w.Header(), rule, res)
+		if !res.Allowed {
+			r.Pattern = pattern
+			logging.FromContext(r.Context()).Info("rate limited", "client", client, "route", pattern)
+			problem.Write(w, r, problem.New(http.StatusTooManyRequests, "rate_limited",
+				fmt.Sprintf("rate limit of %d requests per %s exceeded, retry in %s", rule.Requests, rule.Per, w.Header().Get("Retry-After")+"s")))
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// Deja el cliente y las cabeceras de la respuesta en el contexto para los limites
+// por operacion de GraphQL
+func RateLimitClient(policy RatePolicy, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		client := ratelimit.ClientID(r, policy.TrustProxy)
+		h.ServeHTTP(w, r.WithContext(ratelimit.WithClient(r.Context(), client, w.Header())))
+	})
+}
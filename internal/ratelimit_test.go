@@ -0,0 +1,73 @@
+package internal
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"restServer/ratelimit"
+	"testing"
+	"time"
+)
+
+func TestRateLimit(t *testing.T) {
+	type request struct {
+		remoteAddr string
+		header     http.Header
+		want       int
+	}
+	basic := func(user, pass string) http.Header {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.SetBasicAuth(user, pass)
+		return r.Header
+	}
+
+	tests := []struct {
+		name     string
+		auth     bool
+		requests []request
+	}{
+		{"limit per ip", false, []request{
+			{"192.0.2.1:1", nil, http.StatusOK},
+			{"192.0.2.1:2", nil, http.StatusTooManyRequests},
+			{"192.0.2.2:1", nil, http.StatusOK},
+		}},
+		{"rotating api keys share the ip bucket", false, []request{
+			{"192.0.2.1:1", http.Header{"X-Api-Key": {"a"}}, http.StatusOK},
+			{"192.0.2.1:1", http.Header{"X-Api-Key": {"b"}}, http.StatusTooManyRequests},
+		}},
+		{"authenticated user across ips", true, []request{
+			{"192.0.2.1:1", basic("admin", "secret"), http.StatusOK},
+			{"192.0.2.2:1", basic("admin", "secret"), http.StatusTooManyRequests},
+		}},
+		{"failed logins do not use the user bucket", true, []request{
+			{"192.0.2.1:1", basic("admin", "wrong"), http.StatusUnauthorized},
+			{"192.0.2.2:1", basic("admin", "secret"), http.StatusOK},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc("GET /task/", func(w http.ResponseWriter, r *http.Request) {})
+			policy := RatePolicy{
+				Limiter: ratelimit.NewMemory(100),
+				Default: ratelimit.Rule{Requests: 1, Per: time.Minute},
+			}
+			var h http.Handler = RateLimit(mux, policy)
+			if tt.auth {
+				h = BasicAuth("admin", "secret", h)
+			}
+
+			for i, req := range tt.requests {
+				r := httptest.NewRequest(http.MethodGet, "/task/", nil)
+				r.RemoteAddr = req.remoteAddr
+				for k, v := range req.header {
+					r.Header[k] = v
+				}
+				rec := httptest.NewRecorder()
+				h.ServeHTTP(rec, r)
+				if rec.Code != req.want {
+					t.Errorf("request %d: status %d, want %d", i, rec.Code, req.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package internal
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+	"reflect"
+	"restServer/logging"
+	"runtime"
+	"strings"
+)
+
+var tracer = otel.Tracer("restServer/internal")
+
+// Middleware que abre el span de servidor de cada peticion, continuando la traza
+// del traceparent entrante. Para nombrar el span con el patron de la ruta los
+// middlewares entre este y el mux deben pasar la misma peticion (metricas, log de
+// accesos); va por fuera del log de accesos para que este tenga el trace_id.
+func Tracing(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if IsProbe(r.URL.Path) {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := tracer.Start(ctx, r.Method,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(r.Method),
+				semconv.URLPath(r.URL.Path),
+				semconv.UserAgentOriginal(r.UserAgent()),
+			),
+		)
+		defer span.End()
+		if sc := span.SpanContext(); sc.IsValid() {
+			// Los logs de la peticion se pueden cruzar con la traza
+			ctx = logging.With(ctx, "trace_id", sc.TraceID().String())
+		}
+
+		r = r.WithContext(ctx)
+		sw := &statusWriter{ResponseWriter: w}
+		h.ServeHTTP(sw, r)
+
+		status := sw.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		if r.Pattern != "" {
+			span.SetName(r.Pattern)
+			span.SetAttributes(semconv.HTTPRoute(r.Pattern))
+		}
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	})
+}
+
+// Envuelve un handler REST en un span hijo con el nombre del metodo, p.ej.
+// "TaskServer.GetTaskHandler"
+func Traced(fn http.HandlerFunc) http.Handler {
+	name := handlerName(fn)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, span := tracer.Start(r.Context(), name, trace.WithAttributes(attribute.String("code.function", name)))
+		defer span.End()
+		fn(w, r.WithContext(ctx))
+	})
+}
+
+// "restServer/server.(*TaskServer).GetTaskHandler-fm" -> "TaskServer.GetTaskHandler"
+func handlerName(fn http.HandlerFunc) string {
+	name := runtime.FuncForPC(reflect.ValueOf(fn).Pointer()).Name()
+	name = strings.TrimSuffix(name, "-fm")
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	if i := strings.Index(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	return strings.NewReplacer("(*", "", ")", "").Replace(name)
+}
@@ -0,0 +1,117 @@
+package internal
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"restServer/tracing"
+	"testing"
+)
+
+// Exportador en memoria del proveedor global. Los tracers del paquete se enlazan
+// con el primer proveedor que se instala, por eso se instala una sola vez.
+var spans = tracetest.NewInMemoryExporter()
+
+func TestMain(m *testing.M) {
+	tracing.Install(sdktrace.NewSimpleSpanProcessor(spans), tracing.Options{ServiceName: "test", SampleRatio: 1})
+	os.Exit(m.Run())
+}
+
+type fakeServer struct{}
+
+func (fakeServer) GetThingHandler(w http.ResponseWriter, r *http.Request) {}
+
+func TestHandlerName(t *testing.T) {
+	var s fakeServer
+	tests := []struct {
+		name string
+		fn   http.HandlerFunc
+		want string
+	}{
+		{"method value", s.GetThingHandler, "fakeServer.GetThingHandler"},
+		{"function", func(w http.ResponseWriter, r *http.Request) {}, "TestHandlerName.func1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := handlerName(tt.fn); got != tt.want {
+				t.Errorf("handlerName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTracing(t *testing.T) {
+	const parent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	tests := []struct {
+		name        string
+		path        string
+		traceparent string
+		status      int
+		wantSpans   []string // servidor al final: termina despues del hijo
+		wantError   bool
+	}{
+		{"route pattern", "/thing/7", "", http.StatusOK, []string{"fakeServer.GetThingHandler", "GET /thing/{id}"}, false},
+		{"server error", "/fail", "", http.StatusInternalServerError, []string{"GET /fail"}, true},
+		{"client error is not a span error", "/fail", "", http.StatusNotFound, []string{"GET /fail"}, false},
+		{"continues incoming trace", "/thing/7", parent, http.StatusOK, []string{"fakeServer.GetThingHandler", "GET /thing/{id}"}, false},
+		{"probes are not traced", "/healthz", "", http.StatusOK, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spans.Reset()
+			mux := http.NewServeMux()
+			mux.Handle("GET /thing/{id}", Traced(fakeServer{}.GetThingHandler))
+			mux.HandleFunc("GET /fail", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(tt.status) })
+			mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {})
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.traceparent != "" {
+				req.Header.Set("traceparent", tt.traceparent)
+			}
+			Tracing(mux).ServeHTTP(httptest.NewRecorder(), req)
+
+			got := spans.GetSpans()
+			if len(got) != len(tt.wantSpans) {
+				t.Fatalf("got %d spans, want %d", len(got), len(tt.wantSpans))
+			}
+			for i, name := range tt.wantSpans {
+				if got[i].Name != name {
+					t.Errorf("span %d = %q, want %q", i, got[i].Name, name)
+				}
+			}
+			if len(got) == 0 {
+				return
+			}
+
+			server := got[len(got)-1]
+			if route := attr(server.Attributes, "http.route"); route != server.Name {
+				t.Errorf("http.route = %q", route)
+			}
+			if isError := server.Status.Code == codes.Error; isError != tt.wantError {
+				t.Errorf("error status = %v, want %v", isError, tt.wantError)
+			}
+			if tt.traceparent != "" && server.SpanContext.TraceID().String() != tt.traceparent[3:35] {
+				t.Errorf("trace id = %s, want the incoming one", server.SpanContext.TraceID())
+			}
+			for _, child := range got[:len(got)-1] {
+				if child.Parent.SpanID() != server.SpanContext.SpanID() {
+					t.Errorf("span %q is not a child of the server span", child.Name)
+				}
+			}
+		})
+	}
+}
+
+func attr(attrs []attribute.KeyValue, key string) string {
+	for _, kv := range attrs {
+		if string(kv.Key) == key {
+			return kv.Value.Emit()
+		}
+	}
+	return ""
+}
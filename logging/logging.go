@@ -0,0 +1,84 @@
+// Logs estructurados con log/slog: logger por peticion en el contexto (con el
+// request ID y la traza) y redaccion de cuerpos para el nivel debug.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// Logger con el formato y nivel pedidos ("debug", "info", "warn", "error")
+func New(w io.Writer, level, format string) (*slog.Logger, error) {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("log level %q: %w", level, err)
+	}
+	opts := &slog.HandlerOptions{Level: lvl}
+
+	switch strings.ToLower(format) {
+	case FormatJSON, "":
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	case FormatText:
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	}
+	return nil, fmt.Errorf("log format %q: expected json or text", format)
+}
+
+type loggerKey struct{}
+type requestIDKey struct{}
+
+// Logger de la peticion, o el logger por defecto fuera de una peticion
+func FromContext(ctx context.Context) *slog.Logger {
+	if l, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return l
+	}
+	return slog.Default()
+}
+
+func WithLogger(ctx context.Context, l *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// Agrega atributos al logger del contexto, p.ej. el Id de la traza
+func With(ctx context.Context, args ...any) context.Context {
+	return WithLogger(ctx, FromContext(ctx).With(args...))
+}
+
+// Request ID de la peticion ("" fuera de una peticion)
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// Guarda el request ID y un logger que lo incluye en cada linea
+func WithRequestID(ctx context.Context, id string) context.Context {
+	ctx = context.WithValue(ctx, requestIDKey{}, id)
+	return With(ctx, "request_id", id)
+}
+
+// Campos JSON cuyo valor nunca se escribe en el log
+var sensitiveField = regexp.MustCompile(`(?i)("(?:password|passwd|secret|token|access_token|refresh_token|api_?key|authorization)"\s*:\s*)("(?:[^"\\]|\\.)*"|[^,}\]\s]+)`)
+
+// Cuerpo para el log: recortado a limit bytes y con los campos sensibles ocultos.
+// Funciona aunque el JSON quede cortado.
+func Body(body []byte, limit int) string {
+	truncated := false
+	if limit > 0 && len(body) > limit {
+		body = body[:limit]
+		truncated = true
+	}
+	s := sensitiveField.ReplaceAllString(string(body), `$1"[REDACTED]"`)
+	if truncated {
+		s += "...(truncated)"
+	}
+	return s
+}
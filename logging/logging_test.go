@@ -0,0 +1,73 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestBody(t *testing.T) {
+	tests := []struct {
+		name  string
+		body  string
+		limit int
+		want  string
+	}{
+		{"plain", `{"text":"buy milk"}`, 0, `{"text":"buy milk"}`},
+		{"secret string", `{"url":"https://x","secret":"s3\"cr3t"}`, 0, `{"url":"https://x","secret":"[REDACTED]"}`},
+		{"case and spacing", `{"Password" : "hunter2", "API_KEY":"k"}`, 0, `{"Password" : "[REDACTED]", "API_KEY":"[REDACTED]"}`},
+		{"non-string value", `{"token":12345,"n":1}`, 0, `{"token":"[REDACTED]","n":1}`},
+		{"nested", `{"user":{"access_token":"abc"}}`, 0, `{"user":{"access_token":"[REDACTED]"}}`},
+		{"truncated", `{"text":"a long body"}`, 10, `{"text":"a...(truncated)`},
+		{"truncated inside a secret", `{"secret":"abcdef`, 14, `{"secret":"[REDACTED]"...(truncated)`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Body([]byte(tt.body), tt.limit); got != tt.want {
+				t.Errorf("Body() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequestIDLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "info", FormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := WithRequestID(WithLogger(context.Background(), logger), "abc123")
+	ctx = With(ctx, "trace_id", "t1")
+	FromContext(ctx).Info("handled")
+	FromContext(ctx).Debug("hidden at info")
+
+	var line map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("log %q: %v", buf.String(), err)
+	}
+	if line["request_id"] != "abc123" || line["trace_id"] != "t1" || line["msg"] != "handled" {
+		t.Errorf("log line = %v", line)
+	}
+	if RequestID(ctx) != "abc123" || RequestID(context.Background()) != "" {
+		t.Errorf("RequestID() = %q", RequestID(ctx))
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		level, format string
+		wantErr       bool
+	}{
+		{"debug", "text", false},
+		{"WARN", "", false},
+		{"loud", "json", true},
+		{"info", "xml", true},
+	}
+	for _, tt := range tests {
+		if _, err := New(&bytes.Buffer{}, tt.level, tt.format); (err != nil) != tt.wantErr {
+			t.Errorf("New(%q, %q) error = %v, want error %v", tt.level, tt.format, err, tt.wantErr)
+		}
+	}
+}
@@ -9,59 +9,438 @@
 package main
 
 import (
-	"github.com/99designs/gqlgen/graphql/handler"
-	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"context"
+	"crypto/tls"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/99designs/gqlgen/graphql"
 	"github.com/99designs/gqlgen/graphql/playground"
 	s "github.com/swaggo/http-swagger"
-	"log"
+	"log/slog"
 	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"restServer/config"
 	d "restServer/docs"
 	"restServer/graph"
 	"restServer/internal"
+	"restServer/logging"
+	"restServer/metrics"
+	"restServer/ratelimit"
+	"restServer/reminder"
 	"restServer/server"
+	"restServer/taskstore"
+	"restServer/tracing"
+	"restServer/webhook"
+	"strings"
+	"syscall"
+	"time"
 )
 
 func main() {
+	// Sin subcomando se inicia el servidor, p.ej. "restServer -addr :9443"
+	cmd, args := "serve", os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		cmd, args = args[0], args[1:]
+	}
+
+	var err error
+	switch cmd {
+	case "serve":
+		err = runServe(args)
+	case "gen-cert":
+		err = runGenCert(args)
+	case "import":
+		err = runImport(args)
+	case "export":
+		err = runExport(args)
+	case "extract-queries":
+		err = runExtractQueries(args)
+	default:
+		err = fmt.Errorf("unknown command %q, expected serve, gen-cert, import, export or extract-queries", cmd)
+	}
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		// Con el logger por defecto ya reemplazado, log.Fatal saldria con nivel INFO
+		slog.Error(err.Error())
+		os.Exit(1)
+	}
+}
+
+// Subcomando serve: REST, GraphQL, playground y docs segun la configuracion
+func runServe(args []string) error {
+	cfg, err := config.Load("serve", args)
+	if err != nil {
+		return err
+	}
+
+	// Logs estructurados; log.Printf de otras librerias tambien pasa por aqui
+	logger, err := logging.New(os.Stderr, cfg.Log.Level, cfg.Log.Format)
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(logger)
 
 	// Servidor principal
 	mux := http.NewServeMux()
 
+	// Lo que se inicia registra como detenerse al apagar
+	lifecycle := internal.NewLifecycle()
+
+	// Trazas; se registra primero para que sea el ultimo hook en ejecutarse y
+	// exporte tambien los spans del apagado
+	if cfg.Tracing.Enabled {
+		tp, err := tracing.Start(context.Background(), tracing.Options{
+			Endpoint:    cfg.Tracing.Endpoint,
+			Insecure:    cfg.Tracing.Insecure,
+			ServiceName: cfg.Tracing.ServiceName,
+			SampleRatio: cfg.Tracing.SampleRatio,
+		})
+		if err != nil {
+			return err
+		}
+		lifecycle.OnShutdown("tracer provider", tp.Shutdown)
+		slog.Info("exporting traces", "endpoint", cfg.Tracing.Endpoint)
+	}
+
+	// Store en memoria, cargado del archivo de datos si se configura
+	store := taskstore.New()
+	if cfg.Storage.DataFile != "" {
+		if store, err = taskstore.LoadFile(cfg.Storage.DataFile); err != nil {
+			return err
+		}
+		lifecycle.OnFlush("save "+cfg.Storage.DataFile, func() error {
+			return store.SaveFile(cfg.Storage.DataFile)
+		})
+	}
+
+	// Metricas de Prometheus; con features.metrics=false no se mide nada
+	var registry *metrics.Registry
+	if cfg.Features.Metrics {
+		registry = newMetricsRegistry(store)
+	}
+
+	// Limites por cliente, compartidos por REST y GraphQL
+	var ratePolicy *internal.RatePolicy
+	if cfg.RateLimit.Enabled {
+		ratePolicy = newRatePolicy(cfg.RateLimit, ratelimit.NewMemory(cfg.RateLimit.MaxBuckets))
+	}
+
+	// Consultas persistidas; se cargan antes de iniciar nada para fallar pronto
+	var manifest graph.Manifest
+	if pq := cfg.GraphQL.PersistedQueries; cfg.Features.GraphQL && pq.Manifest != "" {
+		if manifest, err = graph.LoadManifest(pq.Manifest); err != nil {
+			return err
+		}
+		slog.Info("persisted queries loaded", "manifest", pq.Manifest, "operations", len(manifest), "enforce", pq.Enforce)
+	}
+
 	// Logica de negocio
-	taskServer := server.NewTaskServer()
-
-	// Verificar que el store existe
-	store := taskServer.GetStore()
-	log.Printf("TaskServer store creado: %p", store)
-
-	// GraphQL server - comparte el mismo store que REST
-	graphqlServer := handler.New(graph.NewExecutableSchema(graph.Config{
-		Resolvers: &graph.Resolver{Store: store},
-	}))
-
-	// Configurar transportes HTTP para GraphQL
-	graphqlServer.AddTransport(transport.Options{})
-	graphqlServer.AddTransport(transport.GET{})
-	graphqlServer.AddTransport(transport.POST{})
-
-	log.Printf("GraphQL Resolver store configurado: %p", store)
-
-	// Swagger config
-	d.SwaggerInfo.Schemes = []string{"https"}
-	d.SwaggerInfo.Host = "localhost:8443"
-
-	// Endpoints publicos (sin autenticación)
-	mux.Handle("/docs/", s.WrapHandler)
-
-	// GraphQL endpoints
-	mux.Handle("/graphql", graphqlServer)
-	mux.Handle("/playground", playground.Handler("GraphQL Playground", "https://localhost:8443/graphql"))
-	mux.HandleFunc("POST /task/", taskServer.CreateTaskHandler)
-	mux.HandleFunc("GET /task/{id}/", taskServer.GetTaskHandler)
-	mux.HandleFunc("GET /tag/{tag}/", taskServer.TagHandler)
-	mux.HandleFunc("GET /due/{year}/{month}/{day}/", taskServer.DueHandler)
-	mux.HandleFunc("GET /task/", taskServer.GetAllTasksHandler)
-	mux.HandleFunc("DELETE /task/", taskServer.DeleteAllTasksHandler)
-	mux.HandleFunc("DELETE /task/{id}/", taskServer.DeleteTaskHandler)
+	taskServer := server.NewTaskServerWith(store, cfg.Webhooks.Workers)
+	slog.Info("store ready", "data_file", cfg.Storage.DataFile)
+
+	// Scheduler de recordatorios sobre el mismo store
+	scheduler, err := newReminderScheduler(store, cfg.Reminders)
+	if err != nil {
+		return err
+	}
+	store.SetReminderChannels(scheduler.Channels()...)
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	schedulerDone := make(chan struct{})
+	go func() {
+		scheduler.Run(schedulerCtx)
+		close(schedulerDone)
+	}()
+	lifecycle.OnShutdown("reminder scheduler", func(ctx context.Context) error {
+		stopScheduler()
+		<-schedulerDone
+		return nil
+	})
+
+	// Workers de entrega de webhooks
+	taskServer.GetWebhooks().Start()
+	lifecycle.OnShutdown("webhook dispatcher", func(ctx context.Context) error {
+		taskServer.GetWebhooks().Stop()
+		return nil
+	})
+
+	if cfg.Features.GraphQL {
+		// Extensiones en orden: las consultas persistidas resuelven el documento y el
+		// costo se calcula antes de medir la operacion
+		var extensions []graphql.HandlerExtension
+		if manifest != nil {
+			extensions = append(extensions, graph.PersistedQueries{Manifest: manifest, Enforce: cfg.GraphQL.PersistedQueries.Enforce})
+		}
+		extensions = append(extensions, &graph.CostLimit{MaxCost: cfg.GraphQL.MaxCost, MaxDepth: cfg.GraphQL.MaxDepth})
+		if registry != nil {
+			extensions = append(extensions, graph.NewMetrics(registry))
+		}
+		extensions = append(extensions, graph.NewDeprecations(registry))
+		extensions = append(extensions, graph.Tracing{})
+		if ratePolicy != nil {
+			extensions = append(extensions, newGraphQLRateLimit(cfg.RateLimit.GraphQL, ratePolicy.Limiter))
+		}
+
+		// GraphQL server - comparte el mismo store que REST
+		apqCache := cfg.GraphQL.APQCache
+		if cfg.GraphQL.PersistedQueries.Enforce {
+			// Con enforce APQ no sirve: solo se aceptan los documentos del manifest
+			apqCache = 0
+		}
+		graphqlServer, err := graph.NewHandler(graph.HandlerOptions{
+			Store:         store,
+			Webhooks:      taskServer.GetWebhooks(),
+			Transports:    cfg.GraphQL.Transports,
+			QueryCache:    cfg.GraphQL.QueryCache,
+			APQCache:      apqCache,
+			Introspection: cfg.GraphQL.Introspection,
+			Extensions:    extensions,
+		})
+		if err != nil {
+			return err
+		}
+		var graphqlHandler http.Handler = graphqlServer
+		if ratePolicy != nil {
+			graphqlHandler = internal.RateLimitClient(*ratePolicy, graphqlHandler)
+		}
+
+		slog.Info("graphql enabled", "transports", cfg.GraphQL.Transports, "playground", cfg.Features.Playground)
+
+		// GraphQL endpoints
+		mux.Handle("/graphql", lifecycle.Streams(graphqlHandler))
+		if cfg.Features.Playground {
+			mux.Handle("/playground", playground.Handler("GraphQL Playground", "/graphql"))
+		}
+	}
+
+	if cfg.Features.Docs {
+		// Swagger config
+		d.SwaggerInfo.Schemes = []string{"http"}
+		if cfg.Server.TLS.Enabled {
+			d.SwaggerInfo.Schemes = []string{"https"}
+		}
+		d.SwaggerInfo.Host = cfg.Server.PublicHost
+
+		// Endpoints publicos (sin autenticación)
+		mux.Handle("/docs/", s.WrapHandler)
+	}
+
+	if cfg.Features.REST {
+		// v1 y las rutas sin version quedan congeladas y marcadas como deprecadas
+		since, _ := config.ParseDate(cfg.REST.V1Deprecated)
+		sunset, _ := config.ParseDate(cfg.REST.V1Sunset)
+		deprecation := internal.Deprecation{Since: since, Sunset: sunset, Link: cfg.REST.V1Link}
+
+		// ETag y Last-Modified con la version del store, 304 y respuestas en memoria
+		var cache *internal.HTTPCache
+		if cfg.HTTPCache.Enabled {
+			cache = internal.NewHTTPCache(store.Version, cfg.HTTPCache.Routes,
+				cfg.HTTPCache.Entries, cfg.HTTPCache.MaxEntryBytes, registry)
+		}
+		registerRESTv1(mux, taskServer, "", deprecation, cache)
+		registerRESTv1(mux, taskServer, "/v1", deprecation, cache)
+		registerRESTv2(mux, taskServer, cache)
+	}
+
+	// Probes del orquestador, siempre habilitados
+	health := internal.NewHealth(lifecycle)
+	mux.HandleFunc("GET /healthz", health.Liveness)
+	mux.HandleFunc("GET /readyz", health.Readiness)
+	mux.HandleFunc("GET /version", internal.Version)
+	addHealthChecks(health, cfg, store, taskServer.GetWebhooks(), scheduler)
+
+	// Middlewares globales. Rate limit y metricas van pegados al mux para leer
+	// r.Pattern; el log de accesos no cambia la peticion, asi que las trazas lo
+	// envuelven y sus lineas llevan el trace_id
+	var h http.Handler = mux
+	if ratePolicy != nil {
+		h = internal.RateLimit(mux, *ratePolicy)
+	}
+	if registry != nil {
+		mux.Handle("GET /metrics", registry)
+		h = internal.NewHTTPMetrics(registry).Middleware(h)
+	}
+	h = internal.Logging(h, cfg.Log.BodyLimit)
+	h = internal.Tracing(h)
+	if cfg.Compression.Enabled {
+		// Fuera del log de accesos, que guarda los cuerpos sin comprimir
+		h = internal.Compression{
+			Encodings: cfg.Compression.Encodings,
+			MinSize:   cfg.Compression.MinSize,
+			MaxBody:   int64(cfg.Compression.MaxBody),
+		}.Middleware(h)
+	}
+	h = internal.RequestID(h)
+	handlerResponseServer := internal.NameResponseServer(h, cfg.Server.Name)
+
+	httpServer := &http.Server{
+		Addr:              cfg.Server.Addr,
+		Handler:           handlerResponseServer,
+		ReadHeaderTimeout: cfg.Server.Timeouts.ReadHeader,
+		ReadTimeout:       cfg.Server.Timeouts.Read,
+		WriteTimeout:      cfg.Server.Timeouts.Write,
+		IdleTimeout:       cfg.Server.Timeouts.Idle,
+		ErrorLog:          slog.NewLogLogger(logger.Handler(), slog.LevelWarn),
+	}
+	if cfg.Server.TLS.Enabled {
+		// Se carga aqui y no en ListenAndServeTLS para fallar antes de iniciar y
+		// para que /readyz revise la ventana de validez del certificado servido
+		cert, err := tls.LoadX509KeyPair(cfg.Server.TLS.Cert, cfg.Server.TLS.Key) // se generan con mkcert -install localhost o con el subcomando gen-cert. Ver https://github.com/FiloSottile/mkcert
+		if err != nil {
+			return err
+		}
+		httpServer.TLSConfig = &tls.Config{Certificates: []tls.Certificate{cert}}
+		health.Add("certificate", internal.CertCheck(cert.Leaf))
+	}
+	// Se registra al final para que sea lo primero en detenerse: deja de aceptar
+	// conexiones y drena las peticiones en curso antes de parar workers y guardar
+	lifecycle.OnShutdown("http server", httpServer.Shutdown)
+	if delay := cfg.Server.Timeouts.ShutdownDelay; delay > 0 {
+		// /readyz ya responde 503; se sigue atendiendo hasta que el balanceador lo note
+		lifecycle.OnShutdown("readiness delay", func(ctx context.Context) error {
+			select {
+			case <-time.After(delay):
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}
+
+	return serve(httpServer, cfg.Server, lifecycle)
+}
+
+// Atiende hasta recibir SIGINT/SIGTERM y luego apaga ordenadamente con el plazo
+// configurado. Una segunda señal termina el proceso sin esperar.
+func serve(httpServer *http.Server, cfg config.Server, lifecycle *internal.Lifecycle) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errc := make(chan error, 1)
+	go func() {
+		if !cfg.TLS.Enabled {
+			slog.Info("listening", "url", "http://"+cfg.Addr)
+			errc <- httpServer.ListenAndServe()
+			return
+		}
+		slog.Info("listening", "url", "https://"+cfg.Addr)
+		errc <- httpServer.ListenAndServeTLS("", "") // certificado ya cargado en TLSConfig
+	}()
+
+	var serveErr error
+	select {
+	case serveErr = <-errc:
+		// No se pudo iniciar (puerto ocupado, certificado invalido); igual se detiene lo iniciado
+	case <-ctx.Done():
+		slog.Info("shutting down", "timeout", cfg.Timeouts.Shutdown)
+	}
+	stop()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Timeouts.Shutdown)
+	defer cancel()
+	shutdownErr := lifecycle.Shutdown(shutdownCtx)
+
+	if serveErr == nil {
+		serveErr = <-errc
+	}
+	if errors.Is(serveErr, http.ErrServerClosed) {
+		serveErr = nil
+	}
+	return errors.Join(serveErr, shutdownErr)
+}
+
+// Chequeos de /readyz: store, archivo de datos y workers en segundo plano
+func addHealthChecks(health *internal.Health, cfg config.Config, store *taskstore.TaskStore, webhooks *webhook.Dispatcher, scheduler *reminder.Scheduler) {
+	health.Add("store", func(ctx context.Context) error {
+		// Toma el lock del store, un bloqueo se detecta por el timeout del chequeo
+		_, _, err := store.GetAllProjects(ctx, 0, 1)
+		return err
+	})
+	if cfg.Storage.DataFile != "" {
+		health.Add("dataFile", func(ctx context.Context) error {
+			// El archivo se escribe al apagar, el directorio tiene que admitir escritura
+			f, err := os.CreateTemp(filepath.Dir(cfg.Storage.DataFile), ".readyz-*")
+			if err != nil {
+				return err
+			}
+			f.Close()
+			return os.Remove(f.Name())
+		})
+	}
+	health.Add("webhooks", func(ctx context.Context) error {
+		if alive, configured := webhooks.Workers(); alive < configured {
+			return fmt.Errorf("%d of %d delivery workers running", alive, configured)
+		}
+		return nil
+	})
+	health.Add("reminders", func(ctx context.Context) error {
+		// Con margen para una pasada lenta
+		if last := scheduler.LastRun(); time.Since(last) > 3*scheduler.Interval {
+			if last.IsZero() {
+				return errors.New("scheduler has not run yet")
+			}
+			return fmt.Errorf("scheduler last ran %s ago", time.Since(last).Round(time.Second))
+		}
+		return nil
+	})
+}
+
+// Rutas de la API REST
+// Rutas de la version 1 bajo prefix, con las cabeceras de deprecacion
+func registerRESTv1(mux *http.ServeMux, taskServer *server.TaskServer, prefix string, deprecation internal.Deprecation, cache *internal.HTTPCache) {
+	// Cada handler en su propio span, hijo del span de la peticion
+	handle := func(pattern string, fn http.HandlerFunc) {
+		method, path, _ := strings.Cut(pattern, " ")
+		pattern = method + " " + prefix + path
+		mux.Handle(pattern, deprecation.Middleware(server.NegotiateOrJSON(cache.Route(pattern, internal.Traced(fn)))))
+	}
+
+	handle("POST /task/", taskServer.CreateTaskHandler)
+	// Exacta: sin ella POST /task/batch cae en el subarbol de POST /task/ y crea una tarea
+	handle("POST /task/batch", taskServer.BatchTasksHandler)
+	handle("POST /task/batch/{$}", taskServer.BatchTasksHandler)
+	handle("GET /task/{id}/", taskServer.GetTaskHandler)
+	handle("GET /tag/{tag}/", taskServer.TagHandler)
+	handle("GET /due/{year}/{month}/{day}/", taskServer.DueHandler)
+	handle("GET /task/", taskServer.GetAllTasksHandler)
+	handle("DELETE /task/", taskServer.DeleteAllTasksHandler)
+	handle("DELETE /task/{id}/", taskServer.DeleteTaskHandler)
+
+	// Importacion y exportacion
+	handle("GET /export", taskServer.ExportHandler)
+	handle("POST /import", taskServer.ImportHandler)
+
+	// Comentarios de tareas
+	handle("GET /task/{id}/comments/", taskServer.GetCommentsHandler)
+	handle("POST /task/{id}/comments/", taskServer.CreateCommentHandler)
+	handle("PUT /task/{id}/comments/{commentId}/", taskServer.UpdateCommentHandler)
+	handle("DELETE /task/{id}/comments/{commentId}/", taskServer.DeleteCommentHandler)
+
+	// Recordatorios de tareas
+	handle("GET /task/{id}/reminders/", taskServer.GetRemindersHandler)
+	handle("PUT /task/{id}/reminders/", taskServer.SetRemindersHandler)
+
+	// Webhooks
+	handle("POST /webhook/", taskServer.CreateWebhookHandler)
+	handle("GET /webhook/", taskServer.GetWebhooksHandler)
+	handle("GET /webhook/{id}/", taskServer.GetWebhookHandler)
+	handle("PUT /webhook/{id}/", taskServer.UpdateWebhookHandler)
+	handle("DELETE /webhook/{id}/", taskServer.DeleteWebhookHandler)
+	handle("GET /webhook/{id}/deliveries/", taskServer.WebhookDeliveriesHandler)
+	handle("GET /deadletter/", taskServer.DeadLettersHandler)
+	handle("POST /deadletter/{id}/retry/", taskServer.RetryDeadLetterHandler)
+
+	// Proyectos
+	handle("POST /project/", taskServer.CreateProjectHandler)
+	handle("GET /project/", taskServer.GetAllProjectsHandler)
+	handle("GET /project/{id}/", taskServer.GetProjectHandler)
+	handle("PUT /project/{id}/", taskServer.UpdateProjectHandler)
+	handle("DELETE /project/{id}/", taskServer.DeleteProjectHandler)
+	handle("GET /project/{id}/tasks/", taskServer.GetProjectTasksHandler)
 
 	// Endpoints privados (con autenticación básica)
 	/**
@@ -72,17 +451,77 @@ func main() {
 	mux.Handle("DELETE /task/{id}/", internal.BasicAuth("admin", "1234",
 		http.HandlerFunc(taskServer.DeleteTaskHandler)))
 	*/
+}
+
+// Rutas de la version 2: recursos en plural y anidados, sin barra final. Los
+// handlers de v1 cuyas respuestas ya son camelCase se reutilizan.
+func registerRESTv2(mux *http.ServeMux, taskServer *server.TaskServer, cache *internal.HTTPCache) {
+	handle := func(pattern string, fn http.HandlerFunc) {
+		mux.Handle(pattern, server.Negotiate(cache.Route(pattern, internal.Traced(fn))))
+	}
+
+	handle("GET /v2/tasks", taskServer.ListTasksV2Handler)
+	handle("POST /v2/tasks", taskServer.CreateTaskV2Handler)
+	handle("DELETE /v2/tasks", taskServer.DeleteAllTasksV2Handler)
+	handle("POST /v2/tasks/batch", taskServer.BatchTasksV2Handler)
+	handle("GET /v2/tasks/{id}", taskServer.GetTaskV2Handler)
+	handle("DELETE /v2/tasks/{id}", taskServer.DeleteTaskHandler)
+
+	handle("GET /v2/tasks/{id}/comments", taskServer.GetCommentsV2Handler)
+	handle("POST /v2/tasks/{id}/comments", taskServer.CreateCommentV2Handler)
+	handle("GET /v2/tasks/{id}/comments/{commentId}", taskServer.GetCommentV2Handler)
+	handle("PUT /v2/tasks/{id}/comments/{commentId}", taskServer.UpdateCommentV2Handler)
+	handle("DELETE /v2/tasks/{id}/comments/{commentId}", taskServer.DeleteCommentHandler)
 
-	// Middlewares globales
-	h := internal.Logging(mux)
-	handlerResponseServer := internal.NameResponseServer(h, "Andres :D")
+	handle("GET /v2/tasks/{id}/reminders", taskServer.GetRemindersV2Handler)
+	handle("PUT /v2/tasks/{id}/reminders", taskServer.SetRemindersV2Handler)
 
-	log.Printf("Listening in https://localhost:8443\n")
-	log.Fatal(http.ListenAndServeTLS(
-		":8443",
-		"localhost.pem", // se generan con mkcert -install localhost. Para generarlos se tiene que ir a https://github.com/FiloSottile/mkcert
-		"localhost-key.pem",
-		handlerResponseServer))
+	handle("GET /v2/projects", taskServer.GetAllProjectsV2Handler)
+	handle("POST /v2/projects", taskServer.CreateProjectV2Handler)
+	handle("GET /v2/projects/{id}", taskServer.GetProjectV2Handler)
+	handle("PUT /v2/projects/{id}", taskServer.UpdateProjectV2Handler)
+	handle("DELETE /v2/projects/{id}", taskServer.DeleteProjectHandler)
+	handle("GET /v2/projects/{id}/tasks", taskServer.GetProjectTasksV2Handler)
+
+	handle("GET /v2/webhooks", taskServer.GetWebhooksHandler)
+	handle("POST /v2/webhooks", taskServer.CreateWebhookHandler)
+	handle("GET /v2/webhooks/{id}", taskServer.GetWebhookHandler)
+	handle("PUT /v2/webhooks/{id}", taskServer.UpdateWebhookHandler)
+	handle("DELETE /v2/webhooks/{id}", taskServer.DeleteWebhookHandler)
+	handle("GET /v2/webhooks/{id}/deliveries", taskServer.WebhookDeliveriesHandler)
+	handle("GET /v2/deadletters", taskServer.DeadLettersHandler)
+	handle("POST /v2/deadletters/{id}/retry", taskServer.RetryDeadLetterHandler)
+
+	handle("GET /v2/export", taskServer.ExportV2Handler)
+	handle("POST /v2/import", taskServer.ImportV2Handler)
 }
 
 // https://github.com/swaggo/swag
+
+// Configura el scheduler de recordatorios. Los canales webhook y smtp se habilitan
+// si tienen direccion configurada.
+func newReminderScheduler(store *taskstore.TaskStore, cfg config.Reminders) (*reminder.Scheduler, error) {
+	fired, err := reminder.LoadFiredLog(cfg.FiredFile)
+	if err != nil {
+		return nil, err
+	}
+
+	notifiers := []reminder.Notifier{reminder.LogNotifier{}}
+	if cfg.WebhookURL != "" {
+		notifiers = append(notifiers, reminder.NewWebhookNotifier(cfg.WebhookURL))
+	}
+	if cfg.SMTP.Addr != "" {
+		notifiers = append(notifiers, &reminder.SMTPNotifier{
+			Addr: cfg.SMTP.Addr,
+			From: cfg.SMTP.From,
+			To:   cfg.SMTP.To,
+		})
+	}
+
+	scheduler := reminder.New(store, fired, notifiers...)
+	scheduler.DefaultOffsets, err = reminder.ParseOffsetList(cfg.Offsets)
+	if err != nil {
+		return nil, err
+	}
+	return scheduler, nil
+}
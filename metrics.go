@@ -0,0 +1,34 @@
+package main
+
+import (
+	"restServer/metrics"
+	"restServer/taskstore"
+	"time"
+)
+
+// Buckets de espera del mutex del store, casi siempre por debajo del milisegundo
+var lockWaitBuckets = []float64{.00001, .00005, .0001, .0005, .001, .005, .01, .05, .1, .5}
+
+// Registro de metricas con el runtime de Go, los tamanos del store y la espera
+// de su mutex. Las metricas HTTP y GraphQL se agregan al montar cada parte.
+func newMetricsRegistry(store *taskstore.TaskStore) *metrics.Registry {
+	reg := metrics.NewRegistry()
+	reg.RegisterRuntime()
+
+	// Tamanos calculados en cada scrape, un solo Lock por scrape
+	reg.Register(metrics.CollectorFunc(func(w *metrics.Writer) {
+		stats := store.Stats()
+		w.Gauge("taskstore_tasks", "Number of tasks in the store.", float64(stats.Tasks))
+		w.Gauge("taskstore_tags", "Number of distinct tags across all tasks.", float64(stats.Tags))
+		w.Gauge("taskstore_attachment_bytes", "Total size of attachment contents in bytes.", float64(stats.AttachmentBytes))
+		w.Gauge("taskstore_projects", "Number of projects in the store.", float64(stats.Projects))
+		w.Gauge("taskstore_comments", "Number of comments in the store.", float64(stats.Comments))
+	}))
+
+	lockWait := reg.NewHistogramVec("taskstore_lock_wait_seconds",
+		"Time spent waiting to acquire the task store mutex.", lockWaitBuckets)
+	taskstore.SetLockObserver(func(d time.Duration) {
+		lockWait.Observe(d.Seconds())
+	})
+	return reg
+}
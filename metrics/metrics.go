@@ -0,0 +1,249 @@
+// Metricas en el formato de texto de Prometheus (version 0.0.4), sin dependencias
+// externas. Solo lo necesario: contadores e histogramas con etiquetas y
+// colectores que calculan sus valores al momento del scrape.
+package metrics
+
+import (
+	"bufio"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Buckets por defecto de latencias en segundos, los mismos que usa client_golang
+var DefBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Collector escribe una o mas metricas en cada scrape
+type Collector interface {
+	Collect(w *Writer)
+}
+
+// CollectorFunc permite usar una funcion como Collector
+type CollectorFunc func(w *Writer)
+
+func (f CollectorFunc) Collect(w *Writer) { f(w) }
+
+// Registry agrupa los colectores y los expone en /metrics
+type Registry struct {
+	mu         sync.Mutex
+	collectors []Collector
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+func (r *Registry) Register(c Collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.collectors = append(r.collectors, c)
+}
+
+func (r *Registry) NewCounterVec(name, help string, labels ...string) *CounterVec {
+	c := &CounterVec{name: name, help: help, labels: labels, series: make(map[string]*counterSeries)}
+	r.Register(c)
+	return c
+}
+
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	h := &HistogramVec{name: name, help: help, buckets: buckets, labels: labels, series: make(map[string]*histogramSeries)}
+	r.Register(h)
+	return h
+}
+
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	collectors := append([]Collector(nil), r.collectors...)
+	r.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	bw := bufio.NewWriter(w)
+	mw := &Writer{w: bw}
+	for _, c := range collectors {
+		c.Collect(mw)
+	}
+	_ = bw.Flush()
+}
+
+// Writer escribe muestras; las cabeceras HELP y TYPE se escriben una vez por metrica
+type Writer struct {
+	w    *bufio.Writer
+	last string
+}
+
+// Muestra de un gauge. labels son pares nombre, valor.
+func (w *Writer) Gauge(name, help string, value float64, labels ...string) {
+	w.header(name, help, "gauge")
+	w.sample(name, value, labels...)
+}
+
+// Muestra de un contador calculado por el colector
+func (w *Writer) Counter(name, help string, value float64, labels ...string) {
+	w.header(name, help, "counter")
+	w.sample(name, value, labels...)
+}
+
+func (w *Writer) header(name, help, typ string) {
+	if w.last == name {
+		return
+	}
+	w.last = name
+	w.w.WriteString("# HELP " + name + " " + escapeHelp(help) + "\n")
+	w.w.WriteString("# TYPE " + name + " " + typ + "\n")
+}
+
+func (w *Writer) sample(name string, value float64, labels ...string) {
+	w.w.WriteString(name)
+	if len(labels) > 0 {
+		w.w.WriteByte('{')
+		for i := 0; i+1 < len(labels); i += 2 {
+			if i > 0 {
+				w.w.WriteByte(',')
+			}
+			w.w.WriteString(labels[i] + `="` + escapeLabel(labels[i+1]) + `"`)
+		}
+		w.w.WriteByte('}')
+	}
+	w.w.WriteByte(' ')
+	w.w.WriteString(formatFloat(value))
+	w.w.WriteByte('\n')
+}
+
+// CounterVec es un contador con etiquetas, p.ej. peticiones por ruta y estado
+type CounterVec struct {
+	name, help string
+	labels     []string
+
+	mu     sync.Mutex
+	series map[string]*counterSeries
+}
+
+type counterSeries struct {
+	values []string
+	value  float64
+}
+
+// Suma 1 a la serie con esos valores de etiquetas (en el orden de la declaracion)
+func (c *CounterVec) Inc(values ...string) {
+	c.Add(1, values...)
+}
+
+func (c *CounterVec) Add(v float64, values ...string) {
+	key := strings.Join(values, "\xff")
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s, ok := c.series[key]
+	if !ok {
+		s = &counterSeries{values: values}
+		c.series[key] = s
+	}
+	s.value += v
+}
+
+func (c *CounterVec) Collect(w *Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	w.header(c.name, c.help, "counter")
+	for _, key := range sortedKeys(c.series) {
+		s := c.series[key]
+		w.sample(c.name, s.value, pairs(c.labels, s.values)...)
+	}
+}
+
+// HistogramVec es un histograma con etiquetas, p.ej. latencia por ruta
+type HistogramVec struct {
+	name, help string
+	buckets    []float64
+	labels     []string
+
+	mu     sync.Mutex
+	series map[string]*histogramSeries
+}
+
+type histogramSeries struct {
+	values []string
+	counts []uint64 // por bucket, no acumulado
+	count  uint64
+	sum    float64
+}
+
+func (h *HistogramVec) Observe(v float64, values ...string) {
+	key := strings.Join(values, "\xff")
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s, ok := h.series[key]
+	if !ok {
+		s = &histogramSeries{values: values, counts: make([]uint64, len(h.buckets))}
+		h.series[key] = s
+	}
+	if i := sort.SearchFloat64s(h.buckets, v); i < len(h.buckets) {
+		s.counts[i]++
+	}
+	s.count++
+	s.sum += v
+}
+
+func (h *HistogramVec) Collect(w *Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	w.header(h.name, h.help, "histogram")
+	for _, key := range sortedKeys(h.series) {
+		s := h.series[key]
+		labels := pairs(h.labels, s.values)
+
+		var cumulative uint64
+		for i, le := range h.buckets {
+			cumulative += s.counts[i]
+			w.sample(h.name+"_bucket", float64(cumulative), append(labels, "le", formatFloat(le))...)
+		}
+		w.sample(h.name+"_bucket", float64(s.count), append(labels, "le", "+Inf")...)
+		w.sample(h.name+"_sum", s.sum, labels...)
+		w.sample(h.name+"_count", float64(s.count), labels...)
+	}
+}
+
+func pairs(names, values []string) []string {
+	out := make([]string, 0, 2*len(names)+2)
+	for i, name := range names {
+		v := ""
+		if i < len(values) {
+			v = values[i]
+		}
+		out = append(out, name, v)
+	}
+	return out
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string  { return helpEscaper.Replace(s) }
+func escapeLabel(s string) string { return labelEscaper.Replace(s) }
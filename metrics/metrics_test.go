@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func scrape(t *testing.T, reg *Registry) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	reg.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if got := rec.Header().Get("Content-Type"); got != "text/plain; version=0.0.4; charset=utf-8" {
+		t.Errorf("Content-Type = %q", got)
+	}
+	return rec.Body.String()
+}
+
+func TestExposition(t *testing.T) {
+	reg := NewRegistry()
+	requests := reg.NewCounterVec("requests_total", "Requests.\nBy route.", "route", "status")
+	latency := reg.NewHistogramVec("latency_seconds", "Latency.", []float64{0.1, 1}, "route")
+	reg.Register(CollectorFunc(func(w *Writer) {
+		w.Gauge("queue_depth", "Jobs waiting.", 3, "queue", `we"ird\name`)
+		w.Gauge("queue_depth", "Jobs waiting.", 0, "queue", "empty")
+	}))
+
+	requests.Inc("/b", "200")
+	requests.Add(2, "/a", "500")
+	requests.Inc("/b", "200")
+	latency.Observe(0.05, "/a")
+	latency.Observe(0.1, "/a") // el limite es inclusivo
+	latency.Observe(0.5, "/a")
+	latency.Observe(3, "/a")
+
+	want := `# HELP requests_total Requests.\nBy route.
+# TYPE requests_total counter
+requests_total{route="/a",status="500"} 2
+requests_total{route="/b",status="200"} 2
+# HELP latency_seconds Latency.
+# TYPE latency_seconds histogram
+latency_seconds_bucket{route="/a",le="0.1"} 2
+latency_seconds_bucket{route="/a",le="1"} 3
+latency_seconds_bucket{route="/a",le="+Inf"} 4
+latency_seconds_sum{route="/a"} 3.65
+latency_seconds_count{route="/a"} 4
+# HELP queue_depth Jobs waiting.
+# TYPE queue_depth gauge
+queue_depth{queue="we\"ird\\name"} 3
+queue_depth{queue="empty"} 0
+`
+	if got := scrape(t, reg); got != want {
+		t.Errorf("exposition:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRuntimeMetrics(t *testing.T) {
+	reg := NewRegistry()
+	reg.RegisterRuntime()
+	body := scrape(t, reg)
+	for _, name := range []string{"go_goroutines ", "go_memstats_alloc_bytes ", "process_start_time_seconds ", `go_info{version="go`} {
+		if !strings.Contains(body, "\n"+name) {
+			t.Errorf("missing %s in\n%s", name, body)
+		}
+	}
+}
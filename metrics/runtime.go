@@ -0,0 +1,38 @@
+package metrics
+
+import (
+	"runtime"
+	"time"
+)
+
+var processStart = time.Now()
+
+// Registra las metricas del runtime de Go y del proceso. ReadMemStats se llama una
+// vez por scrape.
+func (r *Registry) RegisterRuntime() {
+	r.Register(CollectorFunc(func(w *Writer) {
+		var ms runtime.MemStats
+		runtime.ReadMemStats(&ms)
+
+		w.Gauge("go_info", "Information about the Go environment.", 1, "version", runtime.Version())
+		w.Gauge("go_goroutines", "Number of goroutines that currently exist.", float64(runtime.NumGoroutine()))
+		w.Gauge("go_threads", "Number of OS threads created.", float64(threads()))
+		w.Gauge("go_memstats_alloc_bytes", "Number of bytes allocated and still in use.", float64(ms.Alloc))
+		w.Counter("go_memstats_alloc_bytes_total", "Total number of bytes allocated, even if freed.", float64(ms.TotalAlloc))
+		w.Gauge("go_memstats_sys_bytes", "Number of bytes obtained from system.", float64(ms.Sys))
+		w.Gauge("go_memstats_heap_alloc_bytes", "Number of heap bytes allocated and still in use.", float64(ms.HeapAlloc))
+		w.Gauge("go_memstats_heap_inuse_bytes", "Number of heap bytes that are in use.", float64(ms.HeapInuse))
+		w.Gauge("go_memstats_heap_objects", "Number of allocated objects.", float64(ms.HeapObjects))
+		w.Counter("go_memstats_mallocs_total", "Total number of mallocs.", float64(ms.Mallocs))
+		w.Counter("go_memstats_frees_total", "Total number of frees.", float64(ms.Frees))
+		w.Counter("go_gc_cycles_total", "Number of completed GC cycles.", float64(ms.NumGC))
+		w.Counter("go_gc_pause_seconds_total", "Total GC stop-the-world pause time.", float64(ms.PauseTotalNs)/1e9)
+		w.Gauge("go_memstats_next_gc_bytes", "Heap size target of the next GC cycle.", float64(ms.NextGC))
+		w.Gauge("process_start_time_seconds", "Start time of the process since unix epoch in seconds.", float64(processStart.UnixNano())/1e9)
+	}))
+}
+
+func threads() int {
+	n, _ := runtime.ThreadCreateProfile(nil)
+	return n
+}
@@ -0,0 +1,57 @@
+package problem
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"restServer/logging"
+	"restServer/validation"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// ErrorPresenter para gqlgen: agrega a los errores de los resolvers el mismo
+// codigo y estado que tendria la respuesta REST en extensions.code y extensions.status,
+// y los errores de cada campo en extensions.errors. Los errores internos llevan un
+// mensaje generico.
+func GraphQLErrorPresenter(ctx context.Context, err error) *gqlerror.Error {
+	gqlErr := graphql.DefaultErrorPresenter(ctx, err)
+
+	// Los errores de parseo/validacion de gqlgen ya traen su propio codigo
+	if _, ok := gqlErr.Extensions["code"]; ok {
+		return gqlErr
+	}
+
+	// gqlgen no expone un error propio cuando la introspeccion esta desactivada
+	if gqlErr.Message == "introspection disabled" {
+		gqlErr.Extensions = map[string]any{"code": "introspection_disabled", "status": http.StatusForbidden}
+		return gqlErr
+	}
+
+	code, status := Classify(err)
+	var inputErr *gqlerror.Error
+	if status == http.StatusInternalServerError && errors.As(err, &inputErr) {
+		// gqlgen entrega asi los errores al leer los argumentos (p.ej. un Time mal
+		// formado), son del cliente como un cuerpo invalido en REST
+		code, status = "invalid_input", http.StatusBadRequest
+	}
+	if status == http.StatusInternalServerError {
+		// Igual que en REST, el detalle de los errores internos no se expone al cliente
+		logging.FromContext(ctx).Error("internal error", "path", gqlErr.Path.String(), "err", err)
+		gqlErr.Message = "internal server error"
+		gqlErr.Extensions = map[string]any{"code": code, "status": status}
+		return gqlErr
+	}
+	if gqlErr.Extensions == nil {
+		gqlErr.Extensions = map[string]any{}
+	}
+	gqlErr.Extensions["code"] = code
+	gqlErr.Extensions["status"] = status
+
+	var fields validation.Errors
+	if errors.As(err, &fields) {
+		gqlErr.Extensions["errors"] = fields
+	}
+	return gqlErr
+}
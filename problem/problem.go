@@ -0,0 +1,172 @@
+// Respuestas de error legibles por maquina (RFC 7807, application/problem+json)
+// compartidas por REST y GraphQL.
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"restServer/logging"
+	"restServer/taskstore"
+	"restServer/validation"
+	"restServer/webhook"
+)
+
+const ContentType = "application/problem+json"
+
+// Cuerpo problem+json. Code es estable y es el mismo que se envia en las
+// extensiones de los errores GraphQL; Extra son miembros de extension del RFC.
+type Problem struct {
+	Type     string         `json:"type"`
+	Title    string         `json:"title"`
+	Status   int            `json:"status"`
+	Detail   string         `json:"detail,omitempty"`
+	Instance string         `json:"instance,omitempty"`
+	Code     string         `json:"code"`
+	Extra    map[string]any `json:"-"`
+}
+
+// Error HTTP explicito para los casos que no vienen del store (cuerpo mal formado, etc.)
+type Error struct {
+	Status int
+	Code   string
+	Detail string
+}
+
+func (e *Error) Error() string { return e.Detail }
+
+func New(status int, code, detail string) *Error {
+	return &Error{Status: status, Code: code, Detail: detail}
+}
+
+// Codigos de los errores concretos, se usa el primero que coincida. Los errores
+// que solo pertenecen a una clase usan el codigo de la clase.
+var codes = []struct {
+	err  error
+	code string
+}{
+	{taskstore.ErrTaskNotFound, "task_not_found"},
+	{taskstore.ErrProjectNotFound, "project_not_found"},
+	{taskstore.ErrCommentNotFound, "comment_not_found"},
+	{webhook.ErrNotFound, "webhook_not_found"},
+	{taskstore.ErrProjectHasTasks, "project_has_tasks"},
+	{taskstore.ErrBatchAborted, "batch_aborted"},
+	{taskstore.ErrInvalidReminder, "invalid_reminder"},
+	{taskstore.ErrInvalidChannel, "invalid_reminder_channel"},
+	{taskstore.ErrUnsupportedBatch, "unsupported_operation"},
+	{taskstore.ErrImportInvalid, "import_invalid"},
+	{taskstore.ErrInvalidDeleteMode, "invalid_delete_mode"},
+	{webhook.ErrInvalid, "invalid_webhook"},
+	{taskstore.ErrValidation, "validation_failed"},
+	{taskstore.ErrConflict, "conflict"},
+	{taskstore.ErrNotFound, "not_found"},
+}
+
+// Codigo estable y estado HTTP de un error. La clase decide el estado, por lo que
+// p.ej. un ErrProjectNotFound envuelto en ErrValidation responde 422.
+func Classify(err error) (string, int) {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code, e.Status
+	}
+
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, taskstore.ErrValidation):
+		status = http.StatusUnprocessableEntity
+	case errors.Is(err, taskstore.ErrConflict):
+		status = http.StatusConflict
+	case errors.Is(err, taskstore.ErrNotFound):
+		status = http.StatusNotFound
+	}
+	if status == http.StatusInternalServerError {
+		return "internal", status
+	}
+
+	for _, c := range codes {
+		if errors.Is(err, c.err) {
+			return c.code, status
+		}
+	}
+	return "internal", http.StatusInternalServerError
+}
+
+// Problem que corresponde a un error. Los errores internos van al logger por defecto.
+func FromError(err error, instance string) Problem {
+	return fromError(slog.Default(), err, instance)
+}
+
+func fromError(logger *slog.Logger, err error, instance string) Problem {
+	code, status := Classify(err)
+
+	detail := err.Error()
+	if status == http.StatusInternalServerError {
+		// Los errores internos no se exponen al cliente
+		logger.Error("internal error", "instance", instance, "err", err)
+		detail = ""
+	}
+
+	p := Problem{
+		Type:     "/problems/" + code,
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: instance,
+		Code:     code,
+	}
+
+	// Los errores de validacion incluyen cada campo con su JSON pointer
+	var fields validation.Errors
+	if errors.As(err, &fields) {
+		p.Extra = map[string]any{"errors": fields}
+	}
+	return p
+}
+
+// Responde el error como application/problem+json
+func Write(w http.ResponseWriter, r *http.Request, err error) {
+	WriteProblem(w, fromError(logging.FromContext(r.Context()), err, r.URL.Path))
+}
+
+// Igual que Write pero con miembros de extension (p.ej. el detalle de un batch)
+func WriteWith(w http.ResponseWriter, r *http.Request, err error, extra map[string]any) {
+	p := fromError(logging.FromContext(r.Context()), err, r.URL.Path)
+	if p.Extra == nil {
+		p.Extra = make(map[string]any, len(extra))
+	}
+	for k, v := range extra {
+		p.Extra[k] = v
+	}
+	WriteProblem(w, p)
+}
+
+func WriteProblem(w http.ResponseWriter, p Problem) {
+	js, err := json.Marshal(p)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+	_, _ = w.Write(js)
+}
+
+// Incluye los miembros de extension al mismo nivel que los del RFC
+func (p Problem) MarshalJSON() ([]byte, error) {
+	type plain Problem
+	js, err := json.Marshal(plain(p))
+	if err != nil || len(p.Extra) == 0 {
+		return js, err
+	}
+
+	members := make(map[string]any, len(p.Extra)+6)
+	for k, v := range p.Extra {
+		members[k] = v
+	}
+	if err := json.Unmarshal(js, &members); err != nil {
+		return nil, err
+	}
+	return json.Marshal(members)
+}
@@ -0,0 +1,136 @@
+package problem
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"restServer/taskstore"
+	"restServer/validation"
+	"restServer/webhook"
+	"testing"
+
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantCode   string
+		wantStatus int
+	}{
+		{"concrete not found", taskstore.ErrTaskNotFound, "task_not_found", http.StatusNotFound},
+		{"wrapped", fmt.Errorf("loading: %w", taskstore.ErrCommentNotFound), "comment_not_found", http.StatusNotFound},
+		{"other package", webhook.ErrNotFound, "webhook_not_found", http.StatusNotFound},
+		{"conflict", taskstore.ErrProjectHasTasks, "project_has_tasks", http.StatusConflict},
+		{"class only", taskstore.NewError(taskstore.ErrConflict, "busy"), "conflict", http.StatusConflict},
+		{"field errors", validation.Errors{{Pointer: "/text", Code: "required", Message: "required"}}, "validation_failed", http.StatusUnprocessableEntity},
+		// La clase decide el estado: un proyecto inexistente al crear una tarea es un 422
+		{"class wins", fmt.Errorf("%w: %w", taskstore.ErrValidation, taskstore.ErrProjectNotFound), "project_not_found", http.StatusUnprocessableEntity},
+		{"explicit", New(http.StatusBadRequest, "invalid_body", "bad json"), "invalid_body", http.StatusBadRequest},
+		{"unknown", errors.New("disk full"), "internal", http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, status := Classify(tt.err)
+			if code != tt.wantCode || status != tt.wantStatus {
+				t.Errorf("Classify() = %q, %d, want %q, %d", code, status, tt.wantCode, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		extra      map[string]any
+		wantStatus int
+		wantBody   map[string]any
+	}{
+		{
+			name:       "not found",
+			err:        taskstore.ErrTaskNotFound,
+			wantStatus: http.StatusNotFound,
+			wantBody: map[string]any{
+				"type": "/problems/task_not_found", "title": "Not Found", "status": 404.0,
+				"detail": "task not found", "instance": "/task/9/", "code": "task_not_found",
+			},
+		},
+		{
+			name:       "internal detail is hidden",
+			err:        errors.New("open data.json: permission denied"),
+			wantStatus: http.StatusInternalServerError,
+			wantBody: map[string]any{
+				"type": "/problems/internal", "title": "Internal Server Error", "status": 500.0,
+				"instance": "/task/9/", "code": "internal",
+			},
+		},
+		{
+			name:       "extension members at the top level",
+			err:        validation.Errors{{Pointer: "/text", Code: "required", Message: "text is required"}},
+			extra:      map[string]any{"applied": false},
+			wantStatus: http.StatusUnprocessableEntity,
+			wantBody: map[string]any{
+				"type": "/problems/validation_failed", "title": "Unprocessable Entity", "status": 422.0,
+				"detail": "validation failed: /text: text is required", "instance": "/task/9/", "code": "validation_failed",
+				"applied": false,
+				"errors":  []any{map[string]any{"pointer": "/text", "code": "required", "message": "text is required"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			WriteWith(rec, httptest.NewRequest("GET", "/task/9/", nil), tt.err, tt.extra)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Header().Get("Content-Type"); got != ContentType {
+				t.Errorf("Content-Type = %q", got)
+			}
+			var body map[string]any
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if got, want := fmt.Sprint(body), fmt.Sprint(tt.wantBody); got != want {
+				t.Errorf("body = %s\nwant   %s", got, want)
+			}
+		})
+	}
+}
+
+func TestGraphQLErrorPresenter(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantMessage string
+		wantExt     map[string]any
+	}{
+		{"store error", taskstore.ErrTaskNotFound, "task not found", map[string]any{"code": "task_not_found", "status": 404}},
+		{"internal", errors.New("disk full"), "internal server error", map[string]any{"code": "internal", "status": 500}},
+		{"bad argument", gqlerror.Errorf("cannot parse time"), "cannot parse time", map[string]any{"code": "invalid_input", "status": 400}},
+		{"introspection", gqlerror.Errorf("introspection disabled"), "introspection disabled", map[string]any{"code": "introspection_disabled", "status": 403}},
+		{
+			"field errors",
+			validation.Errors{{Pointer: "/text", Code: "required", Message: "required"}},
+			"validation failed: /text: required",
+			map[string]any{"code": "validation_failed", "status": 422, "errors": validation.Errors{{Pointer: "/text", Code: "required", Message: "required"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := GraphQLErrorPresenter(context.Background(), tt.err)
+			if got.Message != tt.wantMessage {
+				t.Errorf("message = %q, want %q", got.Message, tt.wantMessage)
+			}
+			if fmt.Sprint(got.Extensions) != fmt.Sprint(tt.wantExt) {
+				t.Errorf("extensions = %v, want %v", got.Extensions, tt.wantExt)
+			}
+		})
+	}
+}
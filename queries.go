@@ -0,0 +1,227 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/formatter"
+	"github.com/vektah/gqlparser/v2/parser"
+	"github.com/vektah/gqlparser/v2/validator"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"restServer/graph"
+	"slices"
+	"strings"
+)
+
+// Plantillas gql`...` y graphql`...` del codigo de los clientes; las
+// interpolaciones ${...} suelen ser fragmentos que se definen en otra plantilla
+var (
+	taggedTemplate = regexp.MustCompile("(?s)\\b(?:gql|graphql)\\s*`([^`]*)`")
+	interpolation  = regexp.MustCompile(`\$\{[^}]*\}`)
+)
+
+// Subcomando extract-queries: extrae las operaciones de archivos .graphql y del
+// codigo de los clientes, las valida contra el schema y las agrega al manifest de
+// consultas persistidas. El hash es el del documento en forma canonica, que los
+// clientes no pueden calcular desde su codigo; por eso tambien escribe un mapa de
+// nombre de operacion a hash para que lo envien en extensions.persistedQuery
+func runExtractQueries(args []string) error {
+	fset := flag.NewFlagSet("extract-queries", flag.ExitOnError)
+	out := fset.String("out", "persisted-queries.json", "manifest a actualizar (se crea si no existe)")
+	operations := fset.String("operations", "persisted-operations.json", "mapa de nombre de operacion a hash para los clientes (vacio: no se escribe)")
+	replace := fset.Bool("replace", false, "descartar las operaciones que ya tenian el manifest y el mapa")
+	exts := fset.String("ext", ".graphql,.gql,.js,.jsx,.ts,.tsx", "extensiones de archivo a revisar")
+	fset.Usage = func() {
+		fmt.Fprintln(fset.Output(), "uso: extract-queries [flags] [archivo o directorio ...]")
+		fset.PrintDefaults()
+	}
+	fset.Parse(args)
+
+	paths := fset.Args()
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+	sources, err := querySources(paths, strings.Split(*exts, ","))
+	if err != nil {
+		return err
+	}
+
+	var ops ast.OperationList
+	fragments := map[string]*ast.FragmentDefinition{}
+	for _, src := range sources {
+		doc, err := parser.ParseQuery(src)
+		if err != nil {
+			return err
+		}
+		ops = append(ops, doc.Operations...)
+		for _, f := range doc.Fragments {
+			if prev, ok := fragments[f.Name]; ok && formatFragment(prev) != formatFragment(f) {
+				return fmt.Errorf("%s: fragment %s is defined twice with different selections", f.Position.Src.Name, f.Name)
+			}
+			fragments[f.Name] = f
+		}
+	}
+	if len(ops) == 0 {
+		return errors.New("extract-queries: no operations found")
+	}
+
+	manifest := graph.Manifest{}
+	hashes := map[string]string{}
+	if !*replace {
+		if m, err := graph.LoadManifest(*out); err == nil {
+			manifest = m
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		if *operations != "" {
+			if err := readOperationHashes(*operations, hashes); err != nil {
+				return err
+			}
+		}
+	}
+	named := map[string]string{} // operaciones de esta ejecucion
+
+	schema := graph.NewExecutableSchema(graph.Config{}).Schema()
+	added := 0
+	for _, op := range ops {
+		doc := &ast.QueryDocument{Operations: ast.OperationList{op}}
+		for _, name := range usedFragments(op.SelectionSet, fragments, nil) {
+			doc.Fragments = append(doc.Fragments, fragments[name])
+		}
+		if errs := validator.Validate(schema, doc); len(errs) > 0 {
+			return fmt.Errorf("%s: operation %q: %w", op.Position.Src.Name, op.Name, errs)
+		}
+
+		query := format(doc)
+		hash := graph.QueryHash(query)
+		if _, ok := manifest[hash]; !ok {
+			added++
+		}
+		manifest[hash] = query
+		if op.Name != "" {
+			if prev, ok := named[op.Name]; ok && prev != hash {
+				return fmt.Errorf("%s: operation %s is defined twice with different documents", op.Position.Src.Name, op.Name)
+			}
+			named[op.Name] = hash
+			hashes[op.Name] = hash
+		} else if *operations != "" {
+			fmt.Fprintf(os.Stderr, "%s: anonymous operation is not in %s, name it to send it by hash\n", op.Position.Src.Name, *operations)
+		}
+		fmt.Fprintf(os.Stderr, "%s %s %s\n", hash, op.Operation, cmp.Or(op.Name, "(anonymous)"))
+	}
+
+	var buf bytes.Buffer
+	if err := manifest.Write(&buf); err != nil {
+		return err
+	}
+	if err := os.WriteFile(*out, buf.Bytes(), 0o644); err != nil {
+		return err
+	}
+	if *operations != "" {
+		data, err := json.MarshalIndent(hashes, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(*operations, append(data, '\n'), 0o644); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(os.Stderr, "%d operations, %d new, %d in %s\n", len(ops), added, len(manifest), *out)
+	return nil
+}
+
+// Agrega a hashes el mapa de operaciones que ya habia en path, si existe
+func readOperationHashes(path string, hashes map[string]string) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, &hashes); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// Documentos GraphQL de los archivos indicados, recorriendo los directorios
+func querySources(paths, exts []string) ([]*ast.Source, error) {
+	var sources []*ast.Source
+	for _, root := range paths {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				if path != root && (d.Name() == "node_modules" || strings.HasPrefix(d.Name(), ".")) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			ext := filepath.Ext(path)
+			if path != root && !slices.Contains(exts, ext) {
+				return nil
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			if ext == ".graphql" || ext == ".gql" {
+				sources = append(sources, &ast.Source{Name: path, Input: string(data)})
+				return nil
+			}
+			for i, m := range taggedTemplate.FindAllStringSubmatch(string(data), -1) {
+				sources = append(sources, &ast.Source{
+					Name:  fmt.Sprintf("%s#%d", path, i+1),
+					Input: interpolation.ReplaceAllString(m[1], ""),
+				})
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return sources, nil
+}
+
+// Fragmentos usados por la seleccion, incluidos los de otros fragmentos, en orden
+// de aparicion
+func usedFragments(set ast.SelectionSet, fragments map[string]*ast.FragmentDefinition, seen []string) []string {
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *ast.Field:
+			seen = usedFragments(sel.SelectionSet, fragments, seen)
+		case *ast.InlineFragment:
+			seen = usedFragments(sel.SelectionSet, fragments, seen)
+		case *ast.FragmentSpread:
+			f, ok := fragments[sel.Name]
+			if !ok || slices.Contains(seen, sel.Name) {
+				continue
+			}
+			seen = usedFragments(f.SelectionSet, fragments, append(seen, sel.Name))
+		}
+	}
+	return seen
+}
+
+// Forma canonica del documento; su hash es la clave del manifest y el valor del
+// mapa de operaciones
+func format(doc *ast.QueryDocument) string {
+	var buf bytes.Buffer
+	formatter.NewFormatter(&buf, formatter.WithIndent("  ")).FormatQueryDocument(doc)
+	return buf.String()
+}
+
+func formatFragment(f *ast.FragmentDefinition) string {
+	return format(&ast.QueryDocument{Fragments: ast.FragmentDefinitionList{f}})
+}
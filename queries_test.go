@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"restServer/graph"
+	"testing"
+)
+
+func TestExtractQueries(t *testing.T) {
+	const fragment = "fragment TaskFields on Task { text }"
+
+	tests := []struct {
+		name     string
+		files    map[string]string
+		existing map[string]string // mapa de operaciones anterior
+		wantOps  []string          // nombres en el mapa de operaciones
+		wantErr  bool
+	}{
+		{
+			name: "graphql file",
+			files: map[string]string{
+				"tasks.graphql": "query GetTask($id: ID!) { task(id: $id) { text } }",
+			},
+			wantOps: []string{"GetTask"},
+		},
+		{
+			name: "tagged templates with a shared fragment",
+			files: map[string]string{
+				"fragments.ts": "export const f = gql`" + fragment + "`",
+				"client.ts":    "const q = gql`query GetTask($id: ID!) { task(id: $id) { ...TaskFields } } ${f}`",
+			},
+			wantOps: []string{"GetTask"},
+		},
+		{
+			name: "anonymous operations are not in the map",
+			files: map[string]string{
+				"tasks.graphql": "query GetTask($id: ID!) { task(id: $id) { text } }\n{ projects { name } }",
+			},
+			wantOps: []string{"GetTask"},
+		},
+		{
+			name: "existing map is kept",
+			files: map[string]string{
+				"tasks.graphql": "query GetTask($id: ID!) { task(id: $id) { text } }",
+			},
+			existing: map[string]string{"Old": "0000"},
+			wantOps:  []string{"GetTask", "Old"},
+		},
+		{
+			name: "same name with different documents",
+			files: map[string]string{
+				"a.graphql": "query GetTask($id: ID!) { task(id: $id) { text } }",
+				"b.graphql": "query GetTask($id: ID!) { task(id: $id) { due } }",
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid against the schema",
+			files: map[string]string{
+				"tasks.graphql": "query GetTask { missing }",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			src := filepath.Join(dir, "src")
+			if err := os.Mkdir(src, 0o755); err != nil {
+				t.Fatal(err)
+			}
+			for name, data := range tt.files {
+				if err := os.WriteFile(filepath.Join(src, name), []byte(data), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			out := filepath.Join(dir, "persisted-queries.json")
+			operations := filepath.Join(dir, "persisted-operations.json")
+			if tt.existing != nil {
+				data, _ := json.Marshal(tt.existing)
+				if err := os.WriteFile(operations, data, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := runExtractQueries([]string{"-out", out, "-operations", operations, src})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("runExtractQueries() error = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			manifest, err := graph.LoadManifest(out)
+			if err != nil {
+				t.Fatal(err)
+			}
+			data, err := os.ReadFile(operations)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var hashes map[string]string
+			if err := json.Unmarshal(data, &hashes); err != nil {
+				t.Fatal(err)
+			}
+			if len(hashes) != len(tt.wantOps) {
+				t.Errorf("operations = %v, want %v", hashes, tt.wantOps)
+			}
+			for _, name := range tt.wantOps {
+				hash, ok := hashes[name]
+				if !ok {
+					t.Errorf("operation %s is not in the map", name)
+				}
+				// Las operaciones de esta ejecucion se pueden enviar por hash
+				if _, old := tt.existing[name]; !old {
+					if _, ok := manifest[hash]; !ok {
+						t.Errorf("hash %s of %s is not in the manifest", hash, name)
+					}
+				}
+			}
+		})
+	}
+}
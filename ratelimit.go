@@ -0,0 +1,39 @@
+package main
+
+import (
+	"restServer/config"
+	"restServer/graph"
+	"restServer/internal"
+	"restServer/ratelimit"
+)
+
+// Politica HTTP a partir de la configuracion. limiter es el backend de los buckets;
+// con varias instancias deberia ser uno compartido.
+func newRatePolicy(cfg config.RateLimit, limiter ratelimit.Limiter) *internal.RatePolicy {
+	policy := &internal.RatePolicy{
+		Limiter:    limiter,
+		Default:    rule(cfg.Default),
+		Routes:     make(map[string]ratelimit.Rule, len(cfg.Routes)),
+		TrustProxy: cfg.TrustProxy,
+	}
+	for pattern, rate := range cfg.Routes {
+		policy.Routes[pattern] = rule(rate)
+	}
+	return policy
+}
+
+func newGraphQLRateLimit(cfg config.GraphQLRates, limiter ratelimit.Limiter) graph.RateLimit {
+	ext := graph.RateLimit{
+		Limiter:    limiter,
+		Default:    rule(cfg.Default),
+		Operations: make(map[string]ratelimit.Rule, len(cfg.Operations)),
+	}
+	for op, rate := range cfg.Operations {
+		ext.Operations[op] = rule(rate)
+	}
+	return ext
+}
+
+func rule(rate config.Rate) ratelimit.Rule {
+	return ratelimit.Rule{Requests: rate.Requests, Per: rate.Per, Burst: rate.Burst}
+}
@@ -0,0 +1,81 @@
+package ratelimit
+
+import (
+	"context"
+	"github.com/hashicorp/golang-lru/v2/simplelru"
+	"math"
+	"sync"
+	"time"
+)
+
+// Limiter en memoria, valido para una sola instancia. Los buckets que se llenaron
+// de nuevo se descartan periodicamente; equivalen a uno nuevo. La cantidad de
+// buckets tiene un maximo: al llegar a el se descarta el usado hace mas tiempo.
+type Memory struct {
+	sync.Mutex
+	buckets   *simplelru.LRU[string, *bucket]
+	lastSweep time.Time
+
+	now func() time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	full   time.Time // momento en que vuelve a estar lleno
+}
+
+// Intervalo entre limpiezas de buckets llenos
+const sweepEvery = time.Minute
+
+func NewMemory(maxBuckets int) *Memory {
+	buckets, _ := simplelru.NewLRU[string, *bucket](max(maxBuckets, 1), nil)
+	return &Memory{buckets: buckets, now: time.Now}
+}
+
+func (m *Memory) Allow(ctx context.Context, key string, rule Rule, n int) (Result, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	now := m.now()
+	if now.Sub(m.lastSweep) > sweepEvery {
+		m.sweep(now)
+	}
+
+	capacity := float64(rule.capacity())
+	rate := rule.rate()
+
+	b, ok := m.buckets.Get(key)
+	if !ok {
+		b = &bucket{tokens: capacity, last: now}
+		m.buckets.Add(key, b)
+	}
+	b.tokens = math.Min(capacity, b.tokens+now.Sub(b.last).Seconds()*rate)
+	b.last = now
+
+	res := Result{Limit: rule.capacity()}
+	if b.tokens >= float64(n) {
+		b.tokens -= float64(n)
+		res.Allowed = true
+	} else {
+		res.RetryAfter = secondsToDuration((float64(n) - b.tokens) / rate)
+	}
+	res.Remaining = int(b.tokens)
+	res.Reset = secondsToDuration((capacity - b.tokens) / rate)
+	b.full = now.Add(res.Reset)
+	return res, nil
+}
+
+// Requiere el lock
+func (m *Memory) sweep(now time.Time) {
+	for _, key := range m.buckets.Keys() {
+		if b, ok := m.buckets.Peek(key); ok && !now.Before(b.full) {
+			m.buckets.Remove(key)
+		}
+	}
+	m.lastSweep = now
+}
+
+func secondsToDuration(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,106 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// Reloj manual para el limiter
+type clock struct{ t time.Time }
+
+func (c *clock) now() time.Time { return c.t }
+
+func newTestMemory(maxBuckets int) (*Memory, *clock) {
+	c := &clock{t: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	m := NewMemory(maxBuckets)
+	m.now = c.now
+	return m, c
+}
+
+func TestMemoryAllow(t *testing.T) {
+	rule := Rule{Requests: 2, Per: time.Second, Burst: 4}
+
+	// Cada paso avanza el reloj y consume n tokens del mismo bucket
+	steps := []struct {
+		name       string
+		advance    time.Duration
+		n          int
+		allowed    bool
+		remaining  int
+		retryAfter time.Duration
+		reset      time.Duration
+	}{
+		{"full bucket", 0, 1, true, 3, 0, 500 * time.Millisecond},
+		{"burst", 0, 3, true, 0, 0, 2 * time.Second},
+		{"empty", 0, 1, false, 0, 500 * time.Millisecond, 2 * time.Second},
+		{"refill", 500 * time.Millisecond, 1, true, 0, 0, 2 * time.Second},
+		{"cost above tokens", time.Second, 3, false, 2, 500 * time.Millisecond, time.Second},
+		{"refill is capped at burst", time.Hour, 4, true, 0, 0, 2 * time.Second},
+	}
+	m, c := newTestMemory(10)
+	for _, st := range steps {
+		c.t = c.t.Add(st.advance)
+		res, err := m.Allow(context.Background(), "k", rule, st.n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := Result{Allowed: st.allowed, Limit: 4, Remaining: st.remaining, Reset: st.reset, RetryAfter: st.retryAfter}
+		if res != want {
+			t.Errorf("%s: Allow() = %+v, want %+v", st.name, res, want)
+		}
+	}
+}
+
+func TestMemoryBuckets(t *testing.T) {
+	rule := Rule{Requests: 1, Per: time.Minute}
+
+	tests := []struct {
+		name       string
+		maxBuckets int
+		keys       []string // una peticion por clave, en orden
+		advance    time.Duration
+		key        string // siguiente peticion tras avanzar el reloj
+		allowed    bool
+	}{
+		{"own bucket per key", 10, []string{"a", "b"}, 0, "c", true},
+		{"same key is limited", 10, []string{"a", "b"}, 0, "a", false},
+		{"least recently used is evicted", 2, []string{"a", "b", "c"}, 0, "a", true},
+		{"recent keys are kept", 2, []string{"a", "b", "c"}, 0, "c", false},
+		{"idle bucket refills", 10, []string{"a"}, 2 * time.Minute, "a", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, c := newTestMemory(tt.maxBuckets)
+			for _, key := range tt.keys {
+				if _, err := m.Allow(context.Background(), key, rule, 1); err != nil {
+					t.Fatal(err)
+				}
+			}
+			c.t = c.t.Add(tt.advance)
+			res, err := m.Allow(context.Background(), tt.key, rule, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.Allowed != tt.allowed {
+				t.Errorf("Allow(%q).Allowed = %v, want %v", tt.key, res.Allowed, tt.allowed)
+			}
+			if n := m.buckets.Len(); n > tt.maxBuckets {
+				t.Errorf("%d buckets, max %d", n, tt.maxBuckets)
+			}
+		})
+	}
+}
+
+func TestMemorySweep(t *testing.T) {
+	rule := Rule{Requests: 1, Per: time.Minute}
+	m, c := newTestMemory(10)
+	ctx := context.Background()
+
+	m.Allow(ctx, "idle", rule, 1)
+	c.t = c.t.Add(90 * time.Second)
+	m.Allow(ctx, "active", rule, 1)
+	if n := m.buckets.Len(); n != 1 {
+		t.Errorf("after sweep %d buckets, want only the active one", n)
+	}
+}
@@ -0,0 +1,113 @@
+// Limites de peticiones por cliente con token bucket. Limiter es la interfaz que
+// implementan el store en memoria y cualquier backend compartido (p.ej. Redis)
+// cuando hay varias instancias del servidor.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Regla de un bucket: Requests por cada Per, con rafagas de hasta Burst peticiones
+// (Requests si es 0)
+type Rule struct {
+	Requests int
+	Per      time.Duration
+	Burst    int
+}
+
+func (r Rule) capacity() int {
+	if r.Burst > 0 {
+		return r.Burst
+	}
+	return r.Requests
+}
+
+// Tokens por segundo
+func (r Rule) rate() float64 {
+	return float64(r.Requests) / r.Per.Seconds()
+}
+
+func (r Rule) Enabled() bool {
+	return r.Requests > 0 && r.Per > 0
+}
+
+// Resultado de consumir tokens de un bucket
+type Result struct {
+	Allowed    bool
+	Limit      int           // capacidad del bucket
+	Remaining  int           // tokens que quedan
+	Reset      time.Duration // hasta que el bucket vuelva a estar lleno
+	RetryAfter time.Duration // hasta que haya tokens suficientes, 0 si Allowed
+}
+
+// Backend de los buckets. key identifica cliente y regla; n es el costo de la peticion.
+type Limiter interface {
+	Allow(ctx context.Context, key string, rule Rule, n int) (Result, error)
+}
+
+// Cabeceras RateLimit-* (draft-ietf-httpapi-ratelimit-headers) y Retry-After
+func SetHeaders(h http.Header, rule Rule, res Result) {
+	h.Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+	h.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+	h.Set("RateLimit-Reset", strconv.Itoa(seconds(res.Reset)))
+	h.Set("RateLimit-Policy", strconv.Itoa(rule.Requests)+";w="+strconv.Itoa(seconds(rule.Per)))
+	if !res.Allowed {
+		h.Set("Retry-After", strconv.Itoa(max(1, seconds(res.RetryAfter))))
+	}
+}
+
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+type identityKey struct{}
+
+// Guarda la identidad de un cliente ya autenticado para que sus buckets sean por
+// usuario. La pone el middleware de autenticacion despues de verificar las
+// credenciales; solo cuenta si se ejecuta antes que el limite.
+func WithIdentity(ctx context.Context, user string) context.Context {
+	return context.WithValue(ctx, identityKey{}, user)
+}
+
+// Identidad del cliente para los buckets: el usuario autenticado (WithIdentity) o
+// la IP. Lo que el cliente envia sin verificar (una API key, el usuario de Basic
+// Auth) no cuenta, con un valor nuevo en cada peticion tendria un bucket nuevo.
+// Con trustProxy se usa la primera IP de X-Forwarded-For.
+func ClientID(r *http.Request, trustProxy bool) string {
+	if user, _ := r.Context().Value(identityKey{}).(string); user != "" {
+		return "user:" + user
+	}
+	if trustProxy {
+		if fwd := r.Header.Get("X-Forwarded-For"); fwd != "" {
+			ip, _, _ := strings.Cut(fwd, ",")
+			return "ip:" + strings.TrimSpace(ip)
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+type clientKey struct{}
+type headerKey struct{}
+
+// Guarda la identidad del cliente y las cabeceras de la respuesta para los limites
+// que se aplican despues del middleware HTTP (operaciones GraphQL)
+func WithClient(ctx context.Context, client string, header http.Header) context.Context {
+	ctx = context.WithValue(ctx, clientKey{}, client)
+	return context.WithValue(ctx, headerKey{}, header)
+}
+
+func ClientFromContext(ctx context.Context) (string, http.Header) {
+	client, _ := ctx.Value(clientKey{}).(string)
+	header, _ := ctx.Value(headerKey{}).(http.Header)
+	return client, header
+}
@@ -0,0 +1,102 @@
+package ratelimit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClientID(t *testing.T) {
+	tests := []struct {
+		name       string
+		remoteAddr string
+		header     http.Header
+		identity   string
+		trustProxy bool
+		want       string
+	}{
+		{"remote address", "192.0.2.1:1234", nil, "", false, "ip:192.0.2.1"},
+		{"remote address without port", "192.0.2.1", nil, "", false, "ip:192.0.2.1"},
+		{"ipv6", "[2001:db8::1]:1234", nil, "", false, "ip:2001:db8::1"},
+		{"forwarded for is ignored", "192.0.2.1:1234", http.Header{"X-Forwarded-For": {"198.51.100.7"}}, "", false, "ip:192.0.2.1"},
+		{"forwarded for behind proxy", "192.0.2.1:1234", http.Header{"X-Forwarded-For": {"198.51.100.7, 10.0.0.1"}}, "", true, "ip:198.51.100.7"},
+		{"no forwarded for behind proxy", "192.0.2.1:1234", nil, "", true, "ip:192.0.2.1"},
+		{"authenticated user", "192.0.2.1:1234", nil, "alice", false, "user:alice"},
+		{"user before forwarded for", "192.0.2.1:1234", http.Header{"X-Forwarded-For": {"198.51.100.7"}}, "alice", true, "user:alice"},
+		{"unverified api key", "192.0.2.1:1234", http.Header{"X-Api-Key": {"random"}}, "", false, "ip:192.0.2.1"},
+		{"unverified basic auth", "192.0.2.1:1234", http.Header{"Authorization": {"Basic Ym9iOnNlY3JldA=="}}, "", false, "ip:192.0.2.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = tt.remoteAddr
+			for k, v := range tt.header {
+				r.Header[k] = v
+			}
+			if tt.identity != "" {
+				r = r.WithContext(WithIdentity(r.Context(), tt.identity))
+			}
+			if got := ClientID(r, tt.trustProxy); got != tt.want {
+				t.Errorf("ClientID() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetHeaders(t *testing.T) {
+	rule := Rule{Requests: 100, Per: time.Minute, Burst: 10}
+
+	tests := []struct {
+		name string
+		res  Result
+		want http.Header
+	}{
+		{
+			"allowed",
+			Result{Allowed: true, Limit: 10, Remaining: 9, Reset: 600 * time.Millisecond},
+			http.Header{
+				"Ratelimit-Limit":     {"10"},
+				"Ratelimit-Remaining": {"9"},
+				"Ratelimit-Reset":     {"1"},
+				"Ratelimit-Policy":    {"100;w=60"},
+			},
+		},
+		{
+			"limited",
+			Result{Limit: 10, Reset: 6 * time.Second, RetryAfter: 600 * time.Millisecond},
+			http.Header{
+				"Ratelimit-Limit":     {"10"},
+				"Ratelimit-Remaining": {"0"},
+				"Ratelimit-Reset":     {"6"},
+				"Ratelimit-Policy":    {"100;w=60"},
+				"Retry-After":         {"1"},
+			},
+		},
+		{
+			"retry after is at least one second",
+			Result{Limit: 10},
+			http.Header{
+				"Ratelimit-Limit":     {"10"},
+				"Ratelimit-Remaining": {"0"},
+				"Ratelimit-Reset":     {"0"},
+				"Ratelimit-Policy":    {"100;w=60"},
+				"Retry-After":         {"1"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := http.Header{}
+			SetHeaders(h, rule, tt.res)
+			if len(h) != len(tt.want) {
+				t.Errorf("headers = %v, want %v", h, tt.want)
+			}
+			for k, v := range tt.want {
+				if got := h.Get(k); got != v[0] {
+					t.Errorf("%s = %q, want %q", k, got, v[0])
+				}
+			}
+		})
+	}
+}
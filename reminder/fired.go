@@ -0,0 +1,86 @@
+package reminder
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"restServer/taskstore"
+	"sync"
+	"time"
+)
+
+// FiredLog registra los recordatorios ya enviados para no repetirlos.
+// Si Path no esta vacio se persiste como JSON y sobrevive a reinicios.
+type FiredLog struct {
+	sync.Mutex
+	Path  string
+	fired map[string]time.Time
+}
+
+// Crea el registro cargando el archivo si existe
+func LoadFiredLog(path string) (*FiredLog, error) {
+	fl := &FiredLog{Path: path, fired: make(map[string]time.Time)}
+	if path == "" {
+		return fl, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return fl, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &fl.fired); err != nil {
+		return nil, err
+	}
+	return fl, nil
+}
+
+func (fl *FiredLog) Fired(key string) bool {
+	fl.Lock()
+	defer fl.Unlock()
+
+	_, ok := fl.fired[key]
+	return ok
+}
+
+// Marca el recordatorio como enviado y lo persiste
+func (fl *FiredLog) Mark(key string, at time.Time) error {
+	fl.Lock()
+	defer fl.Unlock()
+
+	fl.fired[key] = at
+	return fl.save()
+}
+
+// Olvida las entradas que ya no cumplen keep (p.ej. de tareas eliminadas)
+func (fl *FiredLog) Prune(keep func(key string) bool) error {
+	fl.Lock()
+	defer fl.Unlock()
+
+	changed := false
+	for key := range fl.fired {
+		if !keep(key) {
+			delete(fl.fired, key)
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return fl.save()
+}
+
+// Escritura atomica: archivo temporal + rename, requiere tener el lock
+func (fl *FiredLog) save() error {
+	if fl.Path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(fl.fired, "", "  ")
+	if err != nil {
+		return err
+	}
+	return taskstore.WriteFileAtomic(fl.Path, data)
+}
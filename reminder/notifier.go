@@ -0,0 +1,181 @@
+package reminder
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+	"net/smtp"
+	"os"
+	"restServer/logging"
+	"strings"
+	"time"
+)
+
+// Recordatorio que se entrega a los notificadores
+type Reminder struct {
+	TaskID string    `json:"taskId"`
+	Text   string    `json:"text"`
+	Due    time.Time `json:"due"`
+	Offset string    `json:"offset"`
+	FireAt time.Time `json:"fireAt"`
+}
+
+// Notifier entrega un recordatorio por un canal concreto (log, webhook, smtp...)
+type Notifier interface {
+	// Nombre del canal, es el que se usa en la configuracion de cada tarea
+	Name() string
+	Notify(ctx context.Context, r Reminder) error
+}
+
+//-------------------------------------------- Log ----------------------------------------//
+
+// Escribe el recordatorio en el log del servidor
+type LogNotifier struct{}
+
+func (LogNotifier) Name() string { return "log" }
+
+func (LogNotifier) Notify(ctx context.Context, r Reminder) error {
+	logging.FromContext(ctx).Info("reminder",
+		"task_id", r.TaskID, "offset", r.Offset, "due", r.Due.Format(time.RFC3339), "text", r.Text)
+	return nil
+}
+
+//-------------------------------------------- Webhook ----------------------------------------//
+
+// Envia el recordatorio como JSON con un POST a una URL
+type WebhookNotifier struct {
+	URL    string
+	Client *http.Client
+}
+
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{URL: url, Client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (n *WebhookNotifier) Name() string { return "webhook" }
+
+func (n *WebhookNotifier) Notify(ctx context.Context, r Reminder) error {
+	js, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.URL, bytes.NewReader(js))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := n.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s responded %d", n.URL, res.StatusCode)
+	}
+	return nil
+}
+
+//-------------------------------------------- SMTP ----------------------------------------//
+
+// Envia el recordatorio por correo a traves de un relay SMTP local (sin autenticacion)
+type SMTPNotifier struct {
+	Addr    string // host:puerto del relay, p.ej. localhost:25
+	From    string
+	To      []string
+	Timeout time.Duration // limite de toda la entrega, 0 = 30s
+}
+
+func (n *SMTPNotifier) Name() string { return "smtp" }
+
+func (n *SMTPNotifier) Notify(ctx context.Context, r Reminder) error {
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", n.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(n.To, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", subject(r.Text))
+	fmt.Fprintf(&msg, "Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	fmt.Fprintf(&msg, "La tarea %s vence el %s.\r\n", r.TaskID, r.Due.Format(time.RFC3339))
+
+	return n.send(ctx, []byte(msg.String()))
+}
+
+// Asunto del correo. El texto de la tarea lo escribe el usuario: los saltos de
+// linea se quitan (inyectarian cabeceras o cuerpo) y se codifica segun RFC 2047.
+func subject(text string) string {
+	text = strings.Join(strings.FieldsFunc(text, func(r rune) bool { return r == '\r' || r == '\n' }), " ")
+	return mime.QEncoding.Encode("utf-8", "Recordatorio: "+text)
+}
+
+// Igual que smtp.SendMail pero con el contexto: la conexion se abre con el dialer
+// del contexto, lleva un deadline y se cierra si el contexto se cancela (apagado).
+func (n *SMTPNotifier) send(ctx context.Context, msg []byte) (err error) {
+	timeout := n.Timeout
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	defer func() {
+		// La conexion cerrada por el contexto se informa como cancelacion o timeout.
+		// El deadline de la conexion puede vencer antes que el del contexto.
+		if err != nil && ctx.Err() != nil {
+			err = ctx.Err()
+		} else if errors.Is(err, os.ErrDeadlineExceeded) {
+			err = context.DeadlineExceeded
+		}
+	}()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", n.Addr)
+	if err != nil {
+		return err
+	}
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return err
+	}
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	host, _, _ := net.SplitHostPort(n.Addr)
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(n.From); err != nil {
+		return err
+	}
+	for _, to := range n.To {
+		if err := client.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
@@ -0,0 +1,167 @@
+package reminder
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"mime"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Relay SMTP falso: acepta una conexion, guarda el mensaje recibido en DATA
+func fakeSMTP(t *testing.T) (addr string, msgs <-chan string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	ch := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		rw := bufio.NewReadWriter(bufio.NewReader(conn), bufio.NewWriter(conn))
+		reply := func(line string) {
+			rw.WriteString(line + "\r\n")
+			rw.Flush()
+		}
+
+		reply("220 fake ESMTP")
+		for {
+			line, err := rw.ReadString('\n')
+			if err != nil {
+				return
+			}
+			switch cmd := strings.ToUpper(strings.TrimSpace(line)); {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				reply("250 fake")
+			case strings.HasPrefix(cmd, "DATA"):
+				reply("354 go ahead")
+				var data strings.Builder
+				for {
+					l, err := rw.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if l == ".\r\n" {
+						break
+					}
+					data.WriteString(l)
+				}
+				ch <- data.String()
+				reply("250 queued")
+			case strings.HasPrefix(cmd, "QUIT"):
+				reply("221 bye")
+				return
+			default:
+				reply("250 ok")
+			}
+		}
+	}()
+	return ln.Addr().String(), ch
+}
+
+func TestSMTPNotifier(t *testing.T) {
+	tests := []struct {
+		name        string
+		text        string
+		wantSubject string
+	}{
+		{"ascii", "pay rent", "Recordatorio: pay rent"},
+		{"non-ascii is encoded", "revisión del año", "Recordatorio: revisión del año"},
+		{"line breaks cannot inject headers", "x\r\nBcc: victim@example.com\r\n\r\nbody", "Recordatorio: x Bcc: victim@example.com body"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr, msgs := fakeSMTP(t)
+			n := &SMTPNotifier{Addr: addr, From: "server@example.com", To: []string{"me@example.com"}, Timeout: 5 * time.Second}
+			err := n.Notify(context.Background(), Reminder{TaskID: "7", Text: tt.text, Due: time.Now()})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			raw := <-msgs
+			msg, err := mail.ReadMessage(strings.NewReader(raw))
+			if err != nil {
+				t.Fatalf("parsing %q: %v", raw, err)
+			}
+			if got := msg.Header.Get("Bcc"); got != "" {
+				t.Errorf("injected Bcc header %q", got)
+			}
+			encoded := msg.Header.Get("Subject")
+			for _, r := range encoded {
+				if r > 127 {
+					t.Fatalf("subject %q is not RFC 2047 encoded", encoded)
+				}
+			}
+			subject, err := new(mime.WordDecoder).DecodeHeader(encoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if subject != tt.wantSubject {
+				t.Errorf("subject = %q, want %q", subject, tt.wantSubject)
+			}
+		})
+	}
+}
+
+func TestSMTPNotifierCancel(t *testing.T) {
+	// Relay que acepta la conexion y nunca responde
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		conn, err := ln.Accept()
+		if err == nil {
+			defer conn.Close()
+			time.Sleep(5 * time.Second)
+		}
+	}()
+
+	n := &SMTPNotifier{Addr: ln.Addr().String(), From: "a@example.com", To: []string{"b@example.com"}, Timeout: 50 * time.Millisecond}
+	err = n.Notify(context.Background(), Reminder{TaskID: "1", Text: "x"})
+	if err != context.DeadlineExceeded {
+		t.Errorf("Notify() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{"accepted", http.StatusNoContent, false},
+		{"rejected", http.StatusInternalServerError, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Reminder
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				json.NewDecoder(r.Body).Decode(&got)
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			err := NewWebhookNotifier(srv.URL).Notify(context.Background(), Reminder{TaskID: "3", Text: "call", Offset: "1h0m0s"})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Notify() error = %v, want error %v", err, tt.wantErr)
+			}
+			if got.TaskID != "3" || got.Text != "call" || got.Offset != "1h0m0s" {
+				t.Errorf("payload = %+v", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package reminder
+
+import (
+	"context"
+	"fmt"
+	"restServer/logging"
+	"restServer/taskstore"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Scheduler revisa periodicamente las tareas y dispara los recordatorios cuyo
+// momento (Due - offset) ya llego, entregandolos por los notificadores configurados.
+type Scheduler struct {
+	store     *taskstore.TaskStore
+	fired     *FiredLog
+	notifiers map[string]Notifier
+	names     []string
+
+	// Offsets usados por las tareas sin configuracion propia (vacio = sin recordatorios)
+	DefaultOffsets []time.Duration
+	// Cada cuanto se revisan las tareas
+	Interval time.Duration
+	// Un recordatorio que se paso por mas de MaxDelay (p.ej. con el servidor apagado) se descarta
+	MaxDelay time.Duration
+
+	lastRun atomic.Int64 // UnixNano de la ultima pasada de Run
+}
+
+func New(store *taskstore.TaskStore, fired *FiredLog, notifiers ...Notifier) *Scheduler {
+	s := &Scheduler{
+		store:     store,
+		fired:     fired,
+		notifiers: make(map[string]Notifier),
+		Interval:  30 * time.Second,
+		MaxDelay:  time.Hour,
+	}
+	for _, n := range notifiers {
+		s.notifiers[n.Name()] = n
+		s.names = append(s.names, n.Name())
+	}
+	return s
+}
+
+// Nombres de los canales configurados, en el orden de los notificadores
+func (s *Scheduler) Channels() []string {
+	return append([]string{}, s.names...)
+}
+
+// Ejecuta el scheduler hasta que se cancele el contexto
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+
+	for {
+		s.Tick(ctx, time.Now())
+		s.lastRun.Store(time.Now().UnixNano())
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Momento de la ultima pasada de Run (cero si nunca corrio), para los chequeos de salud
+func (s *Scheduler) LastRun() time.Time {
+	n := s.lastRun.Load()
+	if n == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, n)
+}
+
+// Una pasada del scheduler: dispara todos los recordatorios pendientes a la hora now
+func (s *Scheduler) Tick(ctx context.Context, now time.Time) {
+	tasks, err := s.store.GetAllTasks(ctx)
+	if err != nil {
+		logging.FromContext(ctx).Error("reminder scheduler", "err", err)
+		return
+	}
+
+	live := make(map[string]bool, len(tasks))
+	for _, task := range tasks {
+		live[task.ID] = true
+
+		config, own, err := s.store.GetReminders(ctx, task.ID)
+		if err != nil {
+			continue // la tarea se elimino durante la pasada
+		}
+
+		offsets := s.DefaultOffsets
+		if own {
+			offsets = parseOffsets(config.Offsets)
+		}
+		channels := s.names
+		if len(config.Channels) > 0 {
+			channels = config.Channels
+		}
+
+		for _, offset := range offsets {
+			fireAt := task.Due.Add(-offset)
+			if now.Before(fireAt) || now.Sub(fireAt) > s.MaxDelay {
+				continue
+			}
+
+			r := Reminder{
+				TaskID: task.ID,
+				Text:   task.Text,
+				Due:    task.Due,
+				Offset: offset.String(),
+				FireAt: fireAt,
+			}
+			for _, channel := range channels {
+				s.deliver(ctx, channel, r, now)
+			}
+		}
+	}
+
+	// Las entradas de tareas eliminadas ya no hacen falta
+	err = s.fired.Prune(func(key string) bool {
+		taskID, _, _ := strings.Cut(key, "|")
+		return live[taskID]
+	})
+	if err != nil {
+		logging.FromContext(ctx).Error("reminder scheduler: prune fired log", "err", err)
+	}
+}
+
+// Entrega un recordatorio por un canal si no se envio antes. Si falla se reintenta
+// en la siguiente pasada mientras no se supere MaxDelay.
+func (s *Scheduler) deliver(ctx context.Context, channel string, r Reminder, now time.Time) {
+	key := firedKey(r, channel)
+	if s.fired.Fired(key) {
+		return
+	}
+
+	n, ok := s.notifiers[channel]
+	if !ok {
+		logging.FromContext(ctx).Warn("reminder scheduler: unknown channel", "channel", channel, "task_id", r.TaskID)
+		return
+	}
+
+	if err := n.Notify(ctx, r); err != nil {
+		logging.FromContext(ctx).Warn("reminder scheduler: notify failed", "channel", channel, "task_id", r.TaskID, "err", err)
+		return
+	}
+	if err := s.fired.Mark(key, now); err != nil {
+		logging.FromContext(ctx).Error("reminder scheduler: mark fired", "err", err)
+	}
+}
+
+// La clave incluye Due para que un cambio de fecha vuelva a programar el recordatorio
+func firedKey(r Reminder, channel string) string {
+	return fmt.Sprintf("%s|%d|%s|%s", r.TaskID, r.Due.Unix(), r.Offset, channel)
+}
+
+// Los offsets se validan al guardarse en el store
+func parseOffsets(raw []string) []time.Duration {
+	offsets := make([]time.Duration, 0, len(raw))
+	for _, o := range raw {
+		if d, err := time.ParseDuration(o); err == nil {
+			offsets = append(offsets, d)
+		}
+	}
+	return offsets
+}
+
+// Convierte una lista separada por comas ("24h,1h") en offsets
+func ParseOffsetList(list string) ([]time.Duration, error) {
+	offsets := make([]time.Duration, 0)
+	for _, o := range strings.Split(list, ",") {
+		o = strings.TrimSpace(o)
+		if o == "" {
+			continue
+		}
+		d, err := time.ParseDuration(o)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("%w: %q", taskstore.ErrInvalidReminder, o)
+		}
+		offsets = append(offsets, d)
+	}
+	return offsets, nil
+}
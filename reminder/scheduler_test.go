@@ -0,0 +1,146 @@
+package reminder
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"restServer/taskstore"
+	"testing"
+	"time"
+)
+
+// Notificador falso que guarda los recordatorios recibidos
+type fakeNotifier struct {
+	name string
+	err  error
+	got  []Reminder
+}
+
+func (n *fakeNotifier) Name() string { return n.name }
+
+func (n *fakeNotifier) Notify(ctx context.Context, r Reminder) error {
+	if n.err != nil {
+		return n.err
+	}
+	n.got = append(n.got, r)
+	return nil
+}
+
+func TestSchedulerTick(t *testing.T) {
+	ctx := context.Background()
+	due := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		offsets  []string // nil = offsets por defecto (1h)
+		channels []string
+		now      time.Time
+		wantLog  int
+		wantMail int
+	}{
+		{"before fire time", nil, nil, due.Add(-2 * time.Hour), 0, 0},
+		{"default offset fires on every channel", nil, nil, due.Add(-time.Hour), 1, 1},
+		{"own offsets", []string{"1h", "30m", "10m"}, nil, due.Add(-20 * time.Minute), 2, 2},
+		{"own channels", []string{"1h"}, []string{"mail"}, due.Add(-time.Hour), 0, 1},
+		{"older than MaxDelay is dropped", nil, nil, due.Add(2 * time.Hour), 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := taskstore.New()
+			id, _ := store.CreateTask(ctx, "task", nil, due, nil, "")
+			if tt.offsets != nil {
+				if _, err := store.SetReminders(ctx, id, tt.offsets, tt.channels); err != nil {
+					t.Fatal(err)
+				}
+			}
+			fired, _ := LoadFiredLog("")
+			log, mail := &fakeNotifier{name: "log"}, &fakeNotifier{name: "mail"}
+			s := New(store, fired, log, mail)
+			s.DefaultOffsets = []time.Duration{time.Hour}
+
+			// La segunda pasada no repite lo ya enviado
+			s.Tick(ctx, tt.now)
+			s.Tick(ctx, tt.now)
+			if len(log.got) != tt.wantLog || len(mail.got) != tt.wantMail {
+				t.Errorf("sent log=%d mail=%d, want log=%d mail=%d", len(log.got), len(mail.got), tt.wantLog, tt.wantMail)
+			}
+		})
+	}
+}
+
+// Un envio fallido se reintenta en la siguiente pasada
+func TestSchedulerRetry(t *testing.T) {
+	ctx := context.Background()
+	due := time.Now().Add(time.Hour)
+	store := taskstore.New()
+	store.CreateTask(ctx, "task", nil, due, nil, "")
+
+	fired, _ := LoadFiredLog("")
+	n := &fakeNotifier{name: "log", err: errors.New("relay down")}
+	s := New(store, fired, n)
+	s.DefaultOffsets = []time.Duration{time.Hour}
+
+	s.Tick(ctx, due.Add(-time.Hour))
+	n.err = nil
+	s.Tick(ctx, due.Add(-time.Hour+time.Minute))
+	if len(n.got) != 1 {
+		t.Fatalf("sent %d reminders, want 1", len(n.got))
+	}
+	if r := n.got[0]; r.Offset != "1h0m0s" || !r.FireAt.Equal(due.Add(-time.Hour)) {
+		t.Errorf("reminder = %+v", r)
+	}
+}
+
+func TestFiredLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fired.json")
+	fl, err := LoadFiredLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fl.Mark("1|0|1h0m0s|log", time.Now())
+	fl.Mark("2|0|1h0m0s|log", time.Now())
+	if err := fl.Prune(func(key string) bool { return key[0] == '1' }); err != nil {
+		t.Fatal(err)
+	}
+
+	// Sobrevive a un reinicio
+	reloaded, err := LoadFiredLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reloaded.Fired("1|0|1h0m0s|log") || reloaded.Fired("2|0|1h0m0s|log") {
+		t.Errorf("reloaded log = %v", reloaded.fired)
+	}
+}
+
+func TestParseOffsetList(t *testing.T) {
+	tests := []struct {
+		list    string
+		want    []time.Duration
+		wantErr bool
+	}{
+		{"24h, 1h", []time.Duration{24 * time.Hour, time.Hour}, false},
+		{"", []time.Duration{}, false},
+		{"1h,soon", nil, true},
+		{"-1h", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.list, func(t *testing.T) {
+			got, err := ParseOffsetList(tt.list)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, taskstore.ErrInvalidReminder) {
+				t.Errorf("error = %v, want %v", err, taskstore.ErrInvalidReminder)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("got %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
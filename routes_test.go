@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"restServer/internal"
+	"restServer/server"
+	"restServer/taskstore"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRESTv1Routes(t *testing.T) {
+	const batch = `{"operations":[{"op":"create","task":{"text":"batched","due":"2026-01-02T15:04:05Z"}}]}`
+	const task = `{"text":"single","due":"2026-01-02T15:04:05Z"}`
+
+	tests := []struct {
+		name       string
+		prefix     string
+		path       string
+		body       string
+		wantStatus int
+		wantBatch  bool // la respuesta es de BatchTasksHandler
+	}{
+		{"batch without slash", "", "/task/batch", batch, http.StatusOK, true},
+		{"batch with slash", "", "/task/batch/", batch, http.StatusOK, true},
+		{"batch under the version prefix", "/v1", "/v1/task/batch", batch, http.StatusOK, true},
+		{"create task", "", "/task/", task, http.StatusOK, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			registerRESTv1(mux, server.NewTaskServerWith(taskstore.New(), 0), tt.prefix, internal.Deprecation{}, nil)
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			var body map[string]any
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if _, isBatch := body["results"]; isBatch != tt.wantBatch {
+				t.Errorf("batch response = %v, want %v: %s", isBatch, tt.wantBatch, rec.Body)
+			}
+		})
+	}
+}
+
+// v1 y las rutas sin version llevan las cabeceras de deprecacion y campos en
+// mayuscula; v2 no las lleva y usa camelCase
+func TestAPIVersions(t *testing.T) {
+	store := taskstore.New()
+	id, err := store.CreateTask(context.Background(), "task", nil, time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC), nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	deprecation := internal.Deprecation{
+		Since:  time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC),
+		Sunset: time.Date(2027, 4, 30, 0, 0, 0, 0, time.UTC),
+		Link:   "https://example.com/migration",
+	}
+	taskServer := server.NewTaskServerWith(store, 0)
+	mux := http.NewServeMux()
+	registerRESTv1(mux, taskServer, "", deprecation, nil)
+	registerRESTv1(mux, taskServer, "/v1", deprecation, nil)
+	registerRESTv2(mux, taskServer, nil)
+
+	tests := []struct {
+		path       string
+		deprecated bool
+		textField  string
+	}{
+		{"/task/" + id + "/", true, "Text"},
+		{"/v1/task/" + id + "/", true, "Text"},
+		{"/v2/tasks/" + id, false, "text"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status %d: %s", rec.Code, rec.Body)
+			}
+
+			header := rec.Header()
+			want := map[string]string{
+				"Deprecation": "@1792368000",
+				"Sunset":      "Fri, 30 Apr 2027 00:00:00 GMT",
+				"Link":        `<https://example.com/migration>; rel="deprecation"`,
+			}
+			for name, value := range want {
+				if !tt.deprecated {
+					value = ""
+				}
+				if got := header.Get(name); got != value {
+					t.Errorf("%s = %q, want %q", name, got, value)
+				}
+			}
+
+			var body map[string]any
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if body[tt.textField] != "task" {
+				t.Errorf("body = %s, want field %s", rec.Body, tt.textField)
+			}
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"restServer/logging"
+	"restServer/problem"
+	"restServer/taskstore"
+	"restServer/validation"
+)
+
+// Cantidad maxima de operaciones por batch
+const maxBatchSize = 1000
+
+// Modos de ejecucion de un batch
+const (
+	batchAtomic  = "atomic"  // todo o nada
+	batchPartial = "partial" // cada operacion por separado
+)
+
+// Cuerpo de creacion de una tarea dentro de un batch: requestTask en v1 y
+// requestTaskV2 en v2
+type batchTask interface {
+	requestTask | requestTaskV2
+	validate() (validation.Task, error)
+	project() string
+}
+
+type requestBatch[T batchTask] struct {
+	Mode       string `json:"mode"`
+	Operations []struct {
+		Op   string `json:"op"`
+		ID   string `json:"id"`
+		Task *T     `json:"task"`
+	} `json:"operations"`
+}
+
+type batchItemResult struct {
+	Index  int    `json:"index"`
+	Op     string `json:"op"`
+	ID     string `json:"id,omitempty"`
+	Status int    `json:"status"`
+	Error  string `json:"error,omitempty"`
+
+	Errors validation.Errors `json:"errors,omitempty"` // errores de validacion de la operacion
+}
+
+type responseBatch struct {
+	Mode    string            `json:"mode"`
+	Applied bool              `json:"applied"`
+	Results []batchItemResult `json:"results"`
+}
+
+// BatchTasksHandler godoc
+// @Summary Operaciones en lote
+// @Description Ejecuta en orden operaciones create (campo task) y delete (campo id). Con mode=atomic (por defecto) si alguna falla no se aplica ninguna y se responde un problem+json con los resultados; con mode=partial cada operacion se aplica por separado y se informa su estado.
+// @Tags task
+// @Accept json
+// @Produce json
+// @Param batch body object true "mode y operations"
+// @Success 200 {object} object
+// @Failure 400 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Failure 413 {object} problem.Problem
+// @Failure 415 {object} problem.Problem
+// @Failure 422 {object} problem.Problem
+// @Router /task/batch [post]
+func (ts *TaskServer) BatchTasksHandler(w http.ResponseWriter, r *http.Request) {
+	logging.FromContext(r.Context()).Debug("handling task batch")
+	batchTasks[requestTask](ts, w, r)
+}
+
+// BatchTasksV2Handler godoc
+// @Summary Operaciones en lote (v2)
+// @Description Como /task/batch, con las tareas de create en el formato de POST /v2/tasks (projectId)
+// @Tags v2
+// @Accept json
+// @Produce json
+// @Param batch body object true "mode y operations"
+// @Success 200 {object} object
+// @Failure 400 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Failure 413 {object} problem.Problem
+// @Failure 415 {object} problem.Problem
+// @Failure 422 {object} problem.Problem
+// @Router /v2/tasks/batch [post]
+func (ts *TaskServer) BatchTasksV2Handler(w http.ResponseWriter, r *http.Request) {
+	logging.FromContext(r.Context()).Debug("handling v2 task batch")
+	batchTasks[requestTaskV2](ts, w, r)
+}
+
+func batchTasks[T batchTask](ts *TaskServer, w http.ResponseWriter, r *http.Request) {
+	var req requestBatch[T]
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	if req.Mode == "" {
+		req.Mode = batchAtomic
+	}
+	if req.Mode != batchAtomic && req.Mode != batchPartial {
+		problem.Write(w, r, problem.New(http.StatusBadRequest, "invalid_parameter", "mode must be atomic or partial"))
+		return
+	}
+	if len(req.Operations) > maxBatchSize {
+		problem.Write(w, r, problem.New(http.StatusRequestEntityTooLarge, "batch_too_large",
+			fmt.Sprintf("at most %d operations per batch", maxBatchSize)))
+		return
+	}
+
+	// Las operaciones mal formadas no llegan al store pero ocupan su posicion en el resultado
+	results := make([]batchItemResult, len(req.Operations))
+	ops := make([]taskstore.BatchOp, 0, len(req.Operations))
+	index := make([]int, 0, len(req.Operations))
+	var fields validation.Errors
+	for i, item := range req.Operations {
+		op, err := batchOp(item.Op, item.ID, item.Task)
+		if err != nil {
+			opFields := err.Prefix(validation.Pointer("operations", i))
+			results[i] = batchItemResult{Index: i, Op: item.Op, ID: item.ID, Status: http.StatusUnprocessableEntity, Error: opFields.Error(), Errors: opFields}
+			fields = append(fields, opFields...)
+			continue
+		}
+		ops = append(ops, op)
+		index = append(index, i)
+	}
+
+	if len(fields) > 0 && req.Mode == batchAtomic {
+		for i, item := range req.Operations {
+			if results[i].Status == 0 {
+				results[i] = batchItemResult{Index: i, Op: item.Op, ID: item.ID, Status: http.StatusFailedDependency, Error: taskstore.ErrBatchAborted.Error()}
+			}
+		}
+		problem.WriteWith(w, r, fields, map[string]any{"mode": req.Mode, "applied": false, "results": results})
+		return
+	}
+
+	storeResults, err := ts.store.Batch(r.Context(), ops, req.Mode == batchAtomic)
+	for j, res := range storeResults {
+		i := index[j]
+		results[i] = batchItemResult{Index: i, Op: res.Op, ID: res.ID, Status: batchStatus(res)}
+		if res.Error != nil {
+			results[i].Error = res.Error.Error()
+		}
+	}
+
+	if err != nil {
+		problem.WriteWith(w, r, err, map[string]any{"mode": req.Mode, "applied": false, "results": results})
+		return
+	}
+	render(w, r, responseBatch{Mode: req.Mode, Applied: true, Results: results})
+}
+
+// Convierte una operacion del cuerpo en una operacion del store. Los errores de
+// validacion usan punteros relativos a la operacion.
+func batchOp[T batchTask](op, id string, task *T) (taskstore.BatchOp, validation.Errors) {
+	switch op {
+	case taskstore.BatchCreate:
+		if task == nil {
+			return taskstore.BatchOp{}, validation.Errors{{Pointer: "/task", Code: "required", Message: "create requires task"}}
+		}
+		valid, err := (*task).validate()
+		if err != nil {
+			return taskstore.BatchOp{}, err.(validation.Errors).Prefix("/task")
+		}
+		return taskstore.BatchOp{
+			Op:          op,
+			Text:        valid.Text,
+			Tags:        valid.Tags,
+			Due:         valid.Due,
+			Attachments: valid.Attachments,
+			ProjectID:   (*task).project(),
+		}, nil
+	case taskstore.BatchDelete:
+		if id == "" {
+			return taskstore.BatchOp{}, validation.Errors{{Pointer: "/id", Code: "required", Message: "delete requires id"}}
+		}
+		return taskstore.BatchOp{Op: op, ID: id}, nil
+	default:
+		return taskstore.BatchOp{}, validation.Errors{{Pointer: "/op", Code: "unsupported", Message: fmt.Sprintf("unsupported batch operation %q", op)}}
+	}
+}
+
+// Codigo HTTP equivalente al resultado de una operacion
+func batchStatus(res taskstore.BatchResult) int {
+	switch {
+	case res.Error == nil && res.Op == taskstore.BatchCreate:
+		return http.StatusCreated
+	case res.Error == nil:
+		return http.StatusNoContent
+	case errors.Is(res.Error, taskstore.ErrBatchAborted):
+		return http.StatusFailedDependency
+	default:
+		_, status := problem.Classify(res.Error)
+		return status
+	}
+}
@@ -0,0 +1,75 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestBatchTasksHandler(t *testing.T) {
+	const create = `{"op":"create","task":{"text":"new","due":"2026-01-02T15:04:05Z"}}`
+	const invalid = `{"op":"create","task":{"text":"","due":"2026-01-02T15:04:05Z"}}`
+
+	tests := []struct {
+		name         string
+		body         string
+		wantStatus   int
+		wantCode     string
+		wantStatuses []int // status de cada operacion
+		wantTasks    int   // tareas en el store despues (hay 1 antes)
+	}{
+		{"atomic", `{"operations":[` + create + `,{"op":"delete","id":"0"}]}`, http.StatusOK, "", []int{201, 204}, 1},
+		{"atomic rolls back", `{"operations":[` + create + `,{"op":"delete","id":"404"}]}`, http.StatusConflict, "batch_aborted", []int{424, 404}, 1},
+		{"atomic invalid operation", `{"operations":[` + create + `,` + invalid + `]}`, http.StatusUnprocessableEntity, "validation_failed", []int{424, 422}, 1},
+		{"partial", `{"mode":"partial","operations":[` + create + `,{"op":"delete","id":"404"},` + invalid + `]}`, http.StatusOK, "", []int{201, 404, 422}, 2},
+		{"unknown op", `{"mode":"partial","operations":[{"op":"move"}]}`, http.StatusOK, "", []int{422}, 1},
+		{"unknown mode", `{"mode":"some","operations":[]}`, http.StatusBadRequest, "invalid_parameter", nil, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := newTestServer()
+			ts.store.CreateTask(context.Background(), "existing", nil, time.Now(), nil, "")
+
+			rec := call(ts.BatchTasksHandler, "POST", "/task/batch", tt.body)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			var resp struct {
+				Code    string `json:"code"`
+				Results []struct {
+					Status int `json:"status"`
+				} `json:"results"`
+			}
+			decodeResponse(t, rec, &resp)
+			if resp.Code != tt.wantCode {
+				t.Errorf("code = %q, want %q", resp.Code, tt.wantCode)
+			}
+			if len(resp.Results) != len(tt.wantStatuses) {
+				t.Fatalf("results = %+v, want statuses %v", resp.Results, tt.wantStatuses)
+			}
+			for i, want := range tt.wantStatuses {
+				if resp.Results[i].Status != want {
+					t.Errorf("result %d status = %d, want %d", i, resp.Results[i].Status, want)
+				}
+			}
+			if tasks, _ := ts.store.GetAllTasks(context.Background()); len(tasks) != tt.wantTasks {
+				t.Errorf("store has %d tasks, want %d", len(tasks), tt.wantTasks)
+			}
+		})
+	}
+}
+
+func TestBatchTooLarge(t *testing.T) {
+	ops := make([]byte, 0, maxBatchSize*20)
+	for i := 0; i <= maxBatchSize; i++ {
+		if i > 0 {
+			ops = append(ops, ',')
+		}
+		ops = append(ops, `{"op":"delete","id":"1"}`...)
+	}
+	rec := call(newTestServer().BatchTasksHandler, "POST", "/task/batch", `{"operations":[`+string(ops)+`]}`)
+	if rec.Code != http.StatusRequestEntityTooLarge || problemCode(t, rec) != "batch_too_large" {
+		t.Errorf("status %d: %s", rec.Code, rec.Body)
+	}
+}
@@ -0,0 +1,155 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"restServer/logging"
+	"restServer/problem"
+	"restServer/taskstore"
+	"strconv"
+)
+
+//-------------------------------------------- Controladores de comentarios ----------------------------------------//
+
+// GetCommentsHandler godoc
+// @Summary Comentarios de una tarea
+// @Description Devuelve los comentarios de una tarea en orden de creacion, el total va en la cabecera X-Total-Count
+// @Tags comment
+// @Produce json
+// @Param id path int true "ID de la tarea"
+// @Param offset query int false "Desplazamiento"
+// @Param limit query int false "Cantidad maxima"
+// @Success 200 {array} server.CommentV1
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Router /task/{id}/comments/ [get]
+func (ts *TaskServer) GetCommentsHandler(w http.ResponseWriter, r *http.Request) {
+	logging.FromContext(r.Context()).Debug("handling comments get")
+
+	offset, limit, err := pagination(r)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	comments, total, err := ts.store.GetComments(r.Context(), r.PathValue("id"), offset, limit)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	render(w, r, newCommentsV1(comments))
+}
+
+// CreateCommentHandler godoc
+// @Summary Comentar una tarea
+// @Description Agrega un comentario a una tarea
+// @Tags comment
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de la tarea"
+// @Param comment body object true "Autor y cuerpo del comentario"
+// @Success 201 {object} server.CommentV1
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 415 {object} problem.Problem
+// @Failure 422 {object} problem.Problem
+// @Router /task/{id}/comments/ [post]
+func (ts *TaskServer) CreateCommentHandler(w http.ResponseWriter, r *http.Request) {
+	logging.FromContext(r.Context()).Debug("handling comment create")
+
+	var req struct {
+		Author string `json:"author"`
+		Body   string `json:"body"`
+	}
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if req.Author == "" || req.Body == "" {
+		problem.Write(w, r, fmt.Errorf("%w: author and body are required", taskstore.ErrValidation))
+		return
+	}
+
+	comment, err := ts.store.AddComment(r.Context(), r.PathValue("id"), req.Author, req.Body)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	renderStatus(w, r, http.StatusCreated, newCommentV1(comment))
+}
+
+// UpdateCommentHandler godoc
+// @Summary Editar un comentario
+// @Description Reemplaza el cuerpo de un comentario
+// @Tags comment
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de la tarea"
+// @Param commentId path int true "ID del comentario"
+// @Param comment body object true "Nuevo cuerpo"
+// @Success 200 {object} server.CommentV1
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 415 {object} problem.Problem
+// @Failure 422 {object} problem.Problem
+// @Router /task/{id}/comments/{commentId}/ [put]
+func (ts *TaskServer) UpdateCommentHandler(w http.ResponseWriter, r *http.Request) {
+	logging.FromContext(r.Context()).Debug("handling comment update")
+
+	var req struct {
+		Body string `json:"body"`
+	}
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if req.Body == "" {
+		problem.Write(w, r, fmt.Errorf("%w: body is required", taskstore.ErrValidation))
+		return
+	}
+
+	if _, err := ts.taskComment(r); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	comment, err := ts.store.UpdateComment(r.Context(), r.PathValue("commentId"), req.Body)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	render(w, r, newCommentV1(comment))
+}
+
+// DeleteCommentHandler godoc
+// @Summary Eliminar un comentario
+// @Description Elimina un comentario de una tarea
+// @Tags comment
+// @Param id path int true "ID de la tarea"
+// @Param commentId path int true "ID del comentario"
+// @Success 204
+// @Failure 404 {object} problem.Problem
+// @Router /task/{id}/comments/{commentId}/ [delete]
+func (ts *TaskServer) DeleteCommentHandler(w http.ResponseWriter, r *http.Request) {
+	logging.FromContext(r.Context()).Debug("handling comment delete")
+
+	if _, err := ts.taskComment(r); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	if err := ts.store.DeleteComment(r.Context(), r.PathValue("commentId")); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Obtiene el comentario de la ruta verificando que pertenezca a la tarea de la ruta
+func (ts *TaskServer) taskComment(r *http.Request) (taskstore.Comment, error) {
+	comment, err := ts.store.GetComment(r.Context(), r.PathValue("commentId"))
+	if err != nil {
+		return taskstore.Comment{}, err
+	}
+	if comment.TaskID != r.PathValue("id") {
+		return taskstore.Comment{}, taskstore.ErrCommentNotFound
+	}
+	return comment, nil
+}
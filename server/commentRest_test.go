@@ -0,0 +1,49 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestCommentHandlers(t *testing.T) {
+	ts := newTestServer()
+	ctx := context.Background()
+	task, _ := ts.store.CreateTask(ctx, "task", nil, time.Now(), nil, "")
+	other, _ := ts.store.CreateTask(ctx, "other", nil, time.Now(), nil, "")
+	comment, _ := ts.store.AddComment(ctx, task, "ana", "hello")
+
+	tests := []struct {
+		name       string
+		handler    http.HandlerFunc
+		method     string
+		body       string
+		task       string
+		comment    string
+		wantStatus int
+		wantCode   string
+	}{
+		{"create", ts.CreateCommentHandler, "POST", `{"author":"bob","body":"hi"}`, task, "", http.StatusCreated, ""},
+		{"create without body", ts.CreateCommentHandler, "POST", `{"author":"bob"}`, task, "", http.StatusUnprocessableEntity, "validation_failed"},
+		{"create on a missing task", ts.CreateCommentHandler, "POST", `{"author":"bob","body":"hi"}`, "404", "", http.StatusNotFound, "task_not_found"},
+		{"list", ts.GetCommentsHandler, "GET", "", task, "", http.StatusOK, ""},
+		{"list a missing task", ts.GetCommentsHandler, "GET", "", "404", "", http.StatusNotFound, "task_not_found"},
+		{"update", ts.UpdateCommentHandler, "PUT", `{"body":"edited"}`, task, comment.ID, http.StatusOK, ""},
+		{"update through another task", ts.UpdateCommentHandler, "PUT", `{"body":"edited"}`, other, comment.ID, http.StatusNotFound, "comment_not_found"},
+		{"delete through another task", ts.DeleteCommentHandler, "DELETE", "", other, comment.ID, http.StatusNotFound, "comment_not_found"},
+		{"delete", ts.DeleteCommentHandler, "DELETE", "", task, comment.ID, http.StatusNoContent, ""},
+		{"delete again", ts.DeleteCommentHandler, "DELETE", "", task, comment.ID, http.StatusNotFound, "comment_not_found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := call(tt.handler, tt.method, "/task/x/comments/", tt.body, "id", tt.task, "commentId", tt.comment)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if tt.wantCode != "" && problemCode(t, rec) != tt.wantCode {
+				t.Errorf("code = %q, want %q", problemCode(t, rec), tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,320 @@
+package server
+
+import (
+	"encoding/json"
+	"restServer/taskio"
+	"restServer/taskstore"
+	"restServer/validation"
+	"time"
+)
+
+// DTOs de REST y sus conversiones desde y hacia el dominio (taskstore). El formato
+// de cada version se define aqui y no depende del schema de GraphQL ni del
+// formato del archivo de datos.
+
+//-------------------------------------------- Peticiones ----------------------------------------//
+
+// Cuerpo JSON para crear una tarea en v1
+type requestTask struct {
+	Text        string         `json:"text"`
+	Tags        []string       `json:"tags"`
+	Due         string         `json:"due"`
+	Attachments []AttachmentV1 `json:"attachments"`
+	Project     string         `json:"project"`
+}
+
+// Cuerpo para crear una tarea en v2; el proyecto va en projectId como en la respuesta
+type requestTaskV2 struct {
+	Text        string         `json:"text"`
+	Tags        []string       `json:"tags"`
+	Due         string         `json:"due"`
+	Attachments []AttachmentV2 `json:"attachments"`
+	ProjectID   string         `json:"projectId"`
+}
+
+// Proyecto de la tarea en cada version
+func (req requestTask) project() string   { return req.Project }
+func (req requestTaskV2) project() string { return req.ProjectID }
+
+func (req requestTask) validate() (validation.Task, error) {
+	attachments := make([]*taskstore.Attachment, 0, len(req.Attachments))
+	for _, a := range req.Attachments {
+		attachments = append(attachments, &taskstore.Attachment{Name: a.Name, Date: a.Date, Contents: a.Contents})
+	}
+	return validateTask(req.Text, req.Tags, req.Due, attachments, validation.FieldsV1)
+}
+
+func (req requestTaskV2) validate() (validation.Task, error) {
+	attachments := make([]*taskstore.Attachment, 0, len(req.Attachments))
+	for _, a := range req.Attachments {
+		attachments = append(attachments, &taskstore.Attachment{Name: a.Name, Date: a.Date, Contents: a.Contents})
+	}
+	return validateTask(req.Text, req.Tags, req.Due, attachments, validation.FieldsCamel)
+}
+
+// Valida y normaliza una tarea con las reglas compartidas con GraphQL. Los
+// punteros de los errores son los campos del cuerpo de cada version.
+func validateTask(text string, tags []string, rawDue string, attachments []*taskstore.Attachment, fields validation.TaskFields) (validation.Task, error) {
+	due, dueErr := time.Parse(time.RFC3339, rawDue)
+	task := validation.Task{Text: text, Tags: tags, Due: due, Attachments: attachments}
+	errs := validation.DefaultTaskRules.Check(&task, fields)
+	if dueErr != nil && rawDue != "" {
+		errs.Set(validation.Pointer(fields.Due), "invalid_format", "due must be an RFC 3339 date-time")
+	}
+	return task, errs.Err()
+}
+
+//-------------------------------------------- Respuestas v1 ----------------------------------------//
+
+// Representaciones de la version 1, congeladas: campos en mayuscula como las
+// respuestas originales y listas vacias omitidas
+
+type AttachmentV1 struct {
+	Name     string    `json:"Name"`
+	Date     time.Time `json:"Date"`
+	Contents string    `json:"Contents"`
+}
+
+type TaskV1 struct {
+	ID          string         `json:"ID"`
+	Text        string         `json:"Text"`
+	Tags        []string       `json:"Tags,omitempty"`
+	Due         time.Time      `json:"Due"`
+	Attachments []AttachmentV1 `json:"Attachments,omitempty"`
+	ProjectID   *string        `json:"ProjectId,omitempty"`
+}
+
+type ProjectV1 struct {
+	ID          string `json:"ID"`
+	Name        string `json:"Name"`
+	Description string `json:"Description"`
+	TaskCount   int    `json:"TaskCount"`
+}
+
+type CommentV1 struct {
+	ID        string    `json:"ID"`
+	TaskID    string    `json:"TaskId"`
+	Author    string    `json:"Author"`
+	Body      string    `json:"Body"`
+	CreatedAt time.Time `json:"CreatedAt"`
+	UpdatedAt time.Time `json:"UpdatedAt"`
+}
+
+type RemindersV1 struct {
+	Offsets  []string `json:"Offsets"`
+	Channels []string `json:"Channels"`
+}
+
+func newTaskV1(task taskstore.Task) TaskV1 {
+	v1 := TaskV1{
+		ID:        task.ID,
+		Text:      task.Text,
+		Tags:      task.Tags,
+		Due:       task.Due,
+		ProjectID: task.ProjectID,
+	}
+	for _, a := range task.Attachments {
+		v1.Attachments = append(v1.Attachments, AttachmentV1(*a))
+	}
+	return v1
+}
+
+// Tarea de un registro de importacion v1
+func (v1 TaskV1) task() taskstore.Task {
+	task := taskstore.Task{ID: v1.ID, Text: v1.Text, Tags: v1.Tags, Due: v1.Due, ProjectID: v1.ProjectID}
+	for _, a := range v1.Attachments {
+		attachment := taskstore.Attachment(a)
+		task.Attachments = append(task.Attachments, &attachment)
+	}
+	return task
+}
+
+// Registros de /export y /import en JSON y NDJSON: la misma representacion que
+// GET /task/, independiente del archivo de datos (taskio.StoreLayout)
+var taskLayoutV1 = taskio.Layout{
+	Marshal: func(task taskstore.Task) any { return newTaskV1(task) },
+	Unmarshal: func(data []byte) (taskstore.Task, error) {
+		var v1 TaskV1
+		if err := json.Unmarshal(data, &v1); err != nil {
+			return taskstore.Task{}, err
+		}
+		return v1.task(), nil
+	},
+	Fields: validation.FieldsPascal,
+}
+
+func newTasksV1(tasks []taskstore.Task) []TaskV1 {
+	out := make([]TaskV1, 0, len(tasks))
+	for _, task := range tasks {
+		out = append(out, newTaskV1(task))
+	}
+	return out
+}
+
+func newProjectV1(project taskstore.Project) ProjectV1 {
+	return ProjectV1{
+		ID:          project.ID,
+		Name:        project.Name,
+		Description: project.Description,
+		TaskCount:   project.TaskCount,
+	}
+}
+
+func newProjectsV1(projects []taskstore.Project) []ProjectV1 {
+	out := make([]ProjectV1, 0, len(projects))
+	for _, project := range projects {
+		out = append(out, newProjectV1(project))
+	}
+	return out
+}
+
+func newCommentV1(comment taskstore.Comment) CommentV1 {
+	return CommentV1{
+		ID:        comment.ID,
+		TaskID:    comment.TaskID,
+		Author:    comment.Author,
+		Body:      comment.Body,
+		CreatedAt: comment.CreatedAt,
+		UpdatedAt: comment.UpdatedAt,
+	}
+}
+
+func newCommentsV1(comments []taskstore.Comment) []CommentV1 {
+	out := make([]CommentV1, 0, len(comments))
+	for _, comment := range comments {
+		out = append(out, newCommentV1(comment))
+	}
+	return out
+}
+
+func newRemindersV1(config taskstore.ReminderConfig) RemindersV1 {
+	return RemindersV1{Offsets: config.Offsets, Channels: config.Channels}
+}
+
+//-------------------------------------------- Respuestas v2 ----------------------------------------//
+
+// Representaciones de la version 2: JSON en camelCase igual que los cuerpos de las
+// peticiones, y las listas siempre presentes (vacias en lugar de omitidas)
+
+type AttachmentV2 struct {
+	Name     string    `json:"name"`
+	Date     time.Time `json:"date"`
+	Contents string    `json:"contents"`
+}
+
+type TaskV2 struct {
+	ID          string         `json:"id"`
+	Text        string         `json:"text"`
+	Tags        []string       `json:"tags"`
+	Due         time.Time      `json:"due"`
+	Attachments []AttachmentV2 `json:"attachments"`
+	ProjectID   string         `json:"projectId,omitempty"`
+}
+
+type ProjectV2 struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	TaskCount   int    `json:"taskCount"`
+}
+
+type CommentV2 struct {
+	ID        string    `json:"id"`
+	TaskID    string    `json:"taskId"`
+	Author    string    `json:"author"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type RemindersV2 struct {
+	Offsets  []string `json:"offsets"`
+	Channels []string `json:"channels"`
+}
+
+func newTaskV2(task taskstore.Task) TaskV2 {
+	v2 := TaskV2{
+		ID:          task.ID,
+		Text:        task.Text,
+		Tags:        task.Tags,
+		Due:         task.Due,
+		Attachments: make([]AttachmentV2, 0, len(task.Attachments)),
+	}
+	if v2.Tags == nil {
+		v2.Tags = []string{}
+	}
+	for _, a := range task.Attachments {
+		v2.Attachments = append(v2.Attachments, AttachmentV2(*a))
+	}
+	if task.ProjectID != nil {
+		v2.ProjectID = *task.ProjectID
+	}
+	return v2
+}
+
+// Tarea de un registro de importacion v2
+func (v2 TaskV2) task() taskstore.Task {
+	task := taskstore.Task{ID: v2.ID, Text: v2.Text, Tags: v2.Tags, Due: v2.Due}
+	for _, a := range v2.Attachments {
+		attachment := taskstore.Attachment(a)
+		task.Attachments = append(task.Attachments, &attachment)
+	}
+	if v2.ProjectID != "" {
+		project := v2.ProjectID
+		task.ProjectID = &project
+	}
+	return task
+}
+
+// Registros de /v2/export y /v2/import en JSON y NDJSON: la misma representacion
+// que GET /v2/tasks
+var taskLayoutV2 = taskio.Layout{
+	Marshal: func(task taskstore.Task) any { return newTaskV2(task) },
+	Unmarshal: func(data []byte) (taskstore.Task, error) {
+		var v2 TaskV2
+		if err := json.Unmarshal(data, &v2); err != nil {
+			return taskstore.Task{}, err
+		}
+		return v2.task(), nil
+	},
+	Fields: validation.FieldsCamel,
+}
+
+func newTasksV2(tasks []taskstore.Task) []TaskV2 {
+	out := make([]TaskV2, 0, len(tasks))
+	for _, task := range tasks {
+		out = append(out, newTaskV2(task))
+	}
+	return out
+}
+
+func newProjectV2(project taskstore.Project) ProjectV2 {
+	return ProjectV2{
+		ID:          project.ID,
+		Name:        project.Name,
+		Description: project.Description,
+		TaskCount:   project.TaskCount,
+	}
+}
+
+func newCommentV2(comment taskstore.Comment) CommentV2 {
+	return CommentV2{
+		ID:        comment.ID,
+		TaskID:    comment.TaskID,
+		Author:    comment.Author,
+		Body:      comment.Body,
+		CreatedAt: comment.CreatedAt,
+		UpdatedAt: comment.UpdatedAt,
+	}
+}
+
+func newRemindersV2(config taskstore.ReminderConfig) RemindersV2 {
+	v2 := RemindersV2{Offsets: config.Offsets, Channels: config.Channels}
+	if v2.Offsets == nil {
+		v2.Offsets = []string{}
+	}
+	if v2.Channels == nil {
+		v2.Channels = []string{}
+	}
+	return v2
+}
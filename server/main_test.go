@@ -0,0 +1,52 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"restServer/taskstore"
+	"strings"
+	"testing"
+)
+
+// Servidor sobre un store nuevo, sin workers de webhooks
+func newTestServer() *TaskServer {
+	return NewTaskServerWith(taskstore.New(), 0)
+}
+
+// Ejecuta h con una peticion JSON; pathValues son pares nombre, valor del patron
+func call(h http.HandlerFunc, method, target, body string, pathValues ...string) *httptest.ResponseRecorder {
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	r := httptest.NewRequest(method, target, reader)
+	if body != "" {
+		r.Header.Set("Content-Type", "application/json")
+	}
+	for i := 0; i+1 < len(pathValues); i += 2 {
+		r.SetPathValue(pathValues[i], pathValues[i+1])
+	}
+	rec := httptest.NewRecorder()
+	h(rec, r)
+	return rec
+}
+
+// Decodifica el cuerpo JSON de la respuesta en v
+func decodeResponse(t *testing.T, rec *httptest.ResponseRecorder, v any) {
+	t.Helper()
+	if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+		t.Fatalf("decoding %q: %v", rec.Body.String(), err)
+	}
+}
+
+// code de un cuerpo problem+json
+func problemCode(t *testing.T, rec *httptest.ResponseRecorder) string {
+	t.Helper()
+	var p struct {
+		Code string `json:"code"`
+	}
+	decodeResponse(t, rec, &p)
+	return p.Code
+}
@@ -0,0 +1,193 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"restServer/codec"
+	"restServer/problem"
+	"strconv"
+	"strings"
+)
+
+// Formatos de respuesta en orden de preferencia: con "Accept: */*" o sin Accept se responde JSON
+const (
+	mediaJSON    = "application/json"
+	mediaNDJSON  = "application/x-ndjson"
+	mediaCSV     = "text/csv"
+	mediaMsgPack = codec.MsgPack
+	mediaCBOR    = codec.CBOR
+)
+
+var responseTypes = []string{mediaJSON, mediaNDJSON, mediaCSV, mediaMsgPack, mediaCBOR}
+
+// Nombres alternativos que se aceptan en Accept y Content-Type
+var mediaAliases = map[string]string{
+	"application/ndjson":      mediaNDJSON,
+	"application/x-msgpack":   mediaMsgPack,
+	"application/vnd.msgpack": mediaMsgPack,
+}
+
+var (
+	errUnsupportedMediaType = problem.New(http.StatusUnsupportedMediaType, "unsupported_media_type",
+		"Content-Type must be application/json, application/msgpack or application/cbor")
+	errNotAcceptable = problem.New(http.StatusNotAcceptable, "not_acceptable",
+		"Accept must allow one of "+strings.Join(responseTypes, ", "))
+)
+
+type mediaTypeKey struct{}
+
+// Negotiate elige el formato de la respuesta a partir de Accept antes de ejecutar
+// el handler, asi una peticion con un Accept imposible falla con 406 sin efectos.
+// Los errores siempre se responden como application/problem+json.
+func Negotiate(h http.Handler) http.Handler {
+	return negotiateHandler(h, false)
+}
+
+// Como Negotiate, pero si Accept no admite ningun formato responde JSON en lugar
+// de 406. Es para las rutas v1, congeladas: antes ignoraban Accept.
+func NegotiateOrJSON(h http.Handler) http.Handler {
+	return negotiateHandler(h, true)
+}
+
+func negotiateHandler(h http.Handler, fallback bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept")
+		mediaType, ok := negotiate(r.Header.Get("Accept"), responseTypes)
+		if !ok && fallback {
+			mediaType = mediaJSON
+		} else if !ok {
+			problem.Write(w, r, errNotAcceptable)
+			return
+		}
+		h.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), mediaTypeKey{}, mediaType)))
+	})
+}
+
+// Formato elegido por Negotiate; JSON si el handler se usa sin el middleware
+func responseType(r *http.Request) string {
+	if mediaType, ok := r.Context().Value(mediaTypeKey{}).(string); ok {
+		return mediaType
+	}
+	return mediaJSON
+}
+
+// Elige entre offers segun un Accept (RFC 9110, 12.5.1): gana la mayor calidad, y
+// a igual calidad el primero de offers. La calidad de cada oferta es la del rango
+// mas especifico que la incluye. Un Accept vacio acepta cualquier oferta.
+func negotiate(accept string, offers []string) (string, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return offers[0], true
+	}
+
+	type mediaRange struct {
+		typ, subtype string
+		q            float64
+	}
+	var ranges []mediaRange
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if raw, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(raw, 64); err != nil || q < 0 || q > 1 {
+				continue
+			}
+		}
+		if alias, ok := mediaAliases[mediaType]; ok {
+			mediaType = alias
+		}
+		typ, subtype, _ := strings.Cut(mediaType, "/")
+		ranges = append(ranges, mediaRange{typ, subtype, q})
+	}
+
+	best, bestQ := "", 0.0
+	for _, offer := range offers {
+		typ, subtype, _ := strings.Cut(offer, "/")
+		q, specificity := 0.0, -1
+		for _, mr := range ranges {
+			s := -1
+			switch {
+			case mr.typ == typ && mr.subtype == subtype:
+				s = 2
+			case mr.typ == typ && mr.subtype == "*":
+				s = 1
+			case mr.typ == "*" && mr.subtype == "*":
+				s = 0
+			}
+			if s > specificity {
+				q, specificity = mr.q, s
+			}
+		}
+		if q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+	return best, best != ""
+}
+
+// Tamaño maximo del cuerpo de una peticion, el mismo que el de una importacion
+const maxBodyBytes = maxImportBytes
+
+// Decodifica el cuerpo en v segun su Content-Type (JSON, MessagePack o CBOR) con
+// las mismas reglas en los tres: campos desconocidos son un error. Responde el
+// error si falla.
+func decodeBody(w http.ResponseWriter, r *http.Request, v any) bool {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		problem.Write(w, r, problem.New(http.StatusBadRequest, "invalid_content_type", err.Error()))
+		return false
+	}
+	if alias, ok := mediaAliases[mediaType]; ok {
+		mediaType = alias
+	}
+
+	var body io.Reader = http.MaxBytesReader(w, r.Body, maxBodyBytes)
+	switch mediaType {
+	case mediaJSON:
+	case mediaMsgPack, mediaCBOR:
+		data, err := io.ReadAll(body)
+		if err != nil {
+			problem.Write(w, r, bodyError(err, "invalid_body"))
+			return false
+		}
+		js, err := codec.ToJSON(mediaType, data)
+		if err != nil {
+			problem.Write(w, r, problem.New(http.StatusBadRequest, "invalid_body", err.Error()))
+			return false
+		}
+		body = bytes.NewReader(js)
+	default:
+		problem.Write(w, r, errUnsupportedMediaType)
+		return false
+	}
+
+	decoder := json.NewDecoder(body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		code := "invalid_json"
+		if mediaType != mediaJSON {
+			code = "invalid_body"
+		}
+		problem.Write(w, r, bodyError(err, code))
+		return false
+	}
+	return true
+}
+
+// Error al leer el cuerpo: 413 si supera maxBodyBytes, si no 400 con code
+func bodyError(err error, code string) error {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return problem.New(http.StatusRequestEntityTooLarge, "body_too_large",
+			fmt.Sprintf("request body must be at most %d bytes", tooLarge.Limit))
+	}
+	return problem.New(http.StatusBadRequest, code, err.Error())
+}
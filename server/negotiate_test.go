@@ -0,0 +1,138 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"restServer/codec"
+	"strings"
+	"testing"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   string
+		ok     bool
+	}{
+		{"", mediaJSON, true},
+		{"*/*", mediaJSON, true},
+		{"application/*", mediaJSON, true},
+		{"application/cbor", mediaCBOR, true},
+		{"application/x-msgpack", mediaMsgPack, true},
+		{"application/ndjson", mediaNDJSON, true},
+		{"text/*", mediaCSV, true},
+		{"application/json;q=0.5, application/cbor", mediaCBOR, true},
+		{"application/*;q=0.5, application/msgpack;q=0.8", mediaMsgPack, true},
+		{"*/*;q=0.1, application/json;q=0", mediaNDJSON, true},
+		{"application/cbor;q=2, text/csv", mediaCSV, true},
+		{"text/html", "", false},
+		{"application/json;q=0", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := negotiate(tt.accept, responseTypes)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("negotiate(%q) = %q, %v, want %q, %v", tt.accept, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestNegotiateHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		middleware func(http.Handler) http.Handler
+		accept     string
+		wantStatus int
+		wantType   string
+	}{
+		{"v2 chooses a format", Negotiate, "application/cbor", http.StatusOK, mediaCBOR},
+		{"v2 rejects unknown formats", Negotiate, "text/html", http.StatusNotAcceptable, ""},
+		{"v1 chooses a format", NegotiateOrJSON, "application/cbor", http.StatusOK, mediaCBOR},
+		{"v1 falls back to json", NegotiateOrJSON, "text/html", http.StatusOK, mediaJSON},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			h := tt.middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = responseType(r)
+			}))
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("Accept", tt.accept)
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, r)
+			if rec.Code != tt.wantStatus || got != tt.wantType {
+				t.Errorf("status %d, type %q, want %d, %q", rec.Code, got, tt.wantStatus, tt.wantType)
+			}
+			if rec.Header().Get("Vary") != "Accept" {
+				t.Errorf("Vary = %q", rec.Header().Get("Vary"))
+			}
+		})
+	}
+}
+
+func TestDecodeBody(t *testing.T) {
+	type body struct {
+		Text string   `json:"text"`
+		Tags []string `json:"tags"`
+	}
+	value := map[string]any{"text": "a", "tags": []string{"x"}}
+	encode := func(format string, v any) []byte {
+		data, err := codec.Marshal(format, v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+	js, _ := json.Marshal(value)
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        []byte
+		wantStatus  int // 0 si se decodifica
+		wantCode    string
+	}{
+		{"json", "application/json; charset=utf-8", js, 0, ""},
+		{"msgpack", codec.MsgPack, encode(codec.MsgPack, value), 0, ""},
+		{"msgpack alias", "application/x-msgpack", encode(codec.MsgPack, value), 0, ""},
+		{"cbor", codec.CBOR, encode(codec.CBOR, value), 0, ""},
+		{"unknown json field", mediaJSON, []byte(`{"text":"a","extra":1}`), http.StatusBadRequest, "invalid_json"},
+		{"unknown cbor field", codec.CBOR, encode(codec.CBOR, map[string]any{"extra": 1}), http.StatusBadRequest, "invalid_body"},
+		{"malformed msgpack", codec.MsgPack, []byte{0x82}, http.StatusBadRequest, "invalid_body"},
+		{"unsupported type", "text/plain", []byte("a"), http.StatusUnsupportedMediaType, "unsupported_media_type"},
+		{"missing type", "", js, http.StatusBadRequest, "invalid_content_type"},
+		{"json too large", mediaJSON, []byte(`{"text":"` + strings.Repeat("x", maxBodyBytes) + `"}`), http.StatusRequestEntityTooLarge, "body_too_large"},
+		{"cbor too large", codec.CBOR, bytes.Repeat([]byte{0}, maxBodyBytes+1), http.StatusRequestEntityTooLarge, "body_too_large"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(tt.body))
+			r.Header.Set("Content-Type", tt.contentType)
+			rec := httptest.NewRecorder()
+
+			var got body
+			ok := decodeBody(rec, r, &got)
+			if ok != (tt.wantStatus == 0) {
+				t.Fatalf("decodeBody() = %v, response %d %s", ok, rec.Code, rec.Body)
+			}
+			if ok {
+				if got.Text != "a" || len(got.Tags) != 1 || got.Tags[0] != "x" {
+					t.Errorf("decoded %+v", got)
+				}
+				return
+			}
+
+			var problem struct {
+				Status int    `json:"status"`
+				Code   string `json:"code"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+				t.Fatal(err)
+			}
+			if rec.Code != tt.wantStatus || problem.Code != tt.wantCode {
+				t.Errorf("response %d %q, want %d %q", rec.Code, problem.Code, tt.wantStatus, tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"restServer/logging"
+	"restServer/problem"
+	"restServer/taskstore"
+	"strconv"
+)
+
+type requestProject struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+//-------------------------------------------- Controladores de proyectos ----------------------------------------//
+
+// CreateProjectHandler godoc
+// @Summary Crear un proyecto
+// @Description Crea un nuevo proyecto que agrupa tareas
+// @Tags project
+// @Accept json
+// @Produce json
+// @Param project body object true "Nuevo proyecto"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} problem.Problem
+// @Failure 415 {object} problem.Problem
+// @Failure 422 {object} problem.Problem
+// @Router /project/ [post]
+func (ts *TaskServer) CreateProjectHandler(w http.ResponseWriter, r *http.Request) {
+	logging.FromContext(r.Context()).Debug("handling project create")
+
+	req, ok := decodeProject(w, r)
+	if !ok {
+		return
+	}
+
+	id := ts.store.CreateProject(r.Context(), req.Name, req.Description)
+	render(w, r, map[string]string{"id": id})
+}
+
+// GetAllProjectsHandler godoc
+// @Summary Listar proyectos
+// @Description Devuelve los proyectos paginados, el total va en la cabecera X-Total-Count
+// @Tags project
+// @Produce json
+// @Param offset query int false "Desplazamiento"
+// @Param limit query int false "Cantidad maxima"
+// @Success 200 {array} server.ProjectV1
+// @Failure 400 {object} problem.Problem
+// @Router /project/ [get]
+func (ts *TaskServer) GetAllProjectsHandler(w http.ResponseWriter, r *http.Request) {
+	logging.FromContext(r.Context()).Debug("handling project get all")
+
+	offset, limit, err := pagination(r)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	projects, total, err := ts.store.GetAllProjects(r.Context(), offset, limit)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	render(w, r, newProjectsV1(projects))
+}
+
+// GetProjectHandler godoc
+// @Summary Obtener un proyecto
+// @Description Obtiene un proyecto por ID
+// @Tags project
+// @Produce json
+// @Param id path int true "ID del proyecto"
+// @Success 200 {object} server.ProjectV1
+// @Failure 404 {object} problem.Problem
+// @Router /project/{id}/ [get]
+func (ts *TaskServer) GetProjectHandler(w http.ResponseWriter, r *http.Request) {
+	logging.FromContext(r.Context()).Debug("handling project get")
+
+	project, err := ts.store.GetProject(r.Context(), r.PathValue("id"))
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	render(w, r, newProjectV1(project))
+}
+
+// UpdateProjectHandler godoc
+// @Summary Actualizar un proyecto
+// @Description Reemplaza nombre y descripcion de un proyecto
+// @Tags project
+// @Accept json
+// @Produce json
+// @Param id path int true "ID del proyecto"
+// @Param project body object true "Proyecto"
+// @Success 200 {object} server.ProjectV1
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 415 {object} problem.Problem
+// @Failure 422 {object} problem.Problem
+// @Router /project/{id}/ [put]
+func (ts *TaskServer) UpdateProjectHandler(w http.ResponseWriter, r *http.Request) {
+	logging.FromContext(r.Context()).Debug("handling project update")
+
+	req, ok := decodeProject(w, r)
+	if !ok {
+		return
+	}
+
+	project, err := ts.store.UpdateProject(r.Context(), r.PathValue("id"), req.Name, req.Description)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	render(w, r, newProjectV1(project))
+}
+
+// DeleteProjectHandler godoc
+// @Summary Eliminar un proyecto
+// @Description Elimina un proyecto. Con mode=reject (por defecto) falla con 409 si tiene tareas, con mode=cascade elimina tambien sus tareas
+// @Tags project
+// @Param id path int true "ID del proyecto"
+// @Param mode query string false "reject o cascade"
+// @Success 204
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 409 {object} problem.Problem
+// @Router /project/{id}/ [delete]
+func (ts *TaskServer) DeleteProjectHandler(w http.ResponseWriter, r *http.Request) {
+	logging.FromContext(r.Context()).Debug("handling project delete")
+
+	mode := taskstore.DeleteReject
+	switch r.URL.Query().Get("mode") {
+	case "", string(taskstore.DeleteReject):
+	case string(taskstore.DeleteCascade):
+		mode = taskstore.DeleteCascade
+	default:
+		problem.Write(w, r, problem.New(http.StatusBadRequest, "invalid_parameter", "mode must be reject or cascade"))
+		return
+	}
+
+	err := ts.store.DeleteProject(r.Context(), r.PathValue("id"), mode)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetProjectTasksHandler godoc
+// @Summary Tareas de un proyecto
+// @Description Devuelve las tareas de un proyecto paginadas, el total va en la cabecera X-Total-Count
+// @Tags project
+// @Produce json,application/x-ndjson,text/csv,application/msgpack,application/cbor
+// @Param id path int true "ID del proyecto"
+// @Param offset query int false "Desplazamiento"
+// @Param limit query int false "Cantidad maxima"
+// @Success 200 {array} server.TaskV1
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 406 {object} problem.Problem
+// @Router /project/{id}/tasks/ [get]
+func (ts *TaskServer) GetProjectTasksHandler(w http.ResponseWriter, r *http.Request) {
+	logging.FromContext(r.Context()).Debug("handling project tasks")
+
+	offset, limit, err := pagination(r)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	tasks, total, err := ts.store.GetTasksByProject(r.Context(), r.PathValue("id"), offset, limit)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	render(w, r, newTasksV1(tasks))
+}
+
+// Decodifica el cuerpo JSON de un proyecto, responde el error si falla
+func decodeProject(w http.ResponseWriter, r *http.Request) (requestProject, bool) {
+	var req requestProject
+	if !decodeBody(w, r, &req) {
+		return req, false
+	}
+	if req.Name == "" {
+		problem.Write(w, r, fmt.Errorf("%w: name is required", taskstore.ErrValidation))
+		return req, false
+	}
+	return req, true
+}
+
+// Lee los parametros offset y limit de la query (0 si no se envian)
+func pagination(r *http.Request) (int, int, error) {
+	values := [2]int{}
+	for i, name := range []string{"offset", "limit"} {
+		raw := r.URL.Query().Get(name)
+		if raw == "" {
+			continue
+		}
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			return 0, 0, problem.New(http.StatusBadRequest, "invalid_parameter", name+" must be a non-negative integer")
+		}
+		values[i] = n
+	}
+	return values[0], values[1], nil
+}
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestProjectHandlers(t *testing.T) {
+	ts := newTestServer()
+	ctx := context.Background()
+	withTasks := ts.store.CreateProject(ctx, "work", "")
+	empty := ts.store.CreateProject(ctx, "home", "")
+	ts.store.CreateTask(ctx, "task", nil, time.Now(), nil, withTasks)
+
+	tests := []struct {
+		name       string
+		handler    http.HandlerFunc
+		method     string
+		target     string
+		body       string
+		id         string
+		wantStatus int
+		wantCode   string
+	}{
+		{"create", ts.CreateProjectHandler, "POST", "/project/", `{"name":"new"}`, "", http.StatusOK, ""},
+		{"create without name", ts.CreateProjectHandler, "POST", "/project/", `{"description":"x"}`, "", http.StatusUnprocessableEntity, "validation_failed"},
+		{"list", ts.GetAllProjectsHandler, "GET", "/project/?offset=1&limit=1", "", "", http.StatusOK, ""},
+		{"invalid limit", ts.GetAllProjectsHandler, "GET", "/project/?limit=-1", "", "", http.StatusBadRequest, "invalid_parameter"},
+		{"get", ts.GetProjectHandler, "GET", "/project/x/", "", withTasks, http.StatusOK, ""},
+		{"get missing", ts.GetProjectHandler, "GET", "/project/x/", "", "404", http.StatusNotFound, "project_not_found"},
+		{"update", ts.UpdateProjectHandler, "PUT", "/project/x/", `{"name":"office"}`, withTasks, http.StatusOK, ""},
+		{"project tasks", ts.GetProjectTasksHandler, "GET", "/project/x/tasks/", "", withTasks, http.StatusOK, ""},
+		{"delete with tasks", ts.DeleteProjectHandler, "DELETE", "/project/x/", "", withTasks, http.StatusConflict, "project_has_tasks"},
+		{"invalid mode", ts.DeleteProjectHandler, "DELETE", "/project/x/?mode=all", "", withTasks, http.StatusBadRequest, "invalid_parameter"},
+		{"delete empty", ts.DeleteProjectHandler, "DELETE", "/project/x/", "", empty, http.StatusNoContent, ""},
+		{"delete cascade", ts.DeleteProjectHandler, "DELETE", "/project/x/?mode=cascade", "", withTasks, http.StatusNoContent, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := call(tt.handler, tt.method, tt.target, tt.body, "id", tt.id)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if tt.wantCode != "" && problemCode(t, rec) != tt.wantCode {
+				t.Errorf("code = %q, want %q", problemCode(t, rec), tt.wantCode)
+			}
+		})
+	}
+
+	// Tras la cascada no queda la tarea del proyecto
+	if tasks, _ := ts.store.GetAllTasks(ctx); len(tasks) != 0 {
+		t.Errorf("%d tasks left after the cascade", len(tasks))
+	}
+}
+
+func TestProjectListHeaders(t *testing.T) {
+	ts := newTestServer()
+	for range 3 {
+		ts.store.CreateProject(context.Background(), "p", "")
+	}
+	rec := call(ts.GetAllProjectsHandler, "GET", "/project/?limit=2", "")
+	var projects []ProjectV1
+	decodeResponse(t, rec, &projects)
+	if len(projects) != 2 || rec.Header().Get("X-Total-Count") != "3" {
+		t.Errorf("%d projects, X-Total-Count %q", len(projects), rec.Header().Get("X-Total-Count"))
+	}
+}
@@ -0,0 +1,62 @@
+package server
+
+import (
+	"net/http"
+	"restServer/logging"
+	"restServer/problem"
+)
+
+//-------------------------------------------- Controladores de recordatorios ----------------------------------------//
+
+// GetRemindersHandler godoc
+// @Summary Recordatorios de una tarea
+// @Description Devuelve la configuracion de recordatorios de una tarea (vacia si usa los offsets por defecto)
+// @Tags reminder
+// @Produce json
+// @Param id path int true "ID de la tarea"
+// @Success 200 {object} server.RemindersV1
+// @Failure 404 {object} problem.Problem
+// @Router /task/{id}/reminders/ [get]
+func (ts *TaskServer) GetRemindersHandler(w http.ResponseWriter, r *http.Request) {
+	logging.FromContext(r.Context()).Debug("handling reminders get")
+
+	config, _, err := ts.store.GetReminders(r.Context(), r.PathValue("id"))
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	render(w, r, newRemindersV1(config))
+}
+
+// SetRemindersHandler godoc
+// @Summary Configurar recordatorios
+// @Description Reemplaza los recordatorios de una tarea. offsets son duraciones antes de Due ("24h", "15m"), channels los notificadores (log, webhook, smtp). Una lista de offsets vacia vuelve a los valores por defecto.
+// @Tags reminder
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de la tarea"
+// @Param reminders body object true "Offsets y canales"
+// @Success 200 {object} server.RemindersV1
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 415 {object} problem.Problem
+// @Failure 422 {object} problem.Problem
+// @Router /task/{id}/reminders/ [put]
+func (ts *TaskServer) SetRemindersHandler(w http.ResponseWriter, r *http.Request) {
+	logging.FromContext(r.Context()).Debug("handling reminders set")
+
+	var req struct {
+		Offsets  []string `json:"offsets"`
+		Channels []string `json:"channels"`
+	}
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	config, err := ts.store.SetReminders(r.Context(), r.PathValue("id"), req.Offsets, req.Channels)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	render(w, r, newRemindersV1(config))
+}
@@ -0,0 +1,175 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"restServer/codec"
+	"restServer/logging"
+	"restServer/problem"
+	"strings"
+)
+
+// Filas de NDJSON entre cada Flush, el cliente recibe los listados grandes por partes
+const ndjsonFlushEvery = 100
+
+func render(w http.ResponseWriter, r *http.Request, v any) {
+	renderStatus(w, r, http.StatusOK, v)
+}
+
+// Escribe v en el formato negociado para la peticion (ver Negotiate)
+func renderStatus(w http.ResponseWriter, r *http.Request, status int, v any) {
+	mediaType := responseType(r)
+	if mediaType == mediaNDJSON {
+		renderNDJSON(w, r, status, v)
+		return
+	}
+
+	var (
+		body []byte
+		err  error
+	)
+	switch mediaType {
+	case mediaCSV:
+		body, err = marshalCSV(v)
+	case mediaMsgPack, mediaCBOR:
+		body, err = codec.Marshal(mediaType, v)
+	default:
+		body, err = json.Marshal(v)
+	}
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	w.Header().Set("Content-Type", mediaType)
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
+
+// Una linea JSON por elemento si v es una lista (si no, una sola linea), escritas
+// a medida que se codifican
+func renderNDJSON(w http.ResponseWriter, r *http.Request, status int, v any) {
+	items := reflect.ValueOf(v)
+	if items.Kind() != reflect.Slice && items.Kind() != reflect.Array {
+		items = reflect.ValueOf([]any{v})
+	}
+
+	w.Header().Set("Content-Type", mediaNDJSON)
+	w.WriteHeader(status)
+	out := bufio.NewWriter(w)
+	flusher := http.NewResponseController(w)
+	encoder := json.NewEncoder(out)
+	for i := range items.Len() {
+		if err := encoder.Encode(items.Index(i).Interface()); err != nil {
+			// Las cabeceras ya se enviaron, solo queda registrarlo
+			logging.FromContext(r.Context()).Error("ndjson encode failed", "err", err)
+			break
+		}
+		if (i+1)%ndjsonFlushEvery == 0 {
+			_ = out.Flush()
+			_ = flusher.Flush()
+		}
+	}
+	_ = out.Flush()
+}
+
+// CSV con una fila por elemento (o una sola si v no es lista) y una columna por
+// campo JSON, en el orden de aparicion. Las listas de textos van separadas por ";"
+// como en el export, el resto de valores compuestos como JSON.
+func marshalCSV(v any) ([]byte, error) {
+	js, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var rows []json.RawMessage
+	if len(js) > 0 && js[0] == '[' {
+		if err := json.Unmarshal(js, &rows); err != nil {
+			return nil, err
+		}
+	} else {
+		rows = []json.RawMessage{js}
+	}
+
+	var header []string
+	columns := make(map[string]int)
+	records := make([]map[string]string, 0, len(rows))
+	for _, row := range rows {
+		fields, err := csvFields(row)
+		if err != nil {
+			return nil, err
+		}
+		record := make(map[string]string, len(fields))
+		for _, field := range fields {
+			if _, ok := columns[field.name]; !ok {
+				columns[field.name] = len(header)
+				header = append(header, field.name)
+			}
+			record[field.name] = field.value
+		}
+		records = append(records, record)
+	}
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if len(header) > 0 {
+		_ = writer.Write(header)
+	}
+	line := make([]string, len(header))
+	for _, record := range records {
+		for i, name := range header {
+			line[i] = record[name]
+		}
+		_ = writer.Write(line)
+	}
+	writer.Flush()
+	return buf.Bytes(), writer.Error()
+}
+
+type csvField struct {
+	name, value string
+}
+
+// Campos de un objeto JSON en orden; un valor que no es objeto es la columna "value"
+func csvFields(row json.RawMessage) ([]csvField, error) {
+	if len(row) == 0 || row[0] != '{' {
+		return []csvField{{"value", csvCell(row)}}, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(row))
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+	var fields []csvField
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return nil, err
+		}
+		fields = append(fields, csvField{key.(string), csvCell(value)})
+	}
+	return fields, nil
+}
+
+func csvCell(value json.RawMessage) string {
+	switch {
+	case string(value) == "null":
+		return ""
+	case value[0] == '"':
+		var s string
+		_ = json.Unmarshal(value, &s)
+		return s
+	case value[0] == '[':
+		var list []string
+		if json.Unmarshal(value, &list) == nil {
+			return strings.Join(list, ";")
+		}
+	}
+	return string(value)
+}
@@ -1,24 +1,34 @@
 package server
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
-	"mime"
 	"net/http"
-	"restServer/graph/model"
+	"restServer/logging"
+	"restServer/problem"
 	"restServer/taskstore"
+	"restServer/webhook"
 	"strconv"
 	"time"
 )
 
 type TaskServer struct {
-	store *taskstore.TaskStore
+	store    *taskstore.TaskStore
+	webhooks *webhook.Dispatcher
 }
 
 func NewTaskServer() *TaskServer {
-	store := taskstore.New()
-	return &TaskServer{store: store}
+	return NewTaskServerWith(taskstore.New(), 4)
+}
+
+// NewTaskServerWith usa un store existente (p.ej. cargado de un archivo de datos)
+// y la cantidad de workers de webhooks indicada
+func NewTaskServerWith(store *taskstore.TaskStore, webhookWorkers int) *TaskServer {
+	// Los cambios del store se publican a los webhooks suscritos
+	webhooks := webhook.NewDispatcher(webhookWorkers)
+	store.Subscribe(webhooks.Publish)
+
+	return &TaskServer{store: store, webhooks: webhooks}
 }
 
 // GetStore retorna el TaskStore para ser usado por GraphQL
@@ -26,82 +36,53 @@ func (ts *TaskServer) GetStore() *taskstore.TaskStore {
 	return ts.store
 }
 
+// GetWebhooks retorna el dispatcher de webhooks para ser usado por GraphQL
+func (ts *TaskServer) GetWebhooks() *webhook.Dispatcher {
+	return ts.webhooks
+}
+
 //-------------------------------------------- Controladores ----------------------------------------//
 
 // CreateTaskHandler godoc
 // @Summary Crear una tarea
 // @Description Crea una nueva tarea
 // @Tags task
-// @Accept json
-// @Produce json
+// @Accept json,application/msgpack,application/cbor
+// @Produce json,application/msgpack,application/cbor
 // @Param task body object true "Nueva tarea"
 // @Success 200 {object} map[string]int
-// @Failure 400 {string} string
-// @Failure 415 {string} string
+// @Failure 400 {object} problem.Problem
+// @Failure 415 {object} problem.Problem
+// @Failure 422 {object} problem.Problem
 // @Router /task/ [post]
 func (ts *TaskServer) CreateTaskHandler(w http.ResponseWriter, r *http.Request) {
-	log.Printf("handling task create at %s\n", r.URL.Path)
-
-	type RequestTask struct {
-		Text        string              `json:"text"`
-		Tags        []string            `json:"tags"`
-		Due         string              `json:"due"`
-		Attachments []*model.Attachment `json:"attachments"`
-	}
+	logging.FromContext(r.Context()).Debug("handling task create")
 
 	type ResponseId struct {
 		Id string `json:"id"`
 	}
 
-	contentType := r.Header.Get("Content-Type")
-	mediaType, _, err := mime.ParseMediaType(contentType)
-
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
-	}
-
-	if mediaType != "application/json" {
-		http.Error(w, "Content-Type must be application/json", http.StatusUnsupportedMediaType)
+	var req requestTask
+	if !decodeBody(w, r, &req) {
 		return
 	}
 
-	decoder := json.NewDecoder(r.Body)
-	decoder.DisallowUnknownFields()
-
-	var req RequestTask
-
-	if err := decoder.Decode(&req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
-	}
-
-	due, err := time.Parse(time.RFC3339, req.Due)
+	task, err := req.validate()
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		problem.Write(w, r, err)
 		return
 	}
 
-	id := ts.store.CreateTask(req.Text, req.Tags, due, req.Attachments)
-	js, err := json.Marshal(ResponseId{Id: id})
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+	id, err := ts.store.CreateTask(r.Context(), task.Text, task.Tags, task.Due, task.Attachments, req.Project)
+	if errors.Is(err, taskstore.ErrProjectNotFound) {
+		// El proyecto referenciado es un dato invalido de la peticion, no un recurso ausente
+		err = fmt.Errorf("%w: %w", taskstore.ErrValidation, err)
 	}
-	w.Header().Set("Content-Type", "application/json")
-	_, _ = w.Write(js)
-
-}
-
-func renderJSON(w http.ResponseWriter, v interface{}) {
-	js, err := json.Marshal(v)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		problem.Write(w, r, err)
 		return
 	}
-	w.Header().Set("Content-Type", "application/json")
-	_, _ = w.Write(js)
-	w.WriteHeader(http.StatusOK)
+	render(w, r, ResponseId{Id: id})
 }
 
 // GetTaskHandler godoc
@@ -110,21 +91,20 @@ func renderJSON(w http.ResponseWriter, v interface{}) {
 // @Tags task
 // @Produce json
 // @Param id path int true "ID de la tarea"
-// @Success 200 {object} taskstore.Task
-// @Failure 400 {string} string
-// @Failure 500 {string} string
+// @Success 200 {object} server.TaskV1
+// @Failure 404 {object} problem.Problem
 // @Router /task/{id}/ [get]
 func (ts *TaskServer) GetTaskHandler(w http.ResponseWriter, r *http.Request) {
-	log.Printf("handling task get at %s\n", r.URL.Path)
+	logging.FromContext(r.Context()).Debug("handling task get")
 
 	id := r.PathValue("id")
 
-	task, err := ts.store.GetTask(id)
+	task, err := ts.store.GetTask(r.Context(), id)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		problem.Write(w, r, err)
 		return
 	}
-	renderJSON(w, task)
+	render(w, r, newTaskV1(task))
 }
 
 // DeleteTaskHandler godoc
@@ -133,18 +113,17 @@ func (ts *TaskServer) GetTaskHandler(w http.ResponseWriter, r *http.Request) {
 // @Tags task
 // @Param id path int true "ID de la tarea"
 // @Success 204
-// @Failure 400 {string} string
-// @Failure 500 {string} string
+// @Failure 404 {object} problem.Problem
 // @Router /task/{id}/ [delete]
 // @Security BasicAuth
 func (ts *TaskServer) DeleteTaskHandler(w http.ResponseWriter, r *http.Request) {
-	log.Printf("handling task delete at %s\n", r.URL.Path)
+	logging.FromContext(r.Context()).Debug("handling task delete")
 
 	idTask := r.PathValue("id")
 
-	err := ts.store.DeleteTask(idTask)
+	err := ts.store.DeleteTask(r.Context(), idTask)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		problem.Write(w, r, err)
 		return
 	}
 	w.WriteHeader(http.StatusNoContent)
@@ -155,22 +134,21 @@ func (ts *TaskServer) DeleteTaskHandler(w http.ResponseWriter, r *http.Request)
 // @Summary Obtener todas las tareas
 // @Description Devuelve todas las tareas
 // @Tags task
-// @Produce json
-// @Success 200 {array} taskstore.Task
-// @Failure 500 {string} string
+// @Produce json,application/x-ndjson,text/csv,application/msgpack,application/cbor
+// @Success 200 {array} server.TaskV1
+// @Failure 500 {object} problem.Problem
+// @Failure 406 {object} problem.Problem
 // @Router /task/ [get]
 // @Security BasicAuth
 func (ts *TaskServer) GetAllTasksHandler(w http.ResponseWriter, r *http.Request) {
 
-	log.Printf("handling task get all at %s\n", r.URL.Path)
-	tasks, err := ts.store.GetAllTasks()
-	js, err := json.Marshal(tasks)
+	logging.FromContext(r.Context()).Debug("handling task get all")
+	tasks, err := ts.store.GetAllTasks(r.Context())
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		problem.Write(w, r, err)
 		return
 	}
-	w.Header().Set("Content-Type", "application/json")
-	_, _ = w.Write(js)
+	render(w, r, newTasksV1(tasks))
 }
 
 // DeleteAllTasksHandler godoc
@@ -178,15 +156,15 @@ func (ts *TaskServer) GetAllTasksHandler(w http.ResponseWriter, r *http.Request)
 // @Description Borra todas las tareas
 // @Tags task
 // @Success 204
-// @Failure 500 {string} string
+// @Failure 500 {object} problem.Problem
 // @Router /task/ [delete]
 // @Security BasicAuth
 func (ts *TaskServer) DeleteAllTasksHandler(w http.ResponseWriter, r *http.Request) {
-	log.Printf("handling task delete all at %s\n", r.URL.Path)
+	logging.FromContext(r.Context()).Debug("handling task delete all")
 
-	err := ts.store.DeleteAllTasks()
+	err := ts.store.DeleteAllTasks(r.Context())
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		problem.Write(w, r, err)
 		return
 	}
 }
@@ -195,50 +173,45 @@ func (ts *TaskServer) DeleteAllTasksHandler(w http.ResponseWriter, r *http.Reque
 // @Summary Obtener tareas por tag
 // @Description Devuelve tareas que contienen un tag
 // @Tags task
-// @Produce json
+// @Produce json,application/x-ndjson,text/csv,application/msgpack,application/cbor
 // @Param tag path string true "Tag"
-// @Success 200 {array} taskstore.Task
-// @Failure 404 {string} string
+// @Success 200 {array} server.TaskV1
+// @Failure 500 {object} problem.Problem
+// @Failure 406 {object} problem.Problem
 // @Router /tag/{tag}/ [get]
 func (ts *TaskServer) TagHandler(w http.ResponseWriter, r *http.Request) {
-	log.Printf("handling task tag at %s\n", r.URL.Path)
+	logging.FromContext(r.Context()).Debug("handling task tag")
 
 	tag := r.PathValue("tag")
 
-	tasks, err := ts.store.GetTasksByTag(tag)
+	tasks, err := ts.store.GetTasksByTag(r.Context(), tag)
 
 	if err != nil {
-		_, _ = w.Write([]byte("Not found Tasks by Tag"))
+		problem.Write(w, r, err)
 		return
 	}
 
-	js, err := json.Marshal(tasks)
-
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	_, _ = w.Write(js)
+	render(w, r, newTasksV1(tasks))
 }
 
 // DueHandler godoc
 // @Summary Obtener tareas por fecha
 // @Description Devuelve tareas por fecha límite
 // @Tags task
-// @Produce json
+// @Produce json,application/x-ndjson,text/csv,application/msgpack,application/cbor
 // @Param year path int true "Año"
 // @Param month path int true "Mes"
 // @Param day path int true "Día"
-// @Success 200 {array} taskstore.Task
-// @Failure 400 {string} string
+// @Success 200 {array} server.TaskV1
+// @Failure 400 {object} problem.Problem
+// @Failure 406 {object} problem.Problem
 // @Router /due/{year}/{month}/{day}/ [get]
 func (ts *TaskServer) DueHandler(w http.ResponseWriter, req *http.Request) {
-	log.Printf("handling tasks by due at %s\n", req.URL.Path)
+	logging.FromContext(req.Context()).Debug("handling tasks by due")
 
 	badRequestError := func() {
-		http.Error(w, fmt.Sprintf("expect /due/<year>/<month>/<day>, got %v", req.URL.Path), http.StatusBadRequest)
+		problem.Write(w, req, problem.New(http.StatusBadRequest, "invalid_parameter",
+			fmt.Sprintf("expect /due/<year>/<month>/<day>, got %v", req.URL.Path)))
 	}
 
 	year, errYear := strconv.Atoi(req.PathValue("year"))
@@ -249,13 +222,6 @@ func (ts *TaskServer) DueHandler(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	tasks, _ := ts.store.GetTasksByDue(year, time.Month(month), day)
-	js, err := json.Marshal(tasks)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	_, _ = w.Write(js)
+	tasks, _ := ts.store.GetTasksByDue(req.Context(), year, time.Month(month), day)
+	render(w, req, newTasksV1(tasks))
 }
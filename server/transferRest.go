@@ -0,0 +1,154 @@
+package server
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+	"restServer/logging"
+	"restServer/problem"
+	"restServer/taskio"
+	"restServer/taskstore"
+	"strconv"
+)
+
+// Tamaño maximo del cuerpo de una importacion
+const maxImportBytes = 32 << 20
+
+// ExportHandler godoc
+// @Summary Exportar tareas
+// @Description Descarga todas las tareas en JSON, NDJSON o CSV (columnas id,text,tags,due,project,attachments)
+// @Tags transfer
+// @Produce json
+// @Produce application/x-ndjson
+// @Produce text/csv
+// @Param format query string false "json (por defecto), ndjson o csv; sin format se elige por Accept"
+// @Success 200 {array} server.TaskV1
+// @Failure 400 {object} problem.Problem
+// @Failure 406 {object} problem.Problem
+// @Router /export [get]
+func (ts *TaskServer) ExportHandler(w http.ResponseWriter, r *http.Request) {
+	logging.FromContext(r.Context()).Debug("handling export")
+	ts.export(w, r, taskLayoutV1)
+}
+
+// ExportV2Handler godoc
+// @Summary Exportar tareas (v2)
+// @Description Como /export, con cada tarea en JSON y NDJSON igual que en GET /v2/tasks (camelCase)
+// @Tags v2
+// @Produce json
+// @Produce application/x-ndjson
+// @Produce text/csv
+// @Param format query string false "json (por defecto), ndjson o csv; sin format se elige por Accept"
+// @Success 200 {array} server.TaskV2
+// @Failure 400 {object} problem.Problem
+// @Failure 406 {object} problem.Problem
+// @Router /v2/export [get]
+func (ts *TaskServer) ExportV2Handler(w http.ResponseWriter, r *http.Request) {
+	logging.FromContext(r.Context()).Debug("handling v2 export")
+	ts.export(w, r, taskLayoutV2)
+}
+
+func (ts *TaskServer) export(w http.ResponseWriter, r *http.Request, layout taskio.Layout) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		// Sin format se usa el Accept, el export solo tiene formatos de texto
+		format = taskio.FormatFromContentType(responseType(r))
+		if format == "" {
+			problem.Write(w, r, errNotAcceptable)
+			return
+		}
+	}
+	if format != taskio.FormatJSON && format != taskio.FormatNDJSON && format != taskio.FormatCSV {
+		problem.Write(w, r, problem.New(http.StatusBadRequest, "invalid_parameter", taskio.ErrUnknownFormat.Error()))
+		return
+	}
+
+	w.Header().Set("Content-Type", taskio.ContentType(format))
+	w.Header().Set("Content-Disposition", `attachment; filename="tasks.`+format+`"`)
+	if err := taskio.Export(r.Context(), ts.store, w, format, layout); err != nil {
+		// Las cabeceras ya se enviaron, solo queda registrarlo
+		logging.FromContext(r.Context()).Error("export failed", "err", err)
+	}
+}
+
+// ImportHandler godoc
+// @Summary Importar tareas
+// @Description Importa tareas en JSON, NDJSON o CSV (formato por query o Content-Type). Valida todo antes de importar: si hay errores no se importa nada y se responde 422 (problem+json) con el informe en el miembro report.
+// @Tags transfer
+// @Accept json
+// @Accept text/csv
+// @Produce json
+// @Param format query string false "json, ndjson o csv"
+// @Param mode query string false "reassign (por defecto) asigna Ids nuevos, preserve conserva los Ids"
+// @Param dryRun query bool false "solo validar"
+// @Success 200 {object} taskstore.ImportReport
+// @Failure 400 {object} problem.Problem
+// @Failure 413 {object} problem.Problem
+// @Failure 422 {object} problem.Problem
+// @Router /import [post]
+func (ts *TaskServer) ImportHandler(w http.ResponseWriter, r *http.Request) {
+	logging.FromContext(r.Context()).Debug("handling import")
+	ts.importTasks(w, r, taskLayoutV1)
+}
+
+// ImportV2Handler godoc
+// @Summary Importar tareas (v2)
+// @Description Como /import, con cada tarea en JSON y NDJSON igual que en GET /v2/tasks (camelCase); los punteros de los errores usan esos nombres
+// @Tags v2
+// @Accept json
+// @Accept text/csv
+// @Produce json
+// @Param format query string false "json, ndjson o csv"
+// @Param mode query string false "reassign (por defecto) asigna Ids nuevos, preserve conserva los Ids"
+// @Param dryRun query bool false "solo validar"
+// @Success 200 {object} taskstore.ImportReport
+// @Failure 400 {object} problem.Problem
+// @Failure 413 {object} problem.Problem
+// @Failure 422 {object} problem.Problem
+// @Router /v2/import [post]
+func (ts *TaskServer) ImportV2Handler(w http.ResponseWriter, r *http.Request) {
+	logging.FromContext(r.Context()).Debug("handling v2 import")
+	ts.importTasks(w, r, taskLayoutV2)
+}
+
+func (ts *TaskServer) importTasks(w http.ResponseWriter, r *http.Request, layout taskio.Layout) {
+	query := r.URL.Query()
+
+	format := query.Get("format")
+	if format == "" {
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		format = taskio.FormatFromContentType(mediaType)
+	}
+
+	var preserveIDs bool
+	switch query.Get("mode") {
+	case "", "reassign":
+	case "preserve":
+		preserveIDs = true
+	default:
+		problem.Write(w, r, problem.New(http.StatusBadRequest, "invalid_parameter", "mode must be preserve or reassign"))
+		return
+	}
+
+	dryRun := false
+	if raw := query.Get("dryRun"); raw != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(raw); err != nil {
+			problem.Write(w, r, problem.New(http.StatusBadRequest, "invalid_parameter", "dryRun must be a boolean"))
+			return
+		}
+	}
+
+	body := http.MaxBytesReader(w, r.Body, maxImportBytes)
+	report, err := taskio.Import(r.Context(), ts.store, body, format, layout, preserveIDs, dryRun)
+	switch {
+	case errors.Is(err, taskstore.ErrImportInvalid):
+		problem.WriteWith(w, r, err, map[string]any{"report": report})
+	case errors.Is(err, taskstore.ErrValidation):
+		problem.Write(w, r, err)
+	case err != nil:
+		problem.Write(w, r, bodyError(err, "invalid_body"))
+	default:
+		render(w, r, report)
+	}
+}
@@ -0,0 +1,130 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"restServer/taskstore"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestImportHandler(t *testing.T) {
+	const valid = `[{"Text":"imported","Due":"2026-01-02T15:04:05Z"}]`
+	const invalid = `[{"Text":"","Due":"2026-01-02T15:04:05Z"}]`
+
+	tests := []struct {
+		name       string
+		query      string
+		body       string
+		wantStatus int
+		wantCode   string
+		wantTasks  int
+	}{
+		{"imports", "?format=json", valid, http.StatusOK, "", 1},
+		{"dry run", "?format=json&dryRun=true", valid, http.StatusOK, "", 0},
+		{"invalid record", "?format=json", invalid, http.StatusUnprocessableEntity, "import_invalid", 0},
+		{"malformed body", "?format=json", `{"not":"a list"`, http.StatusBadRequest, "invalid_body", 0},
+		{"too large", "?format=json", "[" + strings.Repeat(" ", maxImportBytes) + "]", http.StatusRequestEntityTooLarge, "body_too_large", 0},
+		{"unknown mode", "?mode=merge", valid, http.StatusBadRequest, "invalid_parameter", 0},
+		{"bad dryRun", "?dryRun=maybe", valid, http.StatusBadRequest, "invalid_parameter", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := newTestServer()
+			rec := call(ts.ImportHandler, "POST", "/import"+tt.query, tt.body)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status %d, want %d: %.200s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if tt.wantCode != "" && problemCode(t, rec) != tt.wantCode {
+				t.Errorf("code = %q, want %q", problemCode(t, rec), tt.wantCode)
+			}
+			if tasks, _ := ts.store.GetAllTasks(context.Background()); len(tasks) != tt.wantTasks {
+				t.Errorf("store has %d tasks, want %d", len(tasks), tt.wantTasks)
+			}
+		})
+	}
+}
+
+func TestExportHandler(t *testing.T) {
+	ts := newTestServer()
+	ts.store.CreateTask(context.Background(), "exported", []string{"a", "b"}, time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC), nil, "")
+
+	v1 := NegotiateOrJSON(http.HandlerFunc(ts.ExportHandler))
+	v2 := Negotiate(http.HandlerFunc(ts.ExportV2Handler))
+
+	tests := []struct {
+		name       string
+		handler    http.Handler
+		query      string
+		accept     string
+		wantStatus int
+		wantType   string
+		wantBody   string
+	}{
+		{"csv", v1, "?format=csv", "", http.StatusOK, "text/csv", "0,exported,a;b,2026-01-02T15:04:05Z"},
+		{"ndjson by accept", v1, "", "application/x-ndjson", http.StatusOK, "application/x-ndjson", `"Text":"exported"`},
+		{"unknown format", v1, "?format=xml", "", http.StatusBadRequest, "application/problem+json", "invalid_parameter"},
+		{"v1 falls back to json", v1, "", "image/png", http.StatusOK, "application/json", `"Text":"exported"`},
+		{"v2 not acceptable", v2, "", "image/png", http.StatusNotAcceptable, "application/problem+json", "not_acceptable"},
+		{"v2 is camelCase", v2, "", "", http.StatusOK, "application/json", `"text":"exported"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/export"+tt.query, nil)
+			if tt.accept != "" {
+				r.Header.Set("Accept", tt.accept)
+			}
+			rec := httptest.NewRecorder()
+			tt.handler.ServeHTTP(rec, r)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if got := rec.Header().Get("Content-Type"); !strings.HasPrefix(got, tt.wantType) {
+				t.Errorf("Content-Type = %q, want %q", got, tt.wantType)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("body %q does not contain %q", rec.Body, tt.wantBody)
+			}
+		})
+	}
+}
+
+// El export y el import v1 usan TaskV1: cambiar las etiquetas JSON de
+// taskstore.Task (el archivo de datos) no cambia su formato
+func TestTaskLayoutV1(t *testing.T) {
+	project := "3"
+	task := taskstore.Task{
+		ID:          "7",
+		Text:        "exported",
+		Tags:        []string{"a"},
+		Due:         time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC),
+		Attachments: []*taskstore.Attachment{{Name: "a.txt", Date: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), Contents: "x"}},
+		ProjectID:   &project,
+	}
+	const want = `{"ID":"7","Text":"exported","Tags":["a"],"Due":"2026-01-02T15:04:05Z",` +
+		`"Attachments":[{"Name":"a.txt","Date":"2026-01-01T00:00:00Z","Contents":"x"}],"ProjectId":"3"}`
+
+	record := taskLayoutV1.Marshal(task)
+	if _, ok := record.(TaskV1); !ok {
+		t.Fatalf("Marshal() = %T, want TaskV1", record)
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != want {
+		t.Errorf("record = %s, want %s", data, want)
+	}
+
+	got, err := taskLayoutV1.Unmarshal([]byte(want))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, task) {
+		t.Errorf("Unmarshal() = %+v, want %+v", got, task)
+	}
+}
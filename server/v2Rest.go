@@ -0,0 +1,484 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"restServer/logging"
+	"restServer/problem"
+	"restServer/taskstore"
+	"strconv"
+	"time"
+)
+
+// Prefijo de las rutas de la version 2
+const v2Prefix = "/v2"
+
+// Responde 201 con Location apuntando al recurso creado
+func renderCreated(w http.ResponseWriter, r *http.Request, location string, v any) {
+	w.Header().Set("Location", location)
+	renderStatus(w, r, http.StatusCreated, v)
+}
+
+// Pagina [offset, offset+limit) de una lista, limit 0 = hasta el final
+func pageOf[T any](items []T, offset, limit int) []T {
+	start := min(offset, len(items))
+	end := len(items)
+	if limit > 0 {
+		end = min(end, start+limit)
+	}
+	return items[start:end]
+}
+
+//-------------------------------------------- Controladores v2 de tareas ----------------------------------------//
+
+// ListTasksV2Handler godoc
+// @Summary Listar tareas (v2)
+// @Description Devuelve las tareas ordenadas por Id, filtradas por tag, fecha limite y proyecto, paginadas; el total va en la cabecera X-Total-Count
+// @Tags v2
+// @Produce json,application/x-ndjson,text/csv,application/msgpack,application/cbor
+// @Param tag query string false "Tag"
+// @Param due query string false "Fecha limite (2006-01-02)"
+// @Param projectId query string false "ID del proyecto"
+// @Param offset query int false "Desplazamiento"
+// @Param limit query int false "Cantidad maxima"
+// @Success 200 {array} server.TaskV2
+// @Failure 400 {object} problem.Problem
+// @Failure 406 {object} problem.Problem
+// @Router /v2/tasks [get]
+func (ts *TaskServer) ListTasksV2Handler(w http.ResponseWriter, r *http.Request) {
+	logging.FromContext(r.Context()).Debug("handling v2 task list")
+
+	offset, limit, err := pagination(r)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	query := r.URL.Query()
+	filter := taskstore.TaskFilter{Tag: query.Get("tag"), ProjectID: query.Get("projectId")}
+	if raw := query.Get("due"); raw != "" {
+		due, err := time.Parse(time.DateOnly, raw)
+		if err != nil {
+			problem.Write(w, r, problem.New(http.StatusBadRequest, "invalid_parameter", "due must be a date like 2006-01-02"))
+			return
+		}
+		filter.Due = &due
+	}
+
+	tasks, err := ts.store.ListTasks(r.Context(), filter)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	w.Header().Set("X-Total-Count", strconv.Itoa(len(tasks)))
+	render(w, r, newTasksV2(pageOf(tasks, offset, limit)))
+}
+
+// CreateTaskV2Handler godoc
+// @Summary Crear una tarea (v2)
+// @Description Crea una tarea y la devuelve, con su URL en la cabecera Location
+// @Tags v2
+// @Accept json
+// @Produce json
+// @Param task body server.requestTaskV2 true "Nueva tarea"
+// @Success 201 {object} server.TaskV2
+// @Failure 400 {object} problem.Problem
+// @Failure 415 {object} problem.Problem
+// @Failure 422 {object} problem.Problem
+// @Router /v2/tasks [post]
+func (ts *TaskServer) CreateTaskV2Handler(w http.ResponseWriter, r *http.Request) {
+	logging.FromContext(r.Context()).Debug("handling v2 task create")
+
+	var req requestTaskV2
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	task, err := req.validate()
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	id, err := ts.store.CreateTask(r.Context(), task.Text, task.Tags, task.Due, task.Attachments, req.ProjectID)
+	if errors.Is(err, taskstore.ErrProjectNotFound) {
+		err = fmt.Errorf("%w: %w", taskstore.ErrValidation, err)
+	}
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	created, err := ts.store.GetTask(r.Context(), id)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	renderCreated(w, r, v2Prefix+"/tasks/"+id, newTaskV2(created))
+}
+
+// GetTaskV2Handler godoc
+// @Summary Obtener una tarea (v2)
+// @Description Obtiene una tarea por ID
+// @Tags v2
+// @Produce json
+// @Param id path int true "ID de la tarea"
+// @Success 200 {object} server.TaskV2
+// @Failure 404 {object} problem.Problem
+// @Router /v2/tasks/{id} [get]
+func (ts *TaskServer) GetTaskV2Handler(w http.ResponseWriter, r *http.Request) {
+	logging.FromContext(r.Context()).Debug("handling v2 task get")
+
+	task, err := ts.store.GetTask(r.Context(), r.PathValue("id"))
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	render(w, r, newTaskV2(task))
+}
+
+// DeleteAllTasksV2Handler godoc
+// @Summary Eliminar todas las tareas (v2)
+// @Description Borra todas las tareas
+// @Tags v2
+// @Success 204
+// @Failure 500 {object} problem.Problem
+// @Router /v2/tasks [delete]
+func (ts *TaskServer) DeleteAllTasksV2Handler(w http.ResponseWriter, r *http.Request) {
+	logging.FromContext(r.Context()).Debug("handling v2 task delete all")
+
+	if err := ts.store.DeleteAllTasks(r.Context()); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+//-------------------------------------------- Controladores v2 de comentarios y recordatorios ----------------------------------------//
+
+// GetCommentsV2Handler godoc
+// @Summary Comentarios de una tarea (v2)
+// @Description Devuelve los comentarios de una tarea en orden de creacion, el total va en la cabecera X-Total-Count
+// @Tags v2
+// @Produce json
+// @Param id path int true "ID de la tarea"
+// @Param offset query int false "Desplazamiento"
+// @Param limit query int false "Cantidad maxima"
+// @Success 200 {array} server.CommentV2
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Router /v2/tasks/{id}/comments [get]
+func (ts *TaskServer) GetCommentsV2Handler(w http.ResponseWriter, r *http.Request) {
+	logging.FromContext(r.Context()).Debug("handling v2 comments get")
+
+	offset, limit, err := pagination(r)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	comments, total, err := ts.store.GetComments(r.Context(), r.PathValue("id"), offset, limit)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	out := make([]CommentV2, 0, len(comments))
+	for _, comment := range comments {
+		out = append(out, newCommentV2(comment))
+	}
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	render(w, r, out)
+}
+
+// CreateCommentV2Handler godoc
+// @Summary Comentar una tarea (v2)
+// @Description Agrega un comentario a una tarea y lo devuelve, con su URL en la cabecera Location
+// @Tags v2
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de la tarea"
+// @Param comment body object true "Autor y cuerpo del comentario"
+// @Success 201 {object} server.CommentV2
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 415 {object} problem.Problem
+// @Failure 422 {object} problem.Problem
+// @Router /v2/tasks/{id}/comments [post]
+func (ts *TaskServer) CreateCommentV2Handler(w http.ResponseWriter, r *http.Request) {
+	logging.FromContext(r.Context()).Debug("handling v2 comment create")
+
+	var req struct {
+		Author string `json:"author"`
+		Body   string `json:"body"`
+	}
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if req.Author == "" || req.Body == "" {
+		problem.Write(w, r, fmt.Errorf("%w: author and body are required", taskstore.ErrValidation))
+		return
+	}
+
+	comment, err := ts.store.AddComment(r.Context(), r.PathValue("id"), req.Author, req.Body)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	renderCreated(w, r, v2Prefix+"/tasks/"+comment.TaskID+"/comments/"+comment.ID, newCommentV2(comment))
+}
+
+// GetCommentV2Handler godoc
+// @Summary Obtener un comentario (v2)
+// @Description Obtiene un comentario de una tarea
+// @Tags v2
+// @Produce json
+// @Param id path int true "ID de la tarea"
+// @Param commentId path int true "ID del comentario"
+// @Success 200 {object} server.CommentV2
+// @Failure 404 {object} problem.Problem
+// @Router /v2/tasks/{id}/comments/{commentId} [get]
+func (ts *TaskServer) GetCommentV2Handler(w http.ResponseWriter, r *http.Request) {
+	logging.FromContext(r.Context()).Debug("handling v2 comment get")
+
+	comment, err := ts.taskComment(r)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	render(w, r, newCommentV2(comment))
+}
+
+// UpdateCommentV2Handler godoc
+// @Summary Editar un comentario (v2)
+// @Description Reemplaza el cuerpo de un comentario
+// @Tags v2
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de la tarea"
+// @Param commentId path int true "ID del comentario"
+// @Param comment body object true "Nuevo cuerpo"
+// @Success 200 {object} server.CommentV2
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 415 {object} problem.Problem
+// @Failure 422 {object} problem.Problem
+// @Router /v2/tasks/{id}/comments/{commentId} [put]
+func (ts *TaskServer) UpdateCommentV2Handler(w http.ResponseWriter, r *http.Request) {
+	logging.FromContext(r.Context()).Debug("handling v2 comment update")
+
+	var req struct {
+		Body string `json:"body"`
+	}
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	if req.Body == "" {
+		problem.Write(w, r, fmt.Errorf("%w: body is required", taskstore.ErrValidation))
+		return
+	}
+
+	if _, err := ts.taskComment(r); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	comment, err := ts.store.UpdateComment(r.Context(), r.PathValue("commentId"), req.Body)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	render(w, r, newCommentV2(comment))
+}
+
+// GetRemindersV2Handler godoc
+// @Summary Recordatorios de una tarea (v2)
+// @Description Devuelve la configuracion de recordatorios de una tarea (vacia si usa los offsets por defecto)
+// @Tags v2
+// @Produce json
+// @Param id path int true "ID de la tarea"
+// @Success 200 {object} server.RemindersV2
+// @Failure 404 {object} problem.Problem
+// @Router /v2/tasks/{id}/reminders [get]
+func (ts *TaskServer) GetRemindersV2Handler(w http.ResponseWriter, r *http.Request) {
+	logging.FromContext(r.Context()).Debug("handling v2 reminders get")
+
+	config, _, err := ts.store.GetReminders(r.Context(), r.PathValue("id"))
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	render(w, r, newRemindersV2(config))
+}
+
+// SetRemindersV2Handler godoc
+// @Summary Configurar recordatorios (v2)
+// @Description Reemplaza los recordatorios de una tarea. Una lista de offsets vacia vuelve a los valores por defecto.
+// @Tags v2
+// @Accept json
+// @Produce json
+// @Param id path int true "ID de la tarea"
+// @Param reminders body server.RemindersV2 true "Offsets y canales"
+// @Success 200 {object} server.RemindersV2
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 415 {object} problem.Problem
+// @Failure 422 {object} problem.Problem
+// @Router /v2/tasks/{id}/reminders [put]
+func (ts *TaskServer) SetRemindersV2Handler(w http.ResponseWriter, r *http.Request) {
+	logging.FromContext(r.Context()).Debug("handling v2 reminders set")
+
+	var req RemindersV2
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	config, err := ts.store.SetReminders(r.Context(), r.PathValue("id"), req.Offsets, req.Channels)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	render(w, r, newRemindersV2(config))
+}
+
+//-------------------------------------------- Controladores v2 de proyectos ----------------------------------------//
+
+// GetAllProjectsV2Handler godoc
+// @Summary Listar proyectos (v2)
+// @Description Devuelve los proyectos paginados, el total va en la cabecera X-Total-Count
+// @Tags v2
+// @Produce json
+// @Param offset query int false "Desplazamiento"
+// @Param limit query int false "Cantidad maxima"
+// @Success 200 {array} server.ProjectV2
+// @Failure 400 {object} problem.Problem
+// @Router /v2/projects [get]
+func (ts *TaskServer) GetAllProjectsV2Handler(w http.ResponseWriter, r *http.Request) {
+	logging.FromContext(r.Context()).Debug("handling v2 project get all")
+
+	offset, limit, err := pagination(r)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	projects, total, err := ts.store.GetAllProjects(r.Context(), offset, limit)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	out := make([]ProjectV2, 0, len(projects))
+	for _, project := range projects {
+		out = append(out, newProjectV2(project))
+	}
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	render(w, r, out)
+}
+
+// CreateProjectV2Handler godoc
+// @Summary Crear un proyecto (v2)
+// @Description Crea un proyecto y lo devuelve, con su URL en la cabecera Location
+// @Tags v2
+// @Accept json
+// @Produce json
+// @Param project body object true "Nuevo proyecto"
+// @Success 201 {object} server.ProjectV2
+// @Failure 400 {object} problem.Problem
+// @Failure 415 {object} problem.Problem
+// @Failure 422 {object} problem.Problem
+// @Router /v2/projects [post]
+func (ts *TaskServer) CreateProjectV2Handler(w http.ResponseWriter, r *http.Request) {
+	logging.FromContext(r.Context()).Debug("handling v2 project create")
+
+	req, ok := decodeProject(w, r)
+	if !ok {
+		return
+	}
+
+	id := ts.store.CreateProject(r.Context(), req.Name, req.Description)
+	project, err := ts.store.GetProject(r.Context(), id)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	renderCreated(w, r, v2Prefix+"/projects/"+id, newProjectV2(project))
+}
+
+// GetProjectV2Handler godoc
+// @Summary Obtener un proyecto (v2)
+// @Description Obtiene un proyecto por ID
+// @Tags v2
+// @Produce json
+// @Param id path int true "ID del proyecto"
+// @Success 200 {object} server.ProjectV2
+// @Failure 404 {object} problem.Problem
+// @Router /v2/projects/{id} [get]
+func (ts *TaskServer) GetProjectV2Handler(w http.ResponseWriter, r *http.Request) {
+	logging.FromContext(r.Context()).Debug("handling v2 project get")
+
+	project, err := ts.store.GetProject(r.Context(), r.PathValue("id"))
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	render(w, r, newProjectV2(project))
+}
+
+// UpdateProjectV2Handler godoc
+// @Summary Actualizar un proyecto (v2)
+// @Description Reemplaza nombre y descripcion de un proyecto
+// @Tags v2
+// @Accept json
+// @Produce json
+// @Param id path int true "ID del proyecto"
+// @Param project body object true "Proyecto"
+// @Success 200 {object} server.ProjectV2
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 415 {object} problem.Problem
+// @Failure 422 {object} problem.Problem
+// @Router /v2/projects/{id} [put]
+func (ts *TaskServer) UpdateProjectV2Handler(w http.ResponseWriter, r *http.Request) {
+	logging.FromContext(r.Context()).Debug("handling v2 project update")
+
+	req, ok := decodeProject(w, r)
+	if !ok {
+		return
+	}
+
+	project, err := ts.store.UpdateProject(r.Context(), r.PathValue("id"), req.Name, req.Description)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	render(w, r, newProjectV2(project))
+}
+
+// GetProjectTasksV2Handler godoc
+// @Summary Tareas de un proyecto (v2)
+// @Description Devuelve las tareas de un proyecto paginadas, el total va en la cabecera X-Total-Count
+// @Tags v2
+// @Produce json,application/x-ndjson,text/csv,application/msgpack,application/cbor
+// @Param id path int true "ID del proyecto"
+// @Param offset query int false "Desplazamiento"
+// @Param limit query int false "Cantidad maxima"
+// @Success 200 {array} server.TaskV2
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 406 {object} problem.Problem
+// @Router /v2/projects/{id}/tasks [get]
+func (ts *TaskServer) GetProjectTasksV2Handler(w http.ResponseWriter, r *http.Request) {
+	logging.FromContext(r.Context()).Debug("handling v2 project tasks")
+
+	offset, limit, err := pagination(r)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	tasks, total, err := ts.store.GetTasksByProject(r.Context(), r.PathValue("id"), offset, limit)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	render(w, r, newTasksV2(tasks))
+}
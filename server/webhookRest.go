@@ -0,0 +1,186 @@
+package server
+
+import (
+	"net/http"
+	"restServer/logging"
+	"restServer/problem"
+	"restServer/webhook"
+)
+
+type requestWebhook struct {
+	URL    string   `json:"url"`
+	Secret string   `json:"secret"`
+	Events []string `json:"events"`
+	Active *bool    `json:"active"`
+}
+
+//-------------------------------------------- Controladores de webhooks ----------------------------------------//
+
+// CreateWebhookHandler godoc
+// @Summary Crear un webhook
+// @Description Suscribe una URL a los eventos del store. Si no se envia secret se genera uno y se devuelve solo en esta respuesta.
+// @Tags webhook
+// @Accept json
+// @Produce json
+// @Param webhook body object true "url, events y secret opcional"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} problem.Problem
+// @Failure 415 {object} problem.Problem
+// @Failure 422 {object} problem.Problem
+// @Router /webhook/ [post]
+func (ts *TaskServer) CreateWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	logging.FromContext(r.Context()).Debug("handling webhook create")
+
+	var req requestWebhook
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	sub, err := ts.webhooks.Create(req.URL, req.Secret, req.Events)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+
+	type ResponseWebhook struct {
+		webhook.Subscription
+		Secret string `json:"secret"`
+	}
+	renderStatus(w, r, http.StatusCreated, ResponseWebhook{Subscription: sub, Secret: sub.Secret})
+}
+
+// GetWebhooksHandler godoc
+// @Summary Listar webhooks
+// @Description Devuelve todas las suscripciones (sin secretos)
+// @Tags webhook
+// @Produce json
+// @Success 200 {array} webhook.Subscription
+// @Router /webhook/ [get]
+func (ts *TaskServer) GetWebhooksHandler(w http.ResponseWriter, r *http.Request) {
+	logging.FromContext(r.Context()).Debug("handling webhook get all")
+
+	render(w, r, ts.webhooks.List())
+}
+
+// GetWebhookHandler godoc
+// @Summary Obtener un webhook
+// @Description Obtiene una suscripcion por ID
+// @Tags webhook
+// @Produce json
+// @Param id path int true "ID del webhook"
+// @Success 200 {object} webhook.Subscription
+// @Failure 404 {object} problem.Problem
+// @Router /webhook/{id}/ [get]
+func (ts *TaskServer) GetWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	logging.FromContext(r.Context()).Debug("handling webhook get")
+
+	sub, err := ts.webhooks.Get(r.PathValue("id"))
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	render(w, r, sub)
+}
+
+// UpdateWebhookHandler godoc
+// @Summary Actualizar un webhook
+// @Description Reemplaza url, eventos y estado. Un secret vacio conserva el actual.
+// @Tags webhook
+// @Accept json
+// @Produce json
+// @Param id path int true "ID del webhook"
+// @Param webhook body object true "url, events, active y secret opcional"
+// @Success 200 {object} webhook.Subscription
+// @Failure 400 {object} problem.Problem
+// @Failure 404 {object} problem.Problem
+// @Failure 415 {object} problem.Problem
+// @Failure 422 {object} problem.Problem
+// @Router /webhook/{id}/ [put]
+func (ts *TaskServer) UpdateWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	logging.FromContext(r.Context()).Debug("handling webhook update")
+
+	var req requestWebhook
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	active := true
+	if req.Active != nil {
+		active = *req.Active
+	}
+
+	sub, err := ts.webhooks.Update(r.PathValue("id"), req.URL, req.Secret, req.Events, active)
+	if err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	render(w, r, sub)
+}
+
+// DeleteWebhookHandler godoc
+// @Summary Eliminar un webhook
+// @Description Elimina una suscripcion
+// @Tags webhook
+// @Param id path int true "ID del webhook"
+// @Success 204
+// @Failure 404 {object} problem.Problem
+// @Router /webhook/{id}/ [delete]
+func (ts *TaskServer) DeleteWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	logging.FromContext(r.Context()).Debug("handling webhook delete")
+
+	if err := ts.webhooks.Delete(r.PathValue("id")); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// WebhookDeliveriesHandler godoc
+// @Summary Log de entregas
+// @Description Devuelve los ultimos intentos de entrega de un webhook, los mas recientes primero
+// @Tags webhook
+// @Produce json
+// @Param id path int true "ID del webhook"
+// @Success 200 {array} webhook.Delivery
+// @Failure 404 {object} problem.Problem
+// @Router /webhook/{id}/deliveries/ [get]
+func (ts *TaskServer) WebhookDeliveriesHandler(w http.ResponseWriter, r *http.Request) {
+	logging.FromContext(r.Context()).Debug("handling webhook deliveries")
+
+	id := r.PathValue("id")
+	if _, err := ts.webhooks.Get(id); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	render(w, r, ts.webhooks.Deliveries(id))
+}
+
+// DeadLettersHandler godoc
+// @Summary Entregas fallidas
+// @Description Devuelve las entregas que agotaron los reintentos
+// @Tags webhook
+// @Produce json
+// @Success 200 {array} webhook.DeadLetter
+// @Router /deadletter/ [get]
+func (ts *TaskServer) DeadLettersHandler(w http.ResponseWriter, r *http.Request) {
+	logging.FromContext(r.Context()).Debug("handling webhook dead letters")
+
+	render(w, r, ts.webhooks.DeadLetters())
+}
+
+// RetryDeadLetterHandler godoc
+// @Summary Reintentar entrega fallida
+// @Description Vuelve a encolar una entrega de la lista de dead letters
+// @Tags webhook
+// @Param id path int true "ID de la entrega"
+// @Success 202
+// @Failure 404 {object} problem.Problem
+// @Router /deadletter/{id}/retry/ [post]
+func (ts *TaskServer) RetryDeadLetterHandler(w http.ResponseWriter, r *http.Request) {
+	logging.FromContext(r.Context()).Debug("handling webhook dead letter retry")
+
+	if err := ts.webhooks.RetryDeadLetter(r.PathValue("id")); err != nil {
+		problem.Write(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
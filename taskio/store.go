@@ -0,0 +1,82 @@
+package taskio
+
+import (
+	"context"
+	"io"
+	"restServer/taskstore"
+	"restServer/validation"
+	"sort"
+)
+
+// Escribe todas las tareas del store ordenadas por Id en el formato pedido
+func Export(ctx context.Context, store *taskstore.TaskStore, w io.Writer, format string, layout Layout) error {
+	enc, err := NewEncoder(w, format, layout)
+	if err != nil {
+		return err
+	}
+
+	tasks, err := store.GetAllTasks(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, task := range tasks {
+		if err := enc.Encode(task); err != nil {
+			return err
+		}
+	}
+	return enc.Close()
+}
+
+// Lee las tareas en el formato pedido y las importa en el store. Los errores de
+// lectura y de validacion se reunen en el mismo informe, con la posicion de cada
+// registro en la entrada; si hay alguno no se importa nada.
+func Import(ctx context.Context, store *taskstore.TaskStore, r io.Reader, format string, layout Layout, preserveIDs, dryRun bool) (taskstore.ImportReport, error) {
+	records, readErrs, err := Decode(r, format, layout)
+	if err != nil {
+		return taskstore.ImportReport{}, err
+	}
+
+	// Cada tarea pasa por las mismas reglas que REST y GraphQL, con los punteros
+	// relativos al registro
+	total := len(records) + len(readErrs)
+	fields := layout.Fields
+	if format == FormatCSV {
+		fields = validation.FieldsV1
+	}
+	tasks := make([]taskstore.Task, 0, len(records))
+	for _, record := range records {
+		task := record.Task
+		input := validation.Task{Text: task.Text, Tags: task.Tags, Due: task.Due, Attachments: task.Attachments}
+		if errs := validation.DefaultTaskRules.Check(&input, fields); len(errs) > 0 {
+			readErrs = append(readErrs, RecordError{Index: record.Index, Error: errs.Error(), Errors: errs})
+		}
+		task.Text, task.Tags, task.Attachments = input.Text, input.Tags, input.Attachments
+		tasks = append(tasks, task)
+	}
+
+	// Con errores de lectura o de validacion solo se comprueba el resto contra el
+	// store para informarlo todo junto
+	report, err := store.ImportTasks(ctx, tasks, preserveIDs, dryRun || len(readErrs) > 0)
+	report.DryRun = dryRun
+	report.Total = total
+
+	for i := range report.Errors {
+		report.Errors[i].Index = records[report.Errors[i].Index].Index
+	}
+	for _, readErr := range readErrs {
+		importErr := taskstore.ImportError{Index: readErr.Index, Error: readErr.Error}
+		if len(readErr.Errors) > 0 {
+			importErr.Errors = readErr.Errors
+		}
+		report.Errors = append(report.Errors, importErr)
+	}
+	sort.SliceStable(report.Errors, func(i, j int) bool { return report.Errors[i].Index < report.Errors[j].Index })
+
+	if len(readErrs) > 0 {
+		report.Imported = 0
+		report.IDs = map[string]string{}
+		return report, taskstore.ErrImportInvalid
+	}
+	return report, err
+}
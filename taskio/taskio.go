@@ -0,0 +1,284 @@
+// Lectura y escritura de tareas en JSON, NDJSON y CSV para importar y exportar el store
+package taskio
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"restServer/taskstore"
+	"restServer/validation"
+	"strings"
+	"time"
+)
+
+const (
+	FormatJSON   = "json"
+	FormatNDJSON = "ndjson"
+	FormatCSV    = "csv"
+)
+
+var ErrUnknownFormat = taskstore.NewError(taskstore.ErrValidation, "format must be json, ndjson or csv")
+
+// Columnas del CSV. Los tags van separados por ";" y los adjuntos como JSON.
+var csvHeader = []string{"id", "text", "tags", "due", "project", "attachments"}
+
+// Tarea leida junto con su posicion en la entrada (0 = primer registro)
+type Record struct {
+	Index int
+	Task  taskstore.Task
+}
+
+// Error de lectura o de validacion de un registro
+type RecordError struct {
+	Index  int
+	Error  string
+	Errors validation.Errors // errores de cada campo si el registro no cumple las reglas
+}
+
+// Representacion de cada tarea en JSON y NDJSON. StoreLayout es la del archivo de
+// datos, que usan el CLI y las copias; cada version de la API pasa la suya para
+// no depender del almacenamiento. El CSV tiene sus propias columnas y es igual en
+// todas.
+type Layout struct {
+	Marshal   func(taskstore.Task) any
+	Unmarshal func(data []byte) (taskstore.Task, error)
+	Fields    validation.TaskFields // nombres de los campos en los errores de validacion
+}
+
+var StoreLayout = Layout{
+	Marshal: func(task taskstore.Task) any { return task },
+	Unmarshal: func(data []byte) (taskstore.Task, error) {
+		var task taskstore.Task
+		err := json.Unmarshal(data, &task)
+		return task, err
+	},
+	Fields: validation.FieldsPascal,
+}
+
+// Content-Type de cada formato
+func ContentType(format string) string {
+	switch format {
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	case FormatCSV:
+		return "text/csv"
+	default:
+		return "application/json"
+	}
+}
+
+// Formato a partir de un Content-Type, "" si no se reconoce
+func FormatFromContentType(mediaType string) string {
+	switch mediaType {
+	case "application/json":
+		return FormatJSON
+	case "application/x-ndjson", "application/ndjson":
+		return FormatNDJSON
+	case "text/csv":
+		return FormatCSV
+	}
+	return ""
+}
+
+//-------------------------------------------- Escritura ----------------------------------------//
+
+// Encoder escribe las tareas de una en una para poder hacer streaming
+type Encoder struct {
+	w      io.Writer
+	csv    *csv.Writer
+	format string
+	layout Layout
+	count  int
+}
+
+func NewEncoder(w io.Writer, format string, layout Layout) (*Encoder, error) {
+	enc := &Encoder{w: w, format: format, layout: layout}
+	switch format {
+	case FormatJSON:
+		if _, err := io.WriteString(w, "["); err != nil {
+			return nil, err
+		}
+	case FormatNDJSON:
+	case FormatCSV:
+		enc.csv = csv.NewWriter(w)
+		if err := enc.csv.Write(csvHeader); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, ErrUnknownFormat
+	}
+	return enc, nil
+}
+
+func (enc *Encoder) Encode(task taskstore.Task) error {
+	defer func() { enc.count++ }()
+
+	if enc.format == FormatCSV {
+		return enc.csv.Write(csvRow(task))
+	}
+
+	js, err := json.Marshal(enc.layout.Marshal(task))
+	if err != nil {
+		return err
+	}
+	switch {
+	case enc.format == FormatNDJSON:
+		js = append(js, '\n')
+	case enc.count > 0:
+		js = append([]byte(","), js...)
+	}
+	_, err = enc.w.Write(js)
+	return err
+}
+
+// Cierra el documento (el "]" en JSON, vaciar el buffer en CSV)
+func (enc *Encoder) Close() error {
+	switch enc.format {
+	case FormatJSON:
+		_, err := io.WriteString(enc.w, "]\n")
+		return err
+	case FormatCSV:
+		enc.csv.Flush()
+		return enc.csv.Error()
+	}
+	return nil
+}
+
+func csvRow(task taskstore.Task) []string {
+	project := ""
+	if task.ProjectID != nil {
+		project = *task.ProjectID
+	}
+	attachments := ""
+	if len(task.Attachments) > 0 {
+		js, _ := json.Marshal(task.Attachments)
+		attachments = string(js)
+	}
+	return []string{task.ID, task.Text, strings.Join(task.Tags, ";"), task.Due.Format(time.RFC3339), project, attachments}
+}
+
+//-------------------------------------------- Lectura ----------------------------------------//
+
+// Lee todas las tareas. Los registros que no se pueden interpretar se devuelven
+// como errores con su posicion, el resto se sigue leyendo. El error final solo
+// indica un documento ilegible.
+func Decode(r io.Reader, format string, layout Layout) ([]Record, []RecordError, error) {
+	switch format {
+	case FormatJSON:
+		return decodeJSON(r, layout)
+	case FormatNDJSON:
+		return decodeNDJSON(r, layout)
+	case FormatCSV:
+		return decodeCSV(r)
+	}
+	return nil, nil, ErrUnknownFormat
+}
+
+func decodeJSON(r io.Reader, layout Layout) ([]Record, []RecordError, error) {
+	var raw []json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, nil, err
+	}
+
+	records := make([]Record, 0, len(raw))
+	errs := make([]RecordError, 0)
+	for i, item := range raw {
+		task, err := layout.Unmarshal(item)
+		if err != nil {
+			errs = append(errs, RecordError{Index: i, Error: err.Error()})
+			continue
+		}
+		records = append(records, Record{Index: i, Task: task})
+	}
+	return records, errs, nil
+}
+
+func decodeNDJSON(r io.Reader, layout Layout) ([]Record, []RecordError, error) {
+	records := make([]Record, 0)
+	errs := make([]RecordError, 0)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	index := 0
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		task, err := layout.Unmarshal([]byte(line))
+		if err != nil {
+			errs = append(errs, RecordError{Index: index, Error: err.Error()})
+		} else {
+			records = append(records, Record{Index: index, Task: task})
+		}
+		index++
+	}
+	return records, errs, scanner.Err()
+}
+
+func decodeCSV(r io.Reader) ([]Record, []RecordError, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = len(csvHeader)
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, err
+	}
+	for i, name := range csvHeader {
+		if strings.TrimSpace(strings.ToLower(header[i])) != name {
+			return nil, nil, fmt.Errorf("csv header must be %s", strings.Join(csvHeader, ","))
+		}
+	}
+
+	records := make([]Record, 0)
+	errs := make([]RecordError, 0)
+	for index := 0; ; index++ {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) && errors.Is(parseErr.Err, csv.ErrFieldCount) {
+				errs = append(errs, RecordError{Index: index, Error: err.Error()})
+				continue
+			}
+			return nil, nil, err
+		}
+
+		task, err := csvTask(row)
+		if err != nil {
+			errs = append(errs, RecordError{Index: index, Error: err.Error()})
+			continue
+		}
+		records = append(records, Record{Index: index, Task: task})
+	}
+	return records, errs, nil
+}
+
+func csvTask(row []string) (taskstore.Task, error) {
+	task := taskstore.Task{ID: row[0], Text: row[1]}
+	if row[2] != "" {
+		task.Tags = strings.Split(row[2], ";")
+	}
+	if row[3] != "" {
+		due, err := time.Parse(time.RFC3339, row[3])
+		if err != nil {
+			return taskstore.Task{}, err
+		}
+		task.Due = due
+	}
+	if row[4] != "" {
+		project := row[4]
+		task.ProjectID = &project
+	}
+	if row[5] != "" {
+		if err := json.Unmarshal([]byte(row[5]), &task.Attachments); err != nil {
+			return taskstore.Task{}, fmt.Errorf("attachments: %w", err)
+		}
+	}
+	return task, nil
+}
@@ -0,0 +1,123 @@
+package taskio
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"restServer/taskstore"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Store con una tarea de cada tipo: con proyecto, tags y adjuntos, y una minima
+func newExportStore(t *testing.T) *taskstore.TaskStore {
+	t.Helper()
+	ctx := context.Background()
+	store := taskstore.New()
+	project := store.CreateProject(ctx, "home", "")
+	due := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+	attachments := []*taskstore.Attachment{{Name: "a.txt", Date: due, Contents: "hola, \"mundo\"\n"}}
+	if _, err := store.CreateTask(ctx, "first; with, commas", []string{"a", "b"}, due, attachments, project); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.CreateTask(ctx, "second", nil, due.Add(time.Hour), nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, format := range []string{FormatJSON, FormatNDJSON, FormatCSV} {
+		t.Run(format, func(t *testing.T) {
+			ctx := context.Background()
+			src := newExportStore(t)
+			var buf bytes.Buffer
+			if err := Export(ctx, src, &buf, format, StoreLayout); err != nil {
+				t.Fatal(err)
+			}
+
+			dst := taskstore.New()
+			dst.CreateProject(ctx, "home", "")
+			report, err := Import(ctx, dst, bytes.NewReader(buf.Bytes()), format, StoreLayout, true, false)
+			if err != nil {
+				t.Fatalf("Import() error = %v, report %+v", err, report)
+			}
+			if report.Imported != 2 || report.Total != 2 {
+				t.Errorf("report = %+v", report)
+			}
+
+			want, _ := src.GetAllTasks(ctx)
+			got, _ := dst.GetAllTasks(ctx)
+			if fmt.Sprint(tasksString(got)) != fmt.Sprint(tasksString(want)) {
+				t.Errorf("imported %v\nwant     %v", tasksString(got), tasksString(want))
+			}
+		})
+	}
+}
+
+func tasksString(tasks []taskstore.Task) []string {
+	out := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		project := ""
+		if task.ProjectID != nil {
+			project = *task.ProjectID
+		}
+		s := fmt.Sprintf("%s|%s|%v|%s|%s", task.ID, task.Text, task.Tags, task.Due.Format(time.RFC3339), project)
+		for _, a := range task.Attachments {
+			s += fmt.Sprintf("|%s|%s|%q", a.Name, a.Date.Format(time.RFC3339), a.Contents)
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+func TestImport(t *testing.T) {
+	tests := []struct {
+		name       string
+		format     string
+		input      string
+		preserve   bool
+		dryRun     bool
+		wantErr    error
+		wantErrIdx []int // Index de cada error del informe
+		wantTasks  int
+	}{
+		{"json", FormatJSON, `[{"Text":"a","Due":"2026-01-02T15:04:05Z"}]`, false, false, nil, nil, 1},
+		{"dry run", FormatJSON, `[{"Text":"a","Due":"2026-01-02T15:04:05Z"}]`, false, true, nil, nil, 0},
+		{"invalid records keep their position", FormatNDJSON,
+			"{\"Text\":\"a\",\"Due\":\"2026-01-02T15:04:05Z\"}\n\nnot json\n{\"Text\":\"\",\"Due\":\"2026-01-02T15:04:05Z\"}\n",
+			false, false, taskstore.ErrImportInvalid, []int{1, 2}, 0},
+		{"csv field count", FormatCSV, "id,text,tags,due,project,attachments\n0,a\n", false, false, taskstore.ErrImportInvalid, []int{0}, 0},
+		{"csv bad date", FormatCSV, "id,text,tags,due,project,attachments\n0,a,,tomorrow,,\n", false, false, taskstore.ErrImportInvalid, []int{0}, 0},
+		{"unknown project", FormatJSON, `[{"Text":"a","Due":"2026-01-02T15:04:05Z","ProjectID":"9"}]`, false, false, taskstore.ErrImportInvalid, []int{0}, 0},
+		{"unknown format", "xml", ``, false, false, ErrUnknownFormat, nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := taskstore.New()
+			report, err := Import(context.Background(), store, strings.NewReader(tt.input), tt.format, StoreLayout, tt.preserve, tt.dryRun)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Import() error = %v, want %v", err, tt.wantErr)
+			}
+			idx := make([]int, 0, len(report.Errors))
+			for _, e := range report.Errors {
+				idx = append(idx, e.Index)
+			}
+			if fmt.Sprint(idx) != fmt.Sprint(tt.wantErrIdx) {
+				t.Errorf("error indexes = %v, want %v (%+v)", idx, tt.wantErrIdx, report.Errors)
+			}
+			if tasks, _ := store.GetAllTasks(context.Background()); len(tasks) != tt.wantTasks {
+				t.Errorf("store has %d tasks, want %d", len(tasks), tt.wantTasks)
+			}
+		})
+	}
+}
+
+func TestDecodeCSVHeader(t *testing.T) {
+	_, _, err := Decode(strings.NewReader("id,title,tags,due,project,files\n"), FormatCSV, StoreLayout)
+	if err == nil || !strings.Contains(err.Error(), "csv header") {
+		t.Errorf("Decode() error = %v", err)
+	}
+}
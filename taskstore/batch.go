@@ -0,0 +1,150 @@
+package taskstore
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Operaciones soportadas en un batch (las tareas no tienen update)
+const (
+	BatchCreate = "create"
+	BatchDelete = "delete"
+)
+
+// Operacion de un batch: para create se usan los campos de la tarea, para delete el ID
+type BatchOp struct {
+	Op          string
+	ID          string
+	Text        string
+	Tags        []string
+	Due         time.Time
+	Attachments []*Attachment
+	ProjectID   string
+}
+
+// Resultado de cada operacion, ID es el de la tarea creada o eliminada
+type BatchResult struct {
+	Op    string
+	ID    string
+	Error error
+}
+
+// Ejecuta las operaciones en orden con un solo lock. Si atomic es true y alguna
+// falla se deshace todo y se devuelve ErrBatchAborted; si no, cada operacion se
+// aplica por separado y su error queda en el resultado.
+func (ts *TaskStore) Batch(ctx context.Context, ops []BatchOp, atomic bool) ([]BatchResult, error) {
+	_, span := startSpan(ctx, "Batch")
+	defer span.End()
+	ts.Lock()
+	defer ts.Unlock()
+
+	// En modo atomic se guarda solo lo que toca cada operacion para poderla deshacer;
+	// los eventos se retienen hasta confirmar
+	var undo []batchUndo
+	nextId := ts.nextId
+	events := make([]Event, 0, len(ops))
+	ts.pending = &events
+	defer func() { ts.pending = nil }()
+
+	results := make([]BatchResult, len(ops))
+	failed := false
+	for i, op := range ops {
+		var u batchUndo
+		if atomic {
+			u = ts.saveBatchOp(op)
+		}
+		results[i] = ts.applyBatchOp(op)
+		if results[i].Error != nil {
+			failed = true
+			if atomic {
+				break
+			}
+			continue
+		}
+		if atomic {
+			u.taskID = results[i].ID
+			undo = append(undo, u)
+		}
+	}
+
+	if atomic && failed {
+		for i := len(undo) - 1; i >= 0; i-- {
+			ts.undoBatchOp(undo[i])
+		}
+		ts.nextId = nextId
+
+		// Las operaciones que no fallaron quedan sin efecto
+		for i, op := range ops {
+			if results[i].Error == nil {
+				results[i] = BatchResult{Op: op.Op, ID: op.ID, Error: ErrBatchAborted}
+			}
+		}
+		return results, ErrBatchAborted
+	}
+
+	ts.pending = nil
+	for _, event := range events {
+		ts.publish(event)
+	}
+	return results, nil
+}
+
+// Requiere tener el lock
+func (ts *TaskStore) applyBatchOp(op BatchOp) BatchResult {
+	result := BatchResult{Op: op.Op, ID: op.ID}
+	switch op.Op {
+	case BatchCreate:
+		result.ID, result.Error = ts.createTask(op.Text, op.Tags, op.Due, op.Attachments, op.ProjectID)
+	case BatchDelete:
+		result.Error = ts.deleteTask(op.ID)
+	default:
+		result.Error = fmt.Errorf("%w: %q", ErrUnsupportedBatch, op.Op)
+	}
+	return result
+}
+
+// Entradas que toco una operacion del batch, para deshacerla
+type batchUndo struct {
+	taskID   string
+	task     *Task // la tarea eliminada, nil si la operacion la creo
+	reminder *ReminderConfig
+	comments []Comment
+}
+
+// Guarda lo que va a eliminar op, requiere tener el lock
+func (ts *TaskStore) saveBatchOp(op BatchOp) batchUndo {
+	var u batchUndo
+	if op.Op != BatchDelete {
+		return u
+	}
+	task, ok := ts.tasks[op.ID]
+	if !ok {
+		return u
+	}
+	u.task = &task
+	if config, ok := ts.reminders[op.ID]; ok {
+		u.reminder = &config
+	}
+	for _, comment := range ts.comments {
+		if comment.TaskID == op.ID {
+			u.comments = append(u.comments, comment)
+		}
+	}
+	return u
+}
+
+// Requiere tener el lock
+func (ts *TaskStore) undoBatchOp(u batchUndo) {
+	if u.task == nil {
+		delete(ts.tasks, u.taskID)
+		return
+	}
+	ts.tasks[u.taskID] = *u.task
+	if u.reminder != nil {
+		ts.reminders[u.taskID] = *u.reminder
+	}
+	for _, comment := range u.comments {
+		ts.comments[comment.ID] = comment
+	}
+}
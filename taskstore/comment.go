@@ -0,0 +1,120 @@
+package taskstore
+
+import (
+	"context"
+	"go.opentelemetry.io/otel/attribute"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Comentario de una tarea; sus tags json son los del archivo de datos
+type Comment struct {
+	ID        string    `json:"ID"`
+	TaskID    string    `json:"TaskId"`
+	Author    string    `json:"Author"`
+	Body      string    `json:"Body"`
+	CreatedAt time.Time `json:"CreatedAt"`
+	UpdatedAt time.Time `json:"UpdatedAt"`
+}
+
+// ------------------------------- Metodos de comentarios --------------------------------------------------//
+
+// Agrega un comentario a una tarea existente
+func (ts *TaskStore) AddComment(ctx context.Context, taskID, author, body string) (Comment, error) {
+	_, span := startSpan(ctx, "AddComment", attribute.String("task.id", taskID))
+	defer span.End()
+	ts.Lock()
+	defer ts.Unlock()
+
+	if _, ok := ts.tasks[taskID]; !ok {
+		return Comment{}, ErrTaskNotFound
+	}
+
+	now := time.Now().UTC()
+	comment := Comment{
+		ID:        strconv.Itoa(ts.nextCommentId),
+		TaskID:    taskID,
+		Author:    author,
+		Body:      body,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	ts.comments[comment.ID] = comment
+	ts.nextCommentId++
+	ts.emit(EventCommentCreated, comment)
+	return comment, nil
+}
+
+// O(1) obtenemos el comentario por Id
+func (ts *TaskStore) GetComment(ctx context.Context, id string) (Comment, error) {
+	_, span := startSpan(ctx, "GetComment", attribute.String("comment.id", id))
+	defer span.End()
+	ts.Lock()
+	defer ts.Unlock()
+
+	comment, ok := ts.comments[id]
+	if !ok {
+		return Comment{}, ErrCommentNotFound
+	}
+	return comment, nil
+}
+
+// Edita el cuerpo de un comentario y actualiza UpdatedAt
+func (ts *TaskStore) UpdateComment(ctx context.Context, id, body string) (Comment, error) {
+	_, span := startSpan(ctx, "UpdateComment", attribute.String("comment.id", id))
+	defer span.End()
+	ts.Lock()
+	defer ts.Unlock()
+
+	comment, ok := ts.comments[id]
+	if !ok {
+		return Comment{}, ErrCommentNotFound
+	}
+	comment.Body = body
+	comment.UpdatedAt = time.Now().UTC()
+	ts.comments[id] = comment
+	ts.emit(EventCommentUpdated, comment)
+	return comment, nil
+}
+
+// O(1) eliminamos el comentario por Id
+func (ts *TaskStore) DeleteComment(ctx context.Context, id string) error {
+	_, span := startSpan(ctx, "DeleteComment", attribute.String("comment.id", id))
+	defer span.End()
+	ts.Lock()
+	defer ts.Unlock()
+
+	comment, ok := ts.comments[id]
+	if !ok {
+		return ErrCommentNotFound
+	}
+	delete(ts.comments, id)
+	ts.emit(EventCommentDeleted, comment)
+	return nil
+}
+
+// Comentarios paginados de una tarea en orden de creacion, devuelve tambien el total
+func (ts *TaskStore) GetComments(ctx context.Context, taskID string, offset, limit int) ([]Comment, int, error) {
+	_, span := startSpan(ctx, "GetComments", attribute.String("task.id", taskID))
+	defer span.End()
+	ts.Lock()
+	defer ts.Unlock()
+
+	if _, ok := ts.tasks[taskID]; !ok {
+		return nil, 0, ErrTaskNotFound
+	}
+
+	comments := make([]Comment, 0)
+	for _, comment := range ts.comments {
+		if comment.TaskID == taskID {
+			comments = append(comments, comment)
+		}
+	}
+	sort.Slice(comments, func(i, j int) bool {
+		return LessId(comments[i].ID, comments[j].ID)
+	})
+
+	start, end := pageBounds(len(comments), offset, limit)
+	return comments[start:end], len(comments), nil
+}
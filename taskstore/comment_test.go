@@ -0,0 +1,91 @@
+package taskstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestComments(t *testing.T) {
+	ctx := context.Background()
+	store := New()
+	task, _ := store.CreateTask(ctx, "task", nil, time.Now(), nil, "")
+	other, _ := store.CreateTask(ctx, "other", nil, time.Now(), nil, "")
+
+	var events []string
+	store.Subscribe(func(e Event) { events = append(events, e.Type) })
+
+	first, err := store.AddComment(ctx, task, "ana", "first")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.TaskID != task || first.CreatedAt.IsZero() || !first.UpdatedAt.Equal(first.CreatedAt) {
+		t.Errorf("AddComment() = %+v", first)
+	}
+	store.AddComment(ctx, task, "bob", "second")
+	store.AddComment(ctx, other, "ana", "elsewhere")
+
+	if _, err := store.AddComment(ctx, "404", "ana", "x"); !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("AddComment on a missing task error = %v", err)
+	}
+
+	time.Sleep(time.Millisecond)
+	edited, err := store.UpdateComment(ctx, first.ID, "edited")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if edited.Body != "edited" || !edited.UpdatedAt.After(edited.CreatedAt) {
+		t.Errorf("UpdateComment() = %+v", edited)
+	}
+
+	comments, total, err := store.GetComments(ctx, task, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 2 || comments[0].Body != "edited" || comments[1].Body != "second" {
+		t.Errorf("GetComments() = %+v, %d", comments, total)
+	}
+	if page, total, _ := store.GetComments(ctx, task, 1, 1); total != 2 || len(page) != 1 || page[0].Author != "bob" {
+		t.Errorf("GetComments(1, 1) = %+v, %d", page, total)
+	}
+
+	if err := store.DeleteComment(ctx, first.ID); err != nil {
+		t.Fatal(err)
+	}
+	for _, err := range []error{
+		store.DeleteComment(ctx, first.ID),
+		func() error { _, err := store.UpdateComment(ctx, first.ID, "x"); return err }(),
+		func() error { _, err := store.GetComment(ctx, first.ID); return err }(),
+	} {
+		if !errors.Is(err, ErrCommentNotFound) {
+			t.Errorf("deleted comment error = %v, want %v", err, ErrCommentNotFound)
+		}
+	}
+
+	want := []string{EventCommentCreated, EventCommentCreated, EventCommentCreated, EventCommentUpdated, EventCommentDeleted}
+	if fmt.Sprint(events) != fmt.Sprint(want) {
+		t.Errorf("events = %v, want %v", events, want)
+	}
+}
+
+// Al eliminar una tarea se eliminan sus comentarios, los de otras tareas quedan
+func TestDeleteTaskRemovesComments(t *testing.T) {
+	ctx := context.Background()
+	store := New()
+	task, _ := store.CreateTask(ctx, "task", nil, time.Now(), nil, "")
+	other, _ := store.CreateTask(ctx, "other", nil, time.Now(), nil, "")
+	comment, _ := store.AddComment(ctx, task, "ana", "gone")
+	kept, _ := store.AddComment(ctx, other, "ana", "kept")
+
+	if err := store.DeleteTask(ctx, task); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.GetComment(ctx, comment.ID); !errors.Is(err, ErrCommentNotFound) {
+		t.Errorf("comment of a deleted task error = %v", err)
+	}
+	if _, err := store.GetComment(ctx, kept.ID); err != nil {
+		t.Errorf("comment of another task: %v", err)
+	}
+}
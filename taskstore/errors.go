@@ -0,0 +1,41 @@
+package taskstore
+
+import "errors"
+
+// Clases de error del store. Cada error concreto pertenece a una de ellas y se
+// puede comprobar con errors.Is, p.ej. errors.Is(ErrTaskNotFound, ErrNotFound).
+var (
+	ErrNotFound   = errors.New("not found")
+	ErrValidation = errors.New("validation failed")
+	ErrConflict   = errors.New("conflict")
+)
+
+var (
+	ErrTaskNotFound    = NewError(ErrNotFound, "task not found")
+	ErrProjectNotFound = NewError(ErrNotFound, "project not found")
+	ErrCommentNotFound = NewError(ErrNotFound, "comment not found")
+
+	// Se devuelve al eliminar en modo reject un proyecto que aun tiene tareas
+	ErrProjectHasTasks = NewError(ErrConflict, "project has tasks")
+	ErrBatchAborted    = NewError(ErrConflict, "batch aborted, no operation was applied")
+
+	ErrInvalidReminder   = NewError(ErrValidation, "invalid reminder offset")
+	ErrInvalidChannel    = NewError(ErrValidation, "unknown reminder channel")
+	ErrUnsupportedBatch  = NewError(ErrValidation, "unsupported batch operation")
+	ErrImportInvalid     = NewError(ErrValidation, "import has invalid tasks, nothing was imported")
+	ErrInvalidDeleteMode = NewError(ErrValidation, "invalid delete mode")
+)
+
+// Error con mensaje propio que pertenece a una clase (ErrNotFound, ErrValidation, ErrConflict)
+type classError struct {
+	msg   string
+	class error
+}
+
+func (e *classError) Error() string { return e.msg }
+func (e *classError) Unwrap() error { return e.class }
+
+// Crea un error de la clase indicada, tambien lo usan otros paquetes para sus errores
+func NewError(class error, msg string) error {
+	return &classError{msg: msg, class: class}
+}
@@ -0,0 +1,55 @@
+package taskstore
+
+import "time"
+
+// Tipos de evento que emite el store en cada cambio
+const (
+	EventTaskCreated          = "task.created"
+	EventTaskDeleted          = "task.deleted"
+	EventTaskDeletedAll       = "task.deleted_all"
+	EventTaskRemindersUpdated = "task.reminders_updated"
+	EventProjectCreated       = "project.created"
+	EventProjectUpdated       = "project.updated"
+	EventProjectDeleted       = "project.deleted"
+	EventCommentCreated       = "comment.created"
+	EventCommentUpdated       = "comment.updated"
+	EventCommentDeleted       = "comment.deleted"
+)
+
+// Cambio ocurrido en el store, Data es el objeto afectado
+type Event struct {
+	Type string    `json:"type"`
+	Time time.Time `json:"time"`
+	Data any       `json:"data,omitempty"`
+}
+
+// Registra una funcion que recibe todos los eventos del store. Se llama con el lock
+// tomado y en orden, por lo que no debe bloquear ni volver a usar el store.
+func (ts *TaskStore) Subscribe(fn func(Event)) {
+	ts.Lock()
+	defer ts.Unlock()
+
+	ts.listeners = append(ts.listeners, fn)
+}
+
+// Notifica un evento a los suscriptores, requiere tener el lock. Dentro de un
+// batch los eventos se retienen hasta que se confirma. Todo cambio pasa por aqui,
+// asi que tambien aumenta la version del store.
+func (ts *TaskStore) emit(eventType string, data any) {
+	ts.touch()
+	if len(ts.listeners) == 0 {
+		return
+	}
+	event := Event{Type: eventType, Time: time.Now().UTC(), Data: data}
+	if ts.pending != nil {
+		*ts.pending = append(*ts.pending, event)
+		return
+	}
+	ts.publish(event)
+}
+
+func (ts *TaskStore) publish(event Event) {
+	for _, fn := range ts.listeners {
+		fn(event)
+	}
+}
@@ -0,0 +1,101 @@
+package taskstore
+
+import (
+	"context"
+	"errors"
+	"strconv"
+)
+
+// Error de validacion de una tarea importada, Index es su posicion en la entrada.
+// Errors son los errores de cada campo (validation.Errors) cuando los hay.
+type ImportError struct {
+	Index  int    `json:"index"`
+	ID     string `json:"id,omitempty"`
+	Error  string `json:"error"`
+	Errors any    `json:"errors,omitempty"`
+}
+
+// Resultado de una importacion. IDs relaciona el Id de origen con el asignado.
+type ImportReport struct {
+	DryRun      bool              `json:"dryRun"`
+	PreserveIDs bool              `json:"preserveIds"`
+	Total       int               `json:"total"`
+	Imported    int               `json:"imported"`
+	IDs         map[string]string `json:"ids"`
+	Errors      []ImportError     `json:"errors"`
+}
+
+// Importa tareas validandolas todas primero: si alguna es invalida no se importa
+// ninguna y se devuelve ErrImportInvalid. Aqui solo se comprueba lo que depende
+// del store (proyecto, Ids); el contenido de cada tarea lo valida antes quien
+// llama con las reglas compartidas (ver taskio.Import). Con preserveIDs las tareas conservan su
+// Id (un Id repetido o ya existente es un error); si no, se les asigna uno nuevo.
+// Con dryRun solo se valida.
+func (ts *TaskStore) ImportTasks(ctx context.Context, tasks []Task, preserveIDs, dryRun bool) (ImportReport, error) {
+	_, span := startSpan(ctx, "ImportTasks")
+	defer span.End()
+	ts.Lock()
+	defer ts.Unlock()
+
+	report := ImportReport{
+		DryRun:      dryRun,
+		PreserveIDs: preserveIDs,
+		Total:       len(tasks),
+		IDs:         make(map[string]string),
+		Errors:      make([]ImportError, 0),
+	}
+
+	seen := make(map[string]bool, len(tasks))
+	for i, task := range tasks {
+		if err := ts.validateImport(task, preserveIDs, seen); err != nil {
+			report.Errors = append(report.Errors, ImportError{Index: i, ID: task.ID, Error: err.Error()})
+		}
+		seen[task.ID] = true
+	}
+	if len(report.Errors) > 0 {
+		return report, ErrImportInvalid
+	}
+	if dryRun {
+		return report, nil
+	}
+
+	for _, task := range tasks {
+		oldId := task.ID
+		if preserveIDs {
+			// El contador queda por encima de los Ids numericos importados
+			if n, err := strconv.Atoi(task.ID); err == nil && n >= ts.nextId {
+				ts.nextId = n + 1
+			}
+		} else {
+			task.ID = strconv.Itoa(ts.nextId)
+			ts.nextId++
+		}
+		ts.tasks[task.ID] = task
+		ts.emit(EventTaskCreated, task)
+
+		report.Imported++
+		if oldId != "" {
+			report.IDs[oldId] = task.ID
+		}
+	}
+	return report, nil
+}
+
+// Requiere tener el lock
+func (ts *TaskStore) validateImport(task Task, preserveIDs bool, seen map[string]bool) error {
+	if task.ProjectID != nil {
+		if _, ok := ts.projects[*task.ProjectID]; !ok {
+			return ErrProjectNotFound
+		}
+	}
+	if !preserveIDs {
+		return nil
+	}
+	if task.ID == "" {
+		return errors.New("id is required when preserving ids")
+	}
+	if _, ok := ts.tasks[task.ID]; ok || seen[task.ID] {
+		return errors.New("id " + task.ID + " already exists")
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package taskstore
+
+import (
+	"context"
+	"fmt"
+	"go.opentelemetry.io/otel/attribute"
+	"sort"
+	"strconv"
+)
+
+// Proyecto del dominio; como Task, sus tags json son los del archivo de datos.
+// TaskCount se calcula al leerlo.
+type Project struct {
+	ID          string `json:"ID"`
+	Name        string `json:"Name"`
+	Description string `json:"Description"`
+	TaskCount   int    `json:"TaskCount"`
+}
+
+// Modos de eliminacion de un proyecto
+type DeleteMode string
+
+const (
+	// Rechaza la eliminacion si el proyecto tiene tareas
+	DeleteReject DeleteMode = "reject"
+	// Elimina el proyecto junto con todas sus tareas
+	DeleteCascade DeleteMode = "cascade"
+)
+
+// ------------------------------- Metodos de proyectos --------------------------------------------------//
+
+// Creacion de un nuevo proyecto
+func (ts *TaskStore) CreateProject(ctx context.Context, name, description string) string {
+	_, span := startSpan(ctx, "CreateProject")
+	defer span.End()
+	ts.Lock()
+	defer ts.Unlock()
+
+	idStr := strconv.Itoa(ts.nextProjectId)
+	project := Project{
+		ID:          idStr,
+		Name:        name,
+		Description: description,
+	}
+	ts.projects[idStr] = project
+	ts.nextProjectId++
+	ts.emit(EventProjectCreated, project)
+	return idStr
+}
+
+// O(1) obtenemos el proyecto por Id
+func (ts *TaskStore) GetProject(ctx context.Context, id string) (Project, error) {
+	_, span := startSpan(ctx, "GetProject", attribute.String("project.id", id))
+	defer span.End()
+	ts.Lock()
+	defer ts.Unlock()
+
+	project, ok := ts.projects[id]
+	if !ok {
+		return Project{}, ErrProjectNotFound
+	}
+	return ts.withTaskCount(project), nil
+}
+
+// Actualiza nombre y descripcion de un proyecto existente
+func (ts *TaskStore) UpdateProject(ctx context.Context, id, name, description string) (Project, error) {
+	_, span := startSpan(ctx, "UpdateProject", attribute.String("project.id", id))
+	defer span.End()
+	ts.Lock()
+	defer ts.Unlock()
+
+	project, ok := ts.projects[id]
+	if !ok {
+		return Project{}, ErrProjectNotFound
+	}
+	project.Name = name
+	project.Description = description
+	ts.projects[id] = project
+	project = ts.withTaskCount(project)
+	ts.emit(EventProjectUpdated, project)
+	return project, nil
+}
+
+// Elimina un proyecto. En modo reject falla con ErrProjectHasTasks si tiene tareas,
+// en modo cascade elimina tambien todas sus tareas.
+func (ts *TaskStore) DeleteProject(ctx context.Context, id string, mode DeleteMode) error {
+	_, span := startSpan(ctx, "DeleteProject", attribute.String("project.id", id))
+	defer span.End()
+	ts.Lock()
+	defer ts.Unlock()
+
+	project, ok := ts.projects[id]
+	if !ok {
+		return ErrProjectNotFound
+	}
+
+	taskIds := ts.projectTaskIds(id)
+	switch mode {
+	case DeleteCascade:
+		for _, taskId := range taskIds {
+			ts.removeTask(taskId)
+		}
+	case DeleteReject:
+		if len(taskIds) > 0 {
+			return ErrProjectHasTasks
+		}
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidDeleteMode, mode)
+	}
+
+	delete(ts.projects, id)
+	ts.emit(EventProjectDeleted, project)
+	return nil
+}
+
+// Lista paginada de proyectos ordenados por Id, devuelve tambien el total
+func (ts *TaskStore) GetAllProjects(ctx context.Context, offset, limit int) ([]Project, int, error) {
+	_, span := startSpan(ctx, "GetAllProjects")
+	defer span.End()
+	ts.Lock()
+	defer ts.Unlock()
+
+	projects := make([]Project, 0, len(ts.projects))
+	for _, project := range ts.projects {
+		projects = append(projects, ts.withTaskCount(project))
+	}
+	sort.Slice(projects, func(i, j int) bool {
+		return LessId(projects[i].ID, projects[j].ID)
+	})
+
+	start, end := pageBounds(len(projects), offset, limit)
+	return projects[start:end], len(projects), nil
+}
+
+// Lista paginada de las tareas de un proyecto ordenadas por Id, devuelve tambien el total
+func (ts *TaskStore) GetTasksByProject(ctx context.Context, id string, offset, limit int) ([]Task, int, error) {
+	_, span := startSpan(ctx, "GetTasksByProject", attribute.String("project.id", id))
+	defer span.End()
+	ts.Lock()
+	defer ts.Unlock()
+
+	if _, ok := ts.projects[id]; !ok {
+		return nil, 0, ErrProjectNotFound
+	}
+
+	taskIds := ts.projectTaskIds(id)
+	start, end := pageBounds(len(taskIds), offset, limit)
+
+	tasks := make([]Task, 0, end-start)
+	for _, taskId := range taskIds[start:end] {
+		tasks = append(tasks, ts.tasks[taskId])
+	}
+	return tasks, len(taskIds), nil
+}
+
+// Ids ordenados de las tareas que pertenecen a un proyecto, requiere tener el lock
+func (ts *TaskStore) projectTaskIds(projectID string) []string {
+	ids := make([]string, 0)
+	for id, task := range ts.tasks {
+		if task.ProjectID != nil && *task.ProjectID == projectID {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return LessId(ids[i], ids[j]) })
+	return ids
+}
+
+// Copia del proyecto con TaskCount calculado, requiere tener el lock
+func (ts *TaskStore) withTaskCount(project Project) Project {
+	project.TaskCount = len(ts.projectTaskIds(project.ID))
+	return project
+}
+
+// Los Ids son enteros en forma de string, se comparan numericamente (los que no
+// son numeros, como texto)
+func LessId(a, b string) bool {
+	na, errA := strconv.Atoi(a)
+	nb, errB := strconv.Atoi(b)
+	if errA != nil || errB != nil {
+		return a < b
+	}
+	return na < nb
+}
+
+// Limites [start, end) de una pagina, limit <= 0 significa sin limite
+func pageBounds(total, offset, limit int) (int, int) {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > total {
+		offset = total
+	}
+	end := total
+	if limit > 0 && offset+limit < total {
+		end = offset + limit
+	}
+	return offset, end
+}
@@ -0,0 +1,162 @@
+package taskstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// Store con un proyecto con dos tareas, uno vacio y una tarea sin proyecto
+func newProjectStore(t *testing.T) (*TaskStore, string, string) {
+	t.Helper()
+	ctx := context.Background()
+	store := New()
+	withTasks := store.CreateProject(ctx, "work", "")
+	empty := store.CreateProject(ctx, "home", "")
+	for _, project := range []string{withTasks, withTasks, ""} {
+		if _, err := store.CreateTask(ctx, "task", nil, time.Now(), nil, project); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return store, withTasks, empty
+}
+
+func TestProjectCRUD(t *testing.T) {
+	ctx := context.Background()
+	store, id, _ := newProjectStore(t)
+
+	project, err := store.GetProject(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if project.Name != "work" || project.TaskCount != 2 {
+		t.Errorf("GetProject() = %+v", project)
+	}
+
+	project, err = store.UpdateProject(ctx, id, "office", "desc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if project.Name != "office" || project.Description != "desc" || project.TaskCount != 2 {
+		t.Errorf("UpdateProject() = %+v", project)
+	}
+
+	if _, err := store.UpdateProject(ctx, "404", "x", ""); !errors.Is(err, ErrProjectNotFound) {
+		t.Errorf("UpdateProject(404) error = %v", err)
+	}
+	if _, err := store.GetProject(ctx, "404"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetProject(404) error = %v", err)
+	}
+	if _, err := store.CreateTask(ctx, "task", nil, time.Now(), nil, "404"); !errors.Is(err, ErrProjectNotFound) {
+		t.Errorf("CreateTask in a missing project error = %v", err)
+	}
+}
+
+func TestDeleteProject(t *testing.T) {
+	tests := []struct {
+		name      string
+		project   string // "tasks", "empty" o un Id
+		mode      DeleteMode
+		wantErr   error
+		wantTasks int // tareas que quedan en el store
+	}{
+		{"reject with tasks", "tasks", DeleteReject, ErrProjectHasTasks, 3},
+		{"reject empty", "empty", DeleteReject, nil, 3},
+		{"cascade", "tasks", DeleteCascade, nil, 1},
+		{"invalid mode", "tasks", "all", ErrInvalidDeleteMode, 3},
+		{"missing", "404", DeleteCascade, ErrProjectNotFound, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			store, withTasks, empty := newProjectStore(t)
+			id := map[string]string{"tasks": withTasks, "empty": empty}[tt.project]
+			if id == "" {
+				id = tt.project
+			}
+
+			err := store.DeleteProject(ctx, id, tt.mode)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DeleteProject() error = %v, want %v", err, tt.wantErr)
+			}
+			// El proyecto solo se elimina si no hay error
+			if _, getErr := store.GetProject(ctx, id); tt.project != "404" && (getErr == nil) != (err != nil) {
+				t.Errorf("project exists = %v after error %v", getErr == nil, err)
+			}
+			tasks, _ := store.GetAllTasks(ctx)
+			if len(tasks) != tt.wantTasks {
+				t.Errorf("%d tasks left, want %d", len(tasks), tt.wantTasks)
+			}
+		})
+	}
+}
+
+func TestProjectPagination(t *testing.T) {
+	ctx := context.Background()
+	store := New()
+	for i := range 12 {
+		store.CreateProject(ctx, fmt.Sprint("p", i), "")
+	}
+
+	tests := []struct {
+		offset, limit int
+		want          []string
+	}{
+		{0, 0, []string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11"}},
+		{0, 3, []string{"0", "1", "2"}},
+		{9, 5, []string{"9", "10", "11"}},
+		{20, 5, nil},
+		{-1, 1, []string{"0"}},
+	}
+	for _, tt := range tests {
+		projects, total, err := store.GetAllProjects(ctx, tt.offset, tt.limit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []string
+		for _, p := range projects {
+			ids = append(ids, p.ID)
+		}
+		if fmt.Sprint(ids) != fmt.Sprint(tt.want) || total != 12 {
+			t.Errorf("GetAllProjects(%d, %d) = %v, %d, want %v, 12", tt.offset, tt.limit, ids, total, tt.want)
+		}
+	}
+}
+
+func TestGetTasksByProject(t *testing.T) {
+	ctx := context.Background()
+	store, id, empty := newProjectStore(t)
+
+	tasks, total, err := store.GetTasksByProject(ctx, id, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 2 || len(tasks) != 1 || tasks[0].ID != "1" {
+		t.Errorf("GetTasksByProject() = %v, %d", tasks, total)
+	}
+	if tasks, total, _ := store.GetTasksByProject(ctx, empty, 0, 0); total != 0 || len(tasks) != 0 {
+		t.Errorf("empty project has %d tasks", total)
+	}
+	if _, _, err := store.GetTasksByProject(ctx, "404", 0, 0); !errors.Is(err, ErrProjectNotFound) {
+		t.Errorf("GetTasksByProject(404) error = %v", err)
+	}
+}
+
+func TestProjectEvents(t *testing.T) {
+	ctx := context.Background()
+	store, id, _ := newProjectStore(t)
+	var events []string
+	store.Subscribe(func(e Event) { events = append(events, e.Type) })
+
+	store.UpdateProject(ctx, id, "x", "")
+	store.DeleteProject(ctx, id, DeleteReject)
+	store.DeleteProject(ctx, id, DeleteCascade)
+
+	// En cascada se eliminan antes las tareas
+	want := []string{EventProjectUpdated, EventTaskDeleted, EventTaskDeleted, EventProjectDeleted}
+	if fmt.Sprint(events) != fmt.Sprint(want) {
+		t.Errorf("events = %v, want %v", events, want)
+	}
+}
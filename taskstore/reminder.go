@@ -0,0 +1,86 @@
+package taskstore
+
+import (
+	"context"
+	"fmt"
+	"go.opentelemetry.io/otel/attribute"
+	"slices"
+	"time"
+)
+
+// Recordatorios propios de una tarea; sus tags json son los del archivo de datos
+type ReminderConfig struct {
+	Offsets  []string `json:"Offsets"`
+	Channels []string `json:"Channels"`
+}
+
+// ------------------------------- Metodos de recordatorios --------------------------------------------------//
+
+// Guarda la configuracion de recordatorios de una tarea. Los offsets son duraciones
+// de Go ("24h", "15m") antes de Due; una lista vacia elimina la configuracion propia
+// y la tarea vuelve a usar los offsets por defecto del scheduler. Los canales deben
+// ser de los registrados con SetReminderChannels.
+func (ts *TaskStore) SetReminders(ctx context.Context, taskID string, offsets, channels []string) (ReminderConfig, error) {
+	_, span := startSpan(ctx, "SetReminders", attribute.String("task.id", taskID))
+	defer span.End()
+	for _, offset := range offsets {
+		d, err := time.ParseDuration(offset)
+		if err != nil || d < 0 {
+			return ReminderConfig{}, fmt.Errorf("%w: %q", ErrInvalidReminder, offset)
+		}
+	}
+
+	ts.Lock()
+	defer ts.Unlock()
+
+	if ts.channels != nil {
+		for _, channel := range channels {
+			if !slices.Contains(ts.channels, channel) {
+				return ReminderConfig{}, fmt.Errorf("%w: %q", ErrInvalidChannel, channel)
+			}
+		}
+	}
+	if _, ok := ts.tasks[taskID]; !ok {
+		return ReminderConfig{}, ErrTaskNotFound
+	}
+
+	config := ReminderConfig{
+		Offsets:  append([]string{}, offsets...),
+		Channels: append([]string{}, channels...),
+	}
+	if len(offsets) == 0 {
+		delete(ts.reminders, taskID)
+		config.Channels = []string{}
+	} else {
+		ts.reminders[taskID] = config
+	}
+	ts.emit(EventTaskRemindersUpdated, map[string]any{"TaskId": taskID, "Reminders": config})
+	return config, nil
+}
+
+// Registra los canales por los que se pueden entregar recordatorios (los
+// notificadores del scheduler); SetReminders rechaza cualquier otro.
+func (ts *TaskStore) SetReminderChannels(names ...string) {
+	ts.Lock()
+	defer ts.Unlock()
+
+	ts.channels = append([]string{}, names...)
+}
+
+// Configuracion de recordatorios de una tarea, el bool indica si la tarea tiene una propia
+func (ts *TaskStore) GetReminders(ctx context.Context, taskID string) (ReminderConfig, bool, error) {
+	_, span := startSpan(ctx, "GetReminders", attribute.String("task.id", taskID))
+	defer span.End()
+	ts.Lock()
+	defer ts.Unlock()
+
+	if _, ok := ts.tasks[taskID]; !ok {
+		return ReminderConfig{}, false, ErrTaskNotFound
+	}
+
+	config, ok := ts.reminders[taskID]
+	if !ok {
+		return ReminderConfig{Offsets: []string{}, Channels: []string{}}, false, nil
+	}
+	return config, true, nil
+}
@@ -0,0 +1,131 @@
+package taskstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Contenido completo del store tal como se guarda en el archivo de datos
+type Snapshot struct {
+	Tasks         []Task                    `json:"tasks"`
+	Projects      []Project                 `json:"projects"`
+	Comments      []Comment                 `json:"comments"`
+	Reminders     map[string]ReminderConfig `json:"reminders"`
+	NextId        int                       `json:"nextId"`
+	NextProjectId int                       `json:"nextProjectId"`
+	NextCommentId int                       `json:"nextCommentId"`
+}
+
+// Copia del estado actual ordenada por Id
+func (ts *TaskStore) Snapshot(ctx context.Context) Snapshot {
+	_, span := startSpan(ctx, "Snapshot")
+	defer span.End()
+	ts.Lock()
+	defer ts.Unlock()
+
+	snap := Snapshot{
+		Tasks:         make([]Task, 0, len(ts.tasks)),
+		Projects:      make([]Project, 0, len(ts.projects)),
+		Comments:      make([]Comment, 0, len(ts.comments)),
+		Reminders:     make(map[string]ReminderConfig, len(ts.reminders)),
+		NextId:        ts.nextId,
+		NextProjectId: ts.nextProjectId,
+		NextCommentId: ts.nextCommentId,
+	}
+	for _, task := range ts.tasks {
+		snap.Tasks = append(snap.Tasks, task)
+	}
+	for _, project := range ts.projects {
+		snap.Projects = append(snap.Projects, project)
+	}
+	for _, comment := range ts.comments {
+		snap.Comments = append(snap.Comments, comment)
+	}
+	for id, config := range ts.reminders {
+		snap.Reminders[id] = config
+	}
+
+	sort.Slice(snap.Tasks, func(i, j int) bool { return LessId(snap.Tasks[i].ID, snap.Tasks[j].ID) })
+	sort.Slice(snap.Projects, func(i, j int) bool { return LessId(snap.Projects[i].ID, snap.Projects[j].ID) })
+	sort.Slice(snap.Comments, func(i, j int) bool { return LessId(snap.Comments[i].ID, snap.Comments[j].ID) })
+	return snap
+}
+
+// Reemplaza el estado del store por el de la snapshot
+func (ts *TaskStore) Restore(snap Snapshot) {
+	ts.Lock()
+	defer ts.Unlock()
+
+	ts.tasks = make(map[string]Task, len(snap.Tasks))
+	for _, task := range snap.Tasks {
+		ts.tasks[task.ID] = task
+	}
+	ts.projects = make(map[string]Project, len(snap.Projects))
+	for _, project := range snap.Projects {
+		ts.projects[project.ID] = project
+	}
+	ts.comments = make(map[string]Comment, len(snap.Comments))
+	for _, comment := range snap.Comments {
+		ts.comments[comment.ID] = comment
+	}
+	ts.reminders = make(map[string]ReminderConfig, len(snap.Reminders))
+	for id, config := range snap.Reminders {
+		ts.reminders[id] = config
+	}
+	ts.nextId = snap.NextId
+	ts.nextProjectId = snap.NextProjectId
+	ts.nextCommentId = snap.NextCommentId
+	ts.touch()
+}
+
+// Carga un store desde el archivo de datos, si no existe devuelve un store vacio
+func LoadFile(path string) (*TaskStore, error) {
+	ts := New()
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return ts, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var snap Snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, err
+	}
+	ts.Restore(snap)
+	return ts, nil
+}
+
+// Guarda el store en el archivo de datos (archivo temporal + rename)
+func (ts *TaskStore) SaveFile(path string) error {
+	data, err := json.MarshalIndent(ts.Snapshot(context.Background()), "", "  ")
+	if err != nil {
+		return err
+	}
+	return WriteFileAtomic(path, data)
+}
+
+// Escribe data en un archivo temporal del mismo directorio y lo renombra a path,
+// asi un corte a mitad de la escritura nunca deja el archivo a medias
+func WriteFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
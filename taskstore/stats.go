@@ -0,0 +1,60 @@
+package taskstore
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Tamanos del store para las metricas
+type Stats struct {
+	Tasks           int
+	Tags            int // tags distintos
+	AttachmentBytes int
+	Projects        int
+	Comments        int
+}
+
+func (ts *TaskStore) Stats() Stats {
+	ts.Lock()
+	defer ts.Unlock()
+
+	stats := Stats{Tasks: len(ts.tasks), Projects: len(ts.projects), Comments: len(ts.comments)}
+	tags := make(map[string]struct{})
+	for _, task := range ts.tasks {
+		for _, tag := range task.Tags {
+			tags[tag] = struct{}{}
+		}
+		for _, a := range task.Attachments {
+			if a != nil {
+				stats.AttachmentBytes += len(a.Contents)
+			}
+		}
+	}
+	stats.Tags = len(tags)
+	return stats
+}
+
+// Funcion que recibe cuanto espero cada Lock del store
+var lockObserver atomic.Pointer[func(time.Duration)]
+
+// Registra el observador de espera del mutex, nil lo desactiva. Es global porque
+// hay un solo store por proceso y asi Lock no paga nada cuando no hay metricas.
+func SetLockObserver(fn func(time.Duration)) {
+	if fn == nil {
+		lockObserver.Store(nil)
+		return
+	}
+	lockObserver.Store(&fn)
+}
+
+// Oculta sync.Mutex.Lock para medir el tiempo de espera del mutex
+func (ts *TaskStore) Lock() {
+	observe := lockObserver.Load()
+	if observe == nil {
+		ts.Mutex.Lock()
+		return
+	}
+	start := time.Now()
+	ts.Mutex.Lock()
+	(*observe)(time.Since(start))
+}
@@ -0,0 +1,21 @@
+package taskstore
+
+import "time"
+
+// Tarea del dominio. Es la que guarda el store y la del archivo de datos, por eso
+// sus tags json no cambian; REST y GraphQL la convierten a sus propias
+// representaciones.
+type Task struct {
+	ID          string        `json:"ID"`
+	Text        string        `json:"Text"`
+	Tags        []string      `json:"Tags,omitempty"`
+	Due         time.Time     `json:"Due"`
+	Attachments []*Attachment `json:"Attachments,omitempty"`
+	ProjectID   *string       `json:"ProjectId,omitempty"`
+}
+
+type Attachment struct {
+	Name     string    `json:"Name"`
+	Date     time.Time `json:"Date"`
+	Contents string    `json:"Contents"`
+}
@@ -1,8 +1,11 @@
 package taskstore
 
 import (
-	"errors"
-	"restServer/graph/model"
+	"context"
+	"go.opentelemetry.io/otel/attribute"
+	"restServer/logging"
+	"slices"
+	"sort"
 	"strconv"
 	"sync"
 	"time"
@@ -12,44 +15,106 @@ import (
 type TaskStore struct {
 	// Mutex para evitar condiciones de carrera
 	sync.Mutex
-	tasks  map[string]model.Task
+	tasks  map[string]Task
 	nextId int
+
+	// Proyectos que agrupan tareas
+	projects      map[string]Project
+	nextProjectId int
+
+	// Comentarios de las tareas
+	comments      map[string]Comment
+	nextCommentId int
+
+	// Configuracion de recordatorios por Id de tarea
+	reminders map[string]ReminderConfig
+	// Canales de recordatorio que existen (nil = no se comprueban)
+	channels []string
+
+	// Suscriptores a los eventos de cambio
+	listeners []func(Event)
+	// Eventos retenidos mientras se ejecuta un batch
+	pending *[]Event
+
+	// Version de los datos, aumenta con cada cambio (para ETag y Last-Modified)
+	version  uint64
+	modified time.Time
 }
 
 // Funcion para declarar una nueva memoria de Tasks
 func New() *TaskStore {
 	ts := &TaskStore{}
-	ts.tasks = make(map[string]model.Task)
+	ts.tasks = make(map[string]Task)
 	ts.nextId = 0
+	ts.projects = make(map[string]Project)
+	ts.nextProjectId = 0
+	ts.comments = make(map[string]Comment)
+	ts.nextCommentId = 0
+	ts.reminders = make(map[string]ReminderConfig)
+	ts.modified = time.Now().UTC()
 	return ts
 }
 
+// Version actual de los datos y la hora del ultimo cambio. Cualquier cambio del
+// store (tareas, proyectos, comentarios, recordatorios) aumenta la version.
+func (ts *TaskStore) Version() (uint64, time.Time) {
+	ts.Lock()
+	defer ts.Unlock()
+
+	return ts.version, ts.modified
+}
+
+// Registra un cambio, requiere tener el lock
+func (ts *TaskStore) touch() {
+	ts.version++
+	ts.modified = time.Now().UTC()
+}
+
 // ------------------------------- Creacion de metodos para la memoria --------------------------------------------------//
 
-// Creacion de una nueva tarea
-func (ts *TaskStore) CreateTask(text string, tags []string, due time.Time, attachments []*model.Attachment) string {
+// Creacion de una nueva tarea, projectID es opcional ("" = sin proyecto)
+func (ts *TaskStore) CreateTask(ctx context.Context, text string, tags []string, due time.Time, attachments []*Attachment, projectID string) (string, error) {
+	_, span := startSpan(ctx, "CreateTask")
+	defer span.End()
 	ts.Lock()
 	defer ts.Unlock()
 
+	return ts.createTask(text, tags, due, attachments, projectID)
+}
+
+// Creacion de una tarea, requiere tener el lock
+func (ts *TaskStore) createTask(text string, tags []string, due time.Time, attachments []*Attachment, projectID string) (string, error) {
+	if projectID != "" {
+		if _, ok := ts.projects[projectID]; !ok {
+			return "", ErrProjectNotFound
+		}
+	}
+
 	idStr := strconv.Itoa(ts.nextId)
 
 	// creamos una nueva variable de tipo Task
-	newTask := model.Task{
+	newTask := Task{
 		ID:          idStr,
 		Text:        text,
 		Tags:        tags,
 		Due:         due,
 		Attachments: attachments,
 	}
+	if projectID != "" {
+		newTask.ProjectID = &projectID
+	}
 
 	// En la memoria guardamos la nueva tarea con el Id asignado
 	ts.tasks[idStr] = newTask
 	ts.nextId++
-	return newTask.ID
+	ts.emit(EventTaskCreated, newTask)
+	return newTask.ID, nil
 }
 
 // O(1) obtenemos la tarea por Id
-func (ts *TaskStore) GetTask(id string) (model.Task, error) {
+func (ts *TaskStore) GetTask(ctx context.Context, id string) (Task, error) {
+	_, span := startSpan(ctx, "GetTask", attribute.String("task.id", id))
+	defer span.End()
 	ts.Lock()
 	defer ts.Unlock()
 
@@ -57,63 +122,87 @@ func (ts *TaskStore) GetTask(id string) (model.Task, error) {
 	if ok {
 		return task, nil
 	} else {
-		return model.Task{}, errors.New("task not found")
+		return Task{}, ErrTaskNotFound
 	}
 }
 
 // O(1) eliminamos la tarea por Id
-func (ts *TaskStore) DeleteTask(id string) error {
+func (ts *TaskStore) DeleteTask(ctx context.Context, id string) error {
+	_, span := startSpan(ctx, "DeleteTask", attribute.String("task.id", id))
+	defer span.End()
 	ts.Lock()
 	defer ts.Unlock()
 
+	return ts.deleteTask(id)
+}
+
+// Eliminacion de una tarea, requiere tener el lock
+func (ts *TaskStore) deleteTask(id string) error {
 	if _, ok := ts.tasks[id]; !ok {
-		return errors.New("task not found")
+		return ErrTaskNotFound
 	}
 
-	delete(ts.tasks, id)
+	ts.removeTask(id)
 	return nil
 }
 
 // Eliminar las tareas de la memoria, creamos un nuevo mapa vacio
-func (ts *TaskStore) DeleteAllTasks() error {
+func (ts *TaskStore) DeleteAllTasks(ctx context.Context) error {
+	_, span := startSpan(ctx, "DeleteAllTasks")
+	defer span.End()
 	ts.Lock()
 	defer ts.Unlock()
 
-	ts.tasks = make(map[string]model.Task)
+	ts.tasks = make(map[string]Task)
+	ts.comments = make(map[string]Comment)
+	ts.reminders = make(map[string]ReminderConfig)
+	ts.emit(EventTaskDeletedAll, nil)
 	return nil
 }
 
-// Obtener todas las tareas de la memoria O(n)
-func (ts *TaskStore) GetAllTasks() ([]model.Task, error) {
+// Elimina una tarea junto con sus comentarios y recordatorios, requiere tener el lock
+func (ts *TaskStore) removeTask(id string) {
+	task := ts.tasks[id]
+	delete(ts.tasks, id)
+	delete(ts.reminders, id)
+	for commentId, comment := range ts.comments {
+		if comment.TaskID == id {
+			delete(ts.comments, commentId)
+		}
+	}
+	ts.emit(EventTaskDeleted, task)
+}
+
+// Obtener todas las tareas de la memoria ordenadas por Id, O(n log n)
+func (ts *TaskStore) GetAllTasks(ctx context.Context) ([]Task, error) {
+	_, span := startSpan(ctx, "GetAllTasks")
+	defer span.End()
 	ts.Lock()
 	defer ts.Unlock()
 
-	allTasks := make([]model.Task, 0, len(ts.tasks)) // declaracion slice con capacidad inicial hasta la cantidad de tareas
+	allTasks := make([]Task, 0, len(ts.tasks)) // declaracion slice con capacidad inicial hasta la cantidad de tareas
 	for _, task := range ts.tasks {
 		allTasks = append(allTasks, task)
 	}
 
+	sortTasks(allTasks)
 	return allTasks, nil
 }
 
-// Obtener tareas por tag O(n) (se guarda en memoria sin orden)
-func (ts *TaskStore) GetTasksByTag(tag string) ([]model.Task, error) {
+// Obtener tareas por tag ordenadas por Id, se recorren todas (en memoria no hay orden)
+func (ts *TaskStore) GetTasksByTag(ctx context.Context, tag string) ([]Task, error) {
+	_, span := startSpan(ctx, "GetTasksByTag", attribute.String("task.tag", tag))
+	defer span.End()
 
 	ts.Lock()
 	defer ts.Unlock()
 
-	tasks := make([]model.Task, 0, len(ts.tasks))
-
-	// Debug: ver cuántas tareas hay
-	println("DEBUG: Total tareas en store:", len(ts.tasks))
-	println("DEBUG: Buscando tag:", tag)
+	tasks := make([]Task, 0, len(ts.tasks))
 
 	// Bucle etiquetado para continuar con el siguiente elemento del bucle externo
 taskloop: //label asociada a un bucle
 	for _, task := range ts.tasks {
-		println("DEBUG: Tarea ID:", task.ID, "Text:", task.Text, "Tags:", len(task.Tags))
-		for i, taskTag := range task.Tags {
-			println("  DEBUG: Tag[", i, "]:", taskTag)
+		for _, taskTag := range task.Tags {
 			if taskTag == tag {
 				tasks = append(tasks, task)
 				continue taskloop
@@ -121,17 +210,20 @@ taskloop: //label asociada a un bucle
 		}
 	}
 
-	println("DEBUG: Tareas encontradas:", len(tasks))
+	logging.FromContext(ctx).Debug("tasks by tag", "tag", tag, "scanned", len(ts.tasks), "matched", len(tasks))
+	sortTasks(tasks)
 	return tasks, nil
 
 }
 
-// Obtener tareas por fecha de vencimiento O(n) (se guarda en memoria sin orden)
-func (ts *TaskStore) GetTasksByDue(year int, month time.Month, day int) ([]model.Task, error) {
+// Obtener tareas por fecha de vencimiento ordenadas por Id, se recorren todas (en memoria no hay orden)
+func (ts *TaskStore) GetTasksByDue(ctx context.Context, year int, month time.Month, day int) ([]Task, error) {
+	_, span := startSpan(ctx, "GetTasksByDue")
+	defer span.End()
 	ts.Lock()
 	defer ts.Unlock()
 
-	tasksMatch := make([]model.Task, 0, len(ts.tasks))
+	tasksMatch := make([]Task, 0, len(ts.tasks))
 
 	for _, task := range ts.tasks {
 		y, m, d := task.Due.Date()
@@ -140,5 +232,55 @@ func (ts *TaskStore) GetTasksByDue(year int, month time.Month, day int) ([]model
 		}
 	}
 
+	sortTasks(tasksMatch)
 	return tasksMatch, nil
 }
+
+// Filtro de ListTasks; los campos vacios no filtran
+type TaskFilter struct {
+	Tag       string
+	Due       *time.Time // mismo dia que Due
+	ProjectID string
+}
+
+// Tareas que cumplen el filtro ordenadas por Id, para paginar con cursores estables
+func (ts *TaskStore) ListTasks(ctx context.Context, filter TaskFilter) ([]Task, error) {
+	_, span := startSpan(ctx, "ListTasks")
+	defer span.End()
+	ts.Lock()
+	defer ts.Unlock()
+
+	tasks := make([]Task, 0, len(ts.tasks))
+	for _, task := range ts.tasks {
+		if filter.matches(task) {
+			tasks = append(tasks, task)
+		}
+	}
+	sortTasks(tasks)
+	return tasks, nil
+}
+
+// Ordena por Id: las listas salen siempre en el mismo orden (respuestas
+// cacheables con ETag fuerte, cursores, export)
+func sortTasks(tasks []Task) {
+	sort.Slice(tasks, func(i, j int) bool {
+		return LessId(tasks[i].ID, tasks[j].ID)
+	})
+}
+
+func (f TaskFilter) matches(task Task) bool {
+	if f.Tag != "" && !slices.Contains(task.Tags, f.Tag) {
+		return false
+	}
+	if f.Due != nil {
+		y, m, d := f.Due.Date()
+		ty, tm, td := task.Due.Date()
+		if y != ty || m != tm || d != td {
+			return false
+		}
+	}
+	if f.ProjectID != "" && (task.ProjectID == nil || *task.ProjectID != f.ProjectID) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,74 @@
+package taskstore
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+// Las listas salen ordenadas por Id numerico ("10" despues de "9") aunque el
+// store sea un mapa
+func TestTaskListsAreSorted(t *testing.T) {
+	ctx := context.Background()
+	store := New()
+	due := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+	for range 25 {
+		if _, err := store.CreateTask(ctx, "task", []string{"tag"}, due, nil, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		list func() ([]Task, error)
+	}{
+		{"GetAllTasks", func() ([]Task, error) { return store.GetAllTasks(ctx) }},
+		{"GetTasksByTag", func() ([]Task, error) { return store.GetTasksByTag(ctx, "tag") }},
+		{"GetTasksByDue", func() ([]Task, error) { return store.GetTasksByDue(ctx, 2024, time.January, 2) }},
+		{"ListTasks", func() ([]Task, error) { return store.ListTasks(ctx, TaskFilter{Tag: "tag"}) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tasks, err := tt.list()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(tasks) != 25 {
+				t.Fatalf("got %d tasks, want 25", len(tasks))
+			}
+			sorted := slices.IsSortedFunc(tasks, func(a, b Task) int {
+				if LessId(a.ID, b.ID) {
+					return -1
+				}
+				return 1
+			})
+			if !sorted {
+				ids := make([]string, len(tasks))
+				for i := range tasks {
+					ids[i] = tasks[i].ID
+				}
+				t.Errorf("ids not sorted: %v", ids)
+			}
+		})
+	}
+}
+
+func TestLessId(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"1", "2", true},
+		{"9", "10", true},
+		{"10", "9", false},
+		{"2", "2", false},
+		{"a", "b", true},
+		{"10", "a", true},
+	}
+	for _, tt := range tests {
+		if got := LessId(tt.a, tt.b); got != tt.want {
+			t.Errorf("LessId(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package taskstore
+
+import (
+	"context"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("restServer/taskstore")
+
+// Span hijo de ctx para una operacion del store, p.ej. "TaskStore.GetTask".
+// Incluye la espera del mutex.
+func startSpan(ctx context.Context, op string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "TaskStore."+op, trace.WithAttributes(attrs...))
+}
@@ -0,0 +1,55 @@
+// Trazas de OpenTelemetry: proveedor global, propagacion W3C (traceparent y
+// baggage) y exportacion por OTLP/HTTP.
+package tracing
+
+import (
+	"context"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+)
+
+type Options struct {
+	Endpoint    string
+	Insecure    bool
+	ServiceName string
+	SampleRatio float64
+}
+
+// Crea el exportador OTLP/HTTP e instala el proveedor global. El exportador conecta
+// en el primer envio, un collector caido no impide iniciar.
+func Start(ctx context.Context, opts Options) (*sdktrace.TracerProvider, error) {
+	clientOpts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(opts.Endpoint)}
+	if opts.Insecure {
+		clientOpts = append(clientOpts, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(ctx, clientOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return Install(sdktrace.NewBatchSpanProcessor(exporter), opts), nil
+}
+
+// Instala un proveedor global con el procesador dado. Para pruebas se usa un
+// exportador en memoria:
+//
+//	exporter := tracetest.NewInMemoryExporter()
+//	tp := tracing.Install(sdktrace.NewSimpleSpanProcessor(exporter), tracing.Options{ServiceName: "test", SampleRatio: 1})
+//	... exporter.GetSpans()
+func Install(processor sdktrace.SpanProcessor, opts Options) *sdktrace.TracerProvider {
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(processor),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(opts.ServiceName))),
+		// Si el llamador ya decidio muestrear (flag del traceparent) se respeta
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SampleRatio))),
+	)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+	return tp
+}
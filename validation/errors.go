@@ -0,0 +1,78 @@
+// Validacion de las entradas compartida por REST y GraphQL. Los errores se
+// acumulan campo a campo y se identifican con JSON pointers (RFC 6901).
+package validation
+
+import (
+	"restServer/taskstore"
+	"strconv"
+	"strings"
+)
+
+// Error de un campo concreto, p.ej. {"pointer": "/tags/2", "code": "invalid_chars", ...}
+type FieldError struct {
+	Pointer string `json:"pointer"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Todos los errores de una entrada. Pertenece a la clase taskstore.ErrValidation.
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, f := range e {
+		msgs = append(msgs, f.Pointer+": "+f.Message)
+	}
+	return "validation failed: " + strings.Join(msgs, "; ")
+}
+
+func (e Errors) Unwrap() error { return taskstore.ErrValidation }
+
+func (e *Errors) Add(pointer, code, message string) {
+	*e = append(*e, FieldError{Pointer: pointer, Code: code, Message: message})
+}
+
+// Reemplaza los errores previos del mismo campo
+func (e *Errors) Set(pointer, code, message string) {
+	kept := (*e)[:0]
+	for _, f := range *e {
+		if f.Pointer != pointer {
+			kept = append(kept, f)
+		}
+	}
+	*e = kept
+	e.Add(pointer, code, message)
+}
+
+// Copia de los errores con los punteros relativos a prefix (p.ej. "/operations/3/task")
+func (e Errors) Prefix(prefix string) Errors {
+	out := make(Errors, len(e))
+	for i, f := range e {
+		f.Pointer = prefix + f.Pointer
+		out[i] = f
+	}
+	return out
+}
+
+// nil si no hay errores, para devolverlo como error sin caer en una interfaz no nula
+func (e Errors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Construye un JSON pointer escapando cada segmento
+func Pointer(segments ...any) string {
+	var b strings.Builder
+	for _, s := range segments {
+		b.WriteByte('/')
+		switch v := s.(type) {
+		case int:
+			b.WriteString(strconv.Itoa(v))
+		case string:
+			b.WriteString(strings.NewReplacer("~", "~0", "/", "~1").Replace(v))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,126 @@
+package validation
+
+import (
+	"fmt"
+	"regexp"
+	"restServer/taskstore"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Entrada de una tarea. Check la normaliza en el lugar.
+type Task struct {
+	Text        string
+	Tags        []string
+	Due         time.Time
+	Attachments []*taskstore.Attachment
+}
+
+// Nombres de los campos de una entrada de tarea, con ellos se arman los punteros
+// de los errores
+type TaskFields struct {
+	Text, Tags, Due, Attachments       string
+	AttachmentName, AttachmentContents string
+}
+
+var (
+	// Cuerpos de v1 y CSV de importacion: adjuntos con los campos de las respuestas v1
+	FieldsV1 = TaskFields{"text", "tags", "due", "attachments", "Name", "Contents"}
+	// Cuerpos de v2 e inputs camelCase de GraphQL
+	FieldsCamel = TaskFields{"text", "tags", "due", "attachments", "name", "contents"}
+	// Input NewTask de GraphQL y registros JSON de importacion
+	FieldsPascal = TaskFields{"Text", "Tags", "Due", "Attachments", "Name", "Contents"}
+)
+
+// Reglas de una tarea
+type TaskRules struct {
+	MaxTextLength     int            // en caracteres
+	MaxTags           int            // despues de quitar duplicados
+	MaxTagLength      int            // en caracteres
+	TagPattern        *regexp.Regexp // se aplica al tag ya normalizado
+	MinDue, MaxDue    time.Time
+	MaxAttachments    int
+	MaxAttachmentName int // en caracteres
+	MaxAttachmentSize int // bytes de Contents
+}
+
+var DefaultTaskRules = TaskRules{
+	MaxTextLength:     1000,
+	MaxTags:           20,
+	MaxTagLength:      32,
+	TagPattern:        regexp.MustCompile(`^[\p{L}\p{N}][\p{L}\p{N}_-]*$`),
+	MinDue:            time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC),
+	MaxDue:            time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC),
+	MaxAttachments:    10,
+	MaxAttachmentName: 255,
+	MaxAttachmentSize: 1 << 20,
+}
+
+// Normaliza la tarea (texto sin espacios en los extremos, tags en minusculas y sin
+// duplicados) y devuelve todos los errores encontrados, o nil. Los punteros usan
+// los nombres de fields.
+func (r TaskRules) Check(t *Task, fields TaskFields) Errors {
+	var errs Errors
+
+	t.Text = strings.TrimSpace(t.Text)
+	switch {
+	case t.Text == "":
+		errs.Add(Pointer(fields.Text), "required", "text is required")
+	case utf8.RuneCountInString(t.Text) > r.MaxTextLength:
+		errs.Add(Pointer(fields.Text), "too_long", fmt.Sprintf("text must be at most %d characters", r.MaxTextLength))
+	}
+
+	tags := make([]string, 0, len(t.Tags))
+	seen := make(map[string]bool, len(t.Tags))
+	for i, tag := range t.Tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		switch {
+		case tag == "":
+			errs.Add(Pointer(fields.Tags, i), "required", "tag must not be empty")
+		case utf8.RuneCountInString(tag) > r.MaxTagLength:
+			errs.Add(Pointer(fields.Tags, i), "too_long", fmt.Sprintf("tag must be at most %d characters", r.MaxTagLength))
+		case !r.TagPattern.MatchString(tag):
+			errs.Add(Pointer(fields.Tags, i), "invalid_chars", "tag may only contain letters, digits, '-' and '_'")
+		case !seen[tag]:
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	if len(tags) > r.MaxTags {
+		errs.Add(Pointer(fields.Tags), "too_many", fmt.Sprintf("at most %d distinct tags", r.MaxTags))
+	}
+	t.Tags = tags
+
+	switch {
+	case t.Due.IsZero():
+		errs.Add(Pointer(fields.Due), "required", "due is required")
+	case t.Due.Before(r.MinDue) || !t.Due.Before(r.MaxDue):
+		errs.Add(Pointer(fields.Due), "out_of_range", fmt.Sprintf("due must be between %s and %s",
+			r.MinDue.Format(time.DateOnly), r.MaxDue.Format(time.DateOnly)))
+	}
+
+	if len(t.Attachments) > r.MaxAttachments {
+		errs.Add(Pointer(fields.Attachments), "too_many", fmt.Sprintf("at most %d attachments", r.MaxAttachments))
+	}
+	for i, a := range t.Attachments {
+		if a == nil {
+			errs.Add(Pointer(fields.Attachments, i), "required", "attachment must not be null")
+			continue
+		}
+		a.Name = strings.TrimSpace(a.Name)
+		switch {
+		case a.Name == "":
+			errs.Add(Pointer(fields.Attachments, i, fields.AttachmentName), "required", "attachment name is required")
+		case utf8.RuneCountInString(a.Name) > r.MaxAttachmentName:
+			errs.Add(Pointer(fields.Attachments, i, fields.AttachmentName), "too_long",
+				fmt.Sprintf("attachment name must be at most %d characters", r.MaxAttachmentName))
+		}
+		if len(a.Contents) > r.MaxAttachmentSize {
+			errs.Add(Pointer(fields.Attachments, i, fields.AttachmentContents), "too_large",
+				fmt.Sprintf("attachment contents must be at most %d bytes", r.MaxAttachmentSize))
+		}
+	}
+
+	return errs
+}
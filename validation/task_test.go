@@ -0,0 +1,85 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"restServer/taskstore"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheck(t *testing.T) {
+	due := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+	tags := func(n int) []string {
+		out := make([]string, n)
+		for i := range out {
+			out[i] = fmt.Sprint("t", i)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name     string
+		task     Task
+		fields   TaskFields
+		want     []string // pointer:code de cada error
+		wantTags []string
+	}{
+		{"valid", Task{Text: " write ", Tags: []string{"Go", "go ", "web-dev"}, Due: due}, FieldsV1, nil, []string{"go", "web-dev"}},
+		{"missing text and due", Task{Text: "  "}, FieldsV1, []string{"/text:required", "/due:required"}, []string{}},
+		{"text too long", Task{Text: strings.Repeat("ñ", 1001), Due: due}, FieldsV1, []string{"/text:too_long"}, []string{}},
+		{"bad tags", Task{Text: "x", Tags: []string{"ok", "", "no spaces", strings.Repeat("a", 33)}, Due: due}, FieldsV1,
+			[]string{"/tags/1:required", "/tags/2:invalid_chars", "/tags/3:too_long"}, []string{"ok"}},
+		{"too many tags", Task{Text: "x", Tags: tags(21), Due: due}, FieldsV1, []string{"/tags:too_many"}, tags(21)},
+		{"duplicates do not count", Task{Text: "x", Tags: append(tags(20), "T0"), Due: due}, FieldsV1, nil, tags(20)},
+		{"due out of range", Task{Text: "x", Due: time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC)}, FieldsV1, []string{"/due:out_of_range"}, []string{}},
+		{"attachments", Task{Text: "x", Due: due, Attachments: []*taskstore.Attachment{nil, {Name: " "}, {Name: "big", Contents: strings.Repeat("x", 1<<20+1)}}}, FieldsCamel,
+			[]string{"/attachments/0:required", "/attachments/1/name:required", "/attachments/2/contents:too_large"}, []string{}},
+		{"pascal field names", Task{Text: "", Due: due, Attachments: []*taskstore.Attachment{{}}}, FieldsPascal,
+			[]string{"/Text:required", "/Attachments/0/Name:required"}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := tt.task
+			errs := DefaultTaskRules.Check(&task, tt.fields)
+
+			got := make([]string, 0, len(errs))
+			for _, e := range errs {
+				got = append(got, e.Pointer+":"+e.Code)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("errors = %v, want %v", got, tt.want)
+			}
+			if fmt.Sprint(task.Tags) != fmt.Sprint(tt.wantTags) {
+				t.Errorf("tags = %v, want %v", task.Tags, tt.wantTags)
+			}
+			if len(errs) == 0 && task.Text != strings.TrimSpace(tt.task.Text) {
+				t.Errorf("text = %q, not trimmed", task.Text)
+			}
+		})
+	}
+}
+
+func TestErrors(t *testing.T) {
+	var errs Errors
+	if errs.Err() != nil {
+		t.Error("empty Errors.Err() is not nil")
+	}
+	errs.Add("/text", "required", "text is required")
+	errs.Add("/due", "required", "due is required")
+	errs.Set("/text", "too_long", "text is too long")
+
+	if got := errs.Prefix("/operations/2").Error(); got != "validation failed: /operations/2/due: due is required; /operations/2/text: text is too long" {
+		t.Errorf("Error() = %q", got)
+	}
+	if !errors.Is(errs.Err(), taskstore.ErrValidation) {
+		t.Error("Errors is not a taskstore.ErrValidation")
+	}
+}
+
+func TestPointer(t *testing.T) {
+	if got := Pointer("tags", 3, "a/b~c"); got != "/tags/3/a~1b~0c" {
+		t.Errorf("Pointer() = %q", got)
+	}
+}
@@ -0,0 +1,332 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"restServer/taskstore"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Cabeceras de cada entrega. La firma es HMAC-SHA256 del cuerpo con el secreto
+// de la suscripcion, en hexadecimal: "sha256=<hex>".
+const (
+	HeaderSignature = "X-Webhook-Signature"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+)
+
+// Cuerpo JSON que recibe el suscriptor
+type Payload struct {
+	ID    string          `json:"id"`
+	Event taskstore.Event `json:"event"`
+}
+
+// Intento de entrega guardado en el log para depuracion
+type Delivery struct {
+	ID             string        `json:"id"`
+	SubscriptionID string        `json:"subscriptionId"`
+	Event          string        `json:"event"`
+	Attempt        int           `json:"attempt"`
+	StatusCode     int           `json:"statusCode,omitempty"`
+	Error          string        `json:"error,omitempty"`
+	Duration       time.Duration `json:"duration"`
+	Time           time.Time     `json:"time"`
+}
+
+// Entrega que agoto los reintentos
+type DeadLetter struct {
+	ID             string          `json:"id"`
+	SubscriptionID string          `json:"subscriptionId"`
+	Body           json.RawMessage `json:"body"`
+	Attempts       int             `json:"attempts"`
+	LastError      string          `json:"lastError"`
+	Time           time.Time       `json:"time"`
+}
+
+// La suscripcion se busca por Id en cada intento: los reintentos usan la url y
+// el secreto actuales y se descartan si se elimina o desactiva.
+type job struct {
+	deliveryID string
+	subID      string
+	event      string
+	body       []byte
+	attempt    int
+}
+
+// Dispatcher guarda las suscripciones en memoria (se pierden al reiniciar) y entrega los eventos con un pool de workers,
+// reintentando con backoff exponencial y dejando en dead letters lo que no se pudo entregar.
+type Dispatcher struct {
+	sync.Mutex
+	subs   map[string]Subscription
+	nextId int
+
+	deliveries     []Delivery
+	deadLetters    []DeadLetter
+	nextDeliveryId int
+
+	jobs    chan job
+	workers int
+	client  *http.Client
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	alive   atomic.Int32
+
+	// Intentos totales por entrega antes de pasar a dead letters
+	MaxAttempts int
+	// Espera antes del primer reintento, se duplica en cada intento hasta MaxBackoff
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	// Cantidad de intentos que se conservan en el log de entregas
+	LogSize int
+	// Cantidad de dead letters que se conservan, al llenarse se descartan las mas viejas
+	DeadLetterSize int
+}
+
+func NewDispatcher(workers int) *Dispatcher {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Dispatcher{
+		subs:           make(map[string]Subscription),
+		jobs:           make(chan job, 1024),
+		workers:        workers,
+		client:         &http.Client{Timeout: 10 * time.Second},
+		ctx:            ctx,
+		cancel:         cancel,
+		MaxAttempts:    5,
+		BaseBackoff:    time.Second,
+		MaxBackoff:     5 * time.Minute,
+		LogSize:        500,
+		DeadLetterSize: 1000,
+	}
+}
+
+// Inicia los workers de entrega
+func (d *Dispatcher) Start() {
+	for i := 0; i < d.workers; i++ {
+		d.wg.Add(1)
+		go d.work()
+	}
+}
+
+// Cantidad de workers en ejecucion y la configurada, para los chequeos de salud
+func (d *Dispatcher) Workers() (alive, configured int) {
+	return int(d.alive.Load()), d.workers
+}
+
+// Detiene los workers esperando las entregas en curso; los reintentos pendientes se descartan
+func (d *Dispatcher) Stop() {
+	d.cancel()
+	d.wg.Wait()
+}
+
+// Encola el evento para todas las suscripciones interesadas. Pensado para usarse
+// con TaskStore.Subscribe: no bloquea ni llama al store.
+func (d *Dispatcher) Publish(event taskstore.Event) {
+	d.Lock()
+	defer d.Unlock()
+
+	for _, sub := range d.subs {
+		if !sub.Matches(event.Type) {
+			continue
+		}
+
+		deliveryID := strconv.Itoa(d.nextDeliveryId)
+		d.nextDeliveryId++
+
+		body, err := json.Marshal(Payload{ID: deliveryID, Event: event})
+		if err != nil {
+			slog.Error("webhook: marshal payload", "event", event.Type, "err", err)
+			continue
+		}
+		d.enqueue(job{deliveryID: deliveryID, subID: sub.ID, event: event.Type, body: body, attempt: 1})
+	}
+}
+
+// Log de entregas de una suscripcion, las mas recientes primero
+func (d *Dispatcher) Deliveries(subscriptionID string) []Delivery {
+	d.Lock()
+	defer d.Unlock()
+
+	result := make([]Delivery, 0)
+	for i := len(d.deliveries) - 1; i >= 0; i-- {
+		if d.deliveries[i].SubscriptionID == subscriptionID {
+			result = append(result, d.deliveries[i])
+		}
+	}
+	return result
+}
+
+func (d *Dispatcher) DeadLetters() []DeadLetter {
+	d.Lock()
+	defer d.Unlock()
+
+	return append([]DeadLetter{}, d.deadLetters...)
+}
+
+// Vuelve a encolar una entrega fallida con el secreto y la url actuales de la suscripcion
+func (d *Dispatcher) RetryDeadLetter(id string) error {
+	d.Lock()
+	defer d.Unlock()
+
+	for i, dl := range d.deadLetters {
+		if dl.ID != id {
+			continue
+		}
+		if _, ok := d.subs[dl.SubscriptionID]; !ok {
+			return ErrNotFound
+		}
+		var payload Payload
+		_ = json.Unmarshal(dl.Body, &payload)
+
+		d.deadLetters = append(d.deadLetters[:i], d.deadLetters[i+1:]...)
+		d.enqueue(job{deliveryID: dl.ID, subID: dl.SubscriptionID, event: payload.Event.Type, body: dl.Body, attempt: 1})
+		return nil
+	}
+	return taskstore.NewError(taskstore.ErrNotFound, "dead letter "+id+" not found")
+}
+
+// Encola sin bloquear, si la cola esta llena la entrega va directo a dead letters. Requiere el lock.
+func (d *Dispatcher) enqueue(j job) {
+	if d.ctx.Err() != nil {
+		return
+	}
+	select {
+	case d.jobs <- j:
+	default:
+		d.deadLetter(j, "delivery queue full")
+	}
+}
+
+func (d *Dispatcher) work() {
+	d.alive.Add(1)
+	defer d.alive.Add(-1)
+	defer d.wg.Done()
+
+	for {
+		select {
+		case <-d.ctx.Done():
+			return
+		case j := <-d.jobs:
+			d.deliver(j)
+		}
+	}
+}
+
+// Hace un intento de entrega y programa el reintento si falla
+func (d *Dispatcher) deliver(j job) {
+	d.Lock()
+	sub, ok := d.subs[j.subID]
+	d.Unlock()
+	if !ok || !sub.Active {
+		slog.Debug("webhook: delivery dropped, subscription deleted or inactive", "delivery_id", j.deliveryID, "subscription_id", j.subID)
+		return
+	}
+
+	start := time.Now()
+	status, err := d.send(sub, j)
+
+	entry := Delivery{
+		ID:             j.deliveryID,
+		SubscriptionID: j.subID,
+		Event:          j.event,
+		Attempt:        j.attempt,
+		StatusCode:     status,
+		Duration:       time.Since(start),
+		Time:           start.UTC(),
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+
+	d.Lock()
+	defer d.Unlock()
+
+	d.deliveries = append(d.deliveries, entry)
+	if len(d.deliveries) > d.LogSize {
+		d.deliveries = d.deliveries[len(d.deliveries)-d.LogSize:]
+	}
+
+	if err == nil {
+		return
+	}
+	if j.attempt >= d.MaxAttempts {
+		d.deadLetter(j, err.Error())
+		return
+	}
+
+	next := j
+	next.attempt++
+	time.AfterFunc(d.backoff(j.attempt), func() {
+		d.Lock()
+		defer d.Unlock()
+		d.enqueue(next)
+	})
+}
+
+func (d *Dispatcher) send(sub Subscription, j job) (int, error) {
+	req, err := http.NewRequestWithContext(d.ctx, http.MethodPost, sub.URL, bytes.NewReader(j.body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, j.event)
+	req.Header.Set(HeaderDelivery, j.deliveryID)
+	req.Header.Set(HeaderSignature, Sign(sub.Secret, j.body))
+
+	res, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return res.StatusCode, fmt.Errorf("subscriber responded %d", res.StatusCode)
+	}
+	return res.StatusCode, nil
+}
+
+// Backoff exponencial con un 20% de jitter
+func (d *Dispatcher) backoff(attempt int) time.Duration {
+	wait := d.BaseBackoff << (attempt - 1)
+	if wait <= 0 || wait > d.MaxBackoff {
+		wait = d.MaxBackoff
+	}
+	jitter := time.Duration(rand.Int64N(int64(wait)/5 + 1))
+	return wait + jitter
+}
+
+// Requiere el lock
+func (d *Dispatcher) deadLetter(j job, reason string) {
+	d.deadLetters = append(d.deadLetters, DeadLetter{
+		ID:             j.deliveryID,
+		SubscriptionID: j.subID,
+		Body:           j.body,
+		Attempts:       j.attempt,
+		LastError:      reason,
+		Time:           time.Now().UTC(),
+	})
+	if len(d.deadLetters) > d.DeadLetterSize {
+		dropped := d.deadLetters[0]
+		d.deadLetters = d.deadLetters[len(d.deadLetters)-d.DeadLetterSize:]
+		slog.Warn("webhook: dead letter discarded", "delivery_id", dropped.ID, "subscription_id", dropped.SubscriptionID)
+	}
+	slog.Warn("webhook: delivery dead-lettered", "delivery_id", j.deliveryID, "subscription_id", j.subID, "reason", reason)
+}
+
+// Firma que acompaña a cada entrega en la cabecera X-Webhook-Signature
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
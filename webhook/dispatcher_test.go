@@ -0,0 +1,233 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"restServer/taskstore"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Receptor de entregas que responde los status de statuses en orden (el ultimo se repite)
+type receiver struct {
+	sync.Mutex
+	statuses []int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	rc.Lock()
+	defer rc.Unlock()
+	rc.requests = append(rc.requests, r)
+	rc.bodies = append(rc.bodies, body)
+	status := rc.statuses[min(len(rc.requests), len(rc.statuses))-1]
+	w.WriteHeader(status)
+}
+
+func (rc *receiver) count() int {
+	rc.Lock()
+	defer rc.Unlock()
+	return len(rc.requests)
+}
+
+func newReceiver(t *testing.T, statuses ...int) (*receiver, string) {
+	t.Helper()
+	rc := &receiver{statuses: statuses}
+	srv := httptest.NewServer(rc)
+	t.Cleanup(srv.Close)
+	return rc, srv.URL
+}
+
+// Dispatcher con backoff corto, detenido al terminar el test
+func newTestDispatcher(t *testing.T) *Dispatcher {
+	t.Helper()
+	d := NewDispatcher(1)
+	d.BaseBackoff = time.Millisecond
+	d.MaxBackoff = 5 * time.Millisecond
+	d.Start()
+	t.Cleanup(d.Stop)
+	return d
+}
+
+// Espera hasta que cond se cumpla o falla tras un segundo
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestSign(t *testing.T) {
+	// HMAC-SHA256("secret", "body")
+	const want = "sha256=dc46983557fea127b43af721467eb9b3fde2338fe3e14f51952aa8478c13d355"
+	if got := Sign("secret", []byte("body")); got != want {
+		t.Errorf("Sign() = %q, want %q", got, want)
+	}
+}
+
+func TestDelivery(t *testing.T) {
+	rc, url := newReceiver(t, http.StatusOK)
+	d := newTestDispatcher(t)
+	sub, _ := d.Create(url, "s3cret", []string{"task.*"})
+
+	d.Publish(taskstore.Event{Type: "project.created"})
+	d.Publish(taskstore.Event{Type: "task.created", Data: map[string]string{"id": "7"}})
+	waitFor(t, "delivery", func() bool { return len(d.Deliveries(sub.ID)) == 1 })
+
+	if rc.count() != 1 {
+		t.Fatalf("received %d deliveries, want 1", rc.count())
+	}
+	req, body := rc.requests[0], rc.bodies[0]
+	if got := req.Header.Get(HeaderSignature); got != Sign("s3cret", body) {
+		t.Errorf("%s = %q, want %q", HeaderSignature, got, Sign("s3cret", body))
+	}
+	if got := req.Header.Get(HeaderEvent); got != "task.created" {
+		t.Errorf("%s = %q", HeaderEvent, got)
+	}
+	var payload Payload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload.ID != req.Header.Get(HeaderDelivery) || payload.Event.Type != "task.created" {
+		t.Errorf("payload = %+v", payload)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	rc, url := newReceiver(t, http.StatusInternalServerError, http.StatusBadGateway, http.StatusOK)
+	d := newTestDispatcher(t)
+	sub, _ := d.Create(url, "", nil)
+
+	d.Publish(taskstore.Event{Type: "task.created"})
+	waitFor(t, "three attempts", func() bool { return len(d.Deliveries(sub.ID)) == 3 })
+
+	deliveries := d.Deliveries(sub.ID) // la mas reciente primero
+	for i, want := range []int{http.StatusOK, http.StatusBadGateway, http.StatusInternalServerError} {
+		if deliveries[i].StatusCode != want || deliveries[i].Attempt != 3-i {
+			t.Errorf("delivery %d = %+v, want status %d", i, deliveries[i], want)
+		}
+	}
+	if rc.count() != 3 || len(d.DeadLetters()) != 0 {
+		t.Errorf("received %d, dead letters %d", rc.count(), len(d.DeadLetters()))
+	}
+}
+
+func TestDeadLetters(t *testing.T) {
+	_, url := newReceiver(t, http.StatusInternalServerError)
+	d := newTestDispatcher(t)
+	d.MaxAttempts = 2
+	d.DeadLetterSize = 2
+	sub, _ := d.Create(url, "", nil)
+
+	for range 3 {
+		d.Publish(taskstore.Event{Type: "task.created"})
+	}
+	waitFor(t, "dead letters", func() bool { return len(d.Deliveries(sub.ID)) == 6 })
+
+	// Se conservan las 2 mas nuevas
+	dead := d.DeadLetters()
+	if len(dead) != 2 || dead[0].ID == dead[1].ID {
+		t.Fatalf("dead letters = %+v, want 2", dead)
+	}
+	for _, dl := range dead {
+		if dl.Attempts != 2 || dl.LastError != "subscriber responded 500" {
+			t.Errorf("dead letter = %+v", dl)
+		}
+	}
+
+	if err := d.RetryDeadLetter(dead[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.RetryDeadLetter(dead[0].ID); err == nil {
+		t.Error("retrying a dead letter twice succeeded")
+	}
+}
+
+// Los reintentos usan la suscripcion actual: la url nueva, o ninguno si se elimino
+func TestRetryUsesCurrentSubscription(t *testing.T) {
+	tests := []struct {
+		name       string
+		change     func(d *Dispatcher, id, url string)
+		wantMoved  int
+		wantFailed int
+	}{
+		{"updated url", func(d *Dispatcher, id, url string) { d.Update(id, url, "new", nil, true) }, 1, 1},
+		{"deactivated", func(d *Dispatcher, id, url string) { d.Update(id, url, "", nil, false) }, 0, 1},
+		{"deleted", func(d *Dispatcher, id, url string) { d.Delete(id) }, 0, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failing, oldURL := newReceiver(t, http.StatusInternalServerError)
+			moved, newURL := newReceiver(t, http.StatusOK)
+			d := newTestDispatcher(t)
+			d.BaseBackoff = 50 * time.Millisecond
+			d.MaxBackoff = 50 * time.Millisecond
+			sub, _ := d.Create(oldURL, "old", nil)
+
+			d.Publish(taskstore.Event{Type: "task.created"})
+			waitFor(t, "first attempt", func() bool { return len(d.Deliveries(sub.ID)) == 1 })
+			tt.change(d, sub.ID, newURL)
+			time.Sleep(150 * time.Millisecond)
+
+			if failing.count() != tt.wantFailed || moved.count() != tt.wantMoved {
+				t.Fatalf("old url got %d, new url got %d", failing.count(), moved.count())
+			}
+			if tt.wantMoved > 0 {
+				req, body := moved.requests[0], moved.bodies[0]
+				if req.Header.Get(HeaderSignature) != Sign("new", body) {
+					t.Error("retry is not signed with the current secret")
+				}
+			}
+			if len(d.DeadLetters()) != 0 {
+				t.Errorf("dead letters = %+v", d.DeadLetters())
+			}
+		})
+	}
+}
+
+func TestMatches(t *testing.T) {
+	tests := []struct {
+		name   string
+		sub    Subscription
+		event  string
+		wanted bool
+	}{
+		{"all events", Subscription{Active: true}, "task.created", true},
+		{"exact", Subscription{Active: true, Events: []string{"task.created"}}, "task.created", true},
+		{"prefix", Subscription{Active: true, Events: []string{"comment.*"}}, "comment.updated", true},
+		{"other prefix", Subscription{Active: true, Events: []string{"comment.*"}}, "task.created", false},
+		{"inactive", Subscription{Events: []string{"*"}}, "task.created", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.sub.Matches(tt.event); got != tt.wanted {
+				t.Errorf("Matches(%q) = %v, want %v", tt.event, got, tt.wanted)
+			}
+		})
+	}
+}
+
+func TestCreateValidatesURL(t *testing.T) {
+	d := NewDispatcher(0)
+	for _, url := range []string{"", "ftp://example.com", "/relative", "http://"} {
+		if _, err := d.Create(url, "", nil); err == nil {
+			t.Errorf("Create(%q) succeeded", url)
+		}
+	}
+	sub, err := d.Create("https://example.com/hook", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sub.Secret) != 64 || !sub.Active {
+		t.Errorf("Create() = %+v, want a generated secret", sub)
+	}
+}
@@ -0,0 +1,152 @@
+package webhook
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"restServer/taskstore"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotFound = taskstore.NewError(taskstore.ErrNotFound, "webhook not found")
+	ErrInvalid  = taskstore.NewError(taskstore.ErrValidation, "invalid webhook")
+)
+
+// Suscripcion a los eventos del store. Events vacio recibe todos los eventos,
+// tambien se admiten prefijos como "task.*".
+type Subscription struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"-"`
+	Events    []string  `json:"events"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Indica si la suscripcion recibe el tipo de evento
+func (s Subscription) Matches(eventType string) bool {
+	if !s.Active {
+		return false
+	}
+	if len(s.Events) == 0 {
+		return true
+	}
+	for _, e := range s.Events {
+		if e == eventType || e == "*" {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(e, "*"); ok && strings.HasPrefix(eventType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+//-------------------------------------------- CRUD de suscripciones ----------------------------------------//
+
+// Crea una suscripcion. Si secret esta vacio se genera uno aleatorio; la
+// suscripcion devuelta es la unica vez que se puede leer el secreto.
+func (d *Dispatcher) Create(rawURL, secret string, events []string) (Subscription, error) {
+	if err := validateURL(rawURL); err != nil {
+		return Subscription{}, err
+	}
+	if secret == "" {
+		secret = randomHex(32)
+	}
+
+	d.Lock()
+	defer d.Unlock()
+
+	sub := Subscription{
+		ID:        strconv.Itoa(d.nextId),
+		URL:       rawURL,
+		Secret:    secret,
+		Events:    append([]string{}, events...),
+		Active:    true,
+		CreatedAt: time.Now().UTC(),
+	}
+	d.subs[sub.ID] = sub
+	d.nextId++
+	return sub, nil
+}
+
+func (d *Dispatcher) Get(id string) (Subscription, error) {
+	d.Lock()
+	defer d.Unlock()
+
+	sub, ok := d.subs[id]
+	if !ok {
+		return Subscription{}, ErrNotFound
+	}
+	return sub, nil
+}
+
+// Todas las suscripciones ordenadas por Id
+func (d *Dispatcher) List() []Subscription {
+	d.Lock()
+	defer d.Unlock()
+
+	subs := make([]Subscription, 0, len(d.subs))
+	for _, sub := range d.subs {
+		subs = append(subs, sub)
+	}
+	sort.Slice(subs, func(i, j int) bool {
+		a, _ := strconv.Atoi(subs[i].ID)
+		b, _ := strconv.Atoi(subs[j].ID)
+		return a < b
+	})
+	return subs
+}
+
+// Reemplaza url, eventos y estado; un secret vacio conserva el actual
+func (d *Dispatcher) Update(id, rawURL, secret string, events []string, active bool) (Subscription, error) {
+	if err := validateURL(rawURL); err != nil {
+		return Subscription{}, err
+	}
+
+	d.Lock()
+	defer d.Unlock()
+
+	sub, ok := d.subs[id]
+	if !ok {
+		return Subscription{}, ErrNotFound
+	}
+	sub.URL = rawURL
+	sub.Events = append([]string{}, events...)
+	sub.Active = active
+	if secret != "" {
+		sub.Secret = secret
+	}
+	d.subs[id] = sub
+	return sub, nil
+}
+
+func (d *Dispatcher) Delete(id string) error {
+	d.Lock()
+	defer d.Unlock()
+
+	if _, ok := d.subs[id]; !ok {
+		return ErrNotFound
+	}
+	delete(d.subs, id)
+	return nil
+}
+
+func validateURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http(s) URL", ErrInvalid)
+	}
+	return nil
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}